@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/contract"
+	pkgclient "go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/utils"
+)
+
+// balanceResult 余额查询结果
+type balanceResult struct {
+	Address string `json:"address"`
+	Block   string `json:"block"`
+	Token   string `json:"token,omitempty"`
+	Symbol  string `json:"symbol"`
+	Raw     string `json:"raw"`
+	Balance string `json:"balance"`
+}
+
+func newBalanceCmd(opts *globalOptions) *cobra.Command {
+	var token, blockArg string
+
+	cmd := &cobra.Command{
+		Use:   "balance <address>",
+		Short: "查询 ETH 或 ERC20 代币余额",
+		Example: `  ethctl balance 0x742D35CC6634c0532925A3b844BC9E7595F0BEb0
+  ethctl balance 0x742D35CC6634c0532925A3b844BC9E7595F0BEb0 --block 18000000
+  ethctl balance 0x742D35CC6634c0532925A3b844BC9E7595F0BEb0 --token 0x... --block finalized`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			block, err := pkgclient.ParseBlockSelector(blockArg)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			result := balanceResult{Address: address.Hex(), Block: block.String(), Symbol: "ETH"}
+			if token == "" {
+				wei, err := pkgclient.BalanceAt(ctx, s.eth, address, block)
+				if err != nil {
+					return err
+				}
+				result.Raw = wei.String()
+				result.Balance = utils.FormatEther(wei)
+			} else if err := tokenBalance(ctx, s, token, address, block, &result); err != nil {
+				return err
+			}
+
+			return opts.out.object(result,
+				field{"地址", result.Address},
+				field{"区块", result.Block},
+				field{"余额", result.Balance + " " + result.Symbol},
+				field{"最小单位", result.Raw},
+			)
+		},
+	}
+	cmd.Flags().StringVar(&token, "token", "", "ERC20 合约地址")
+	cmd.Flags().StringVar(&blockArg, "block", "latest", "查询的区块：区块号、区块哈希或 latest|safe|finalized|pending")
+	return cmd
+}
+
+// tokenBalance 查询 ERC20 余额并按精度格式化
+func tokenBalance(ctx context.Context, s *session, token string, owner common.Address, block pkgclient.BlockSelector, result *balanceResult) error {
+	tokenAddr, err := parseAddress(token)
+	if err != nil {
+		return err
+	}
+	erc20, err := contract.NewERC20Contract(tokenAddr.Hex(), s.eth, nil)
+	if err != nil {
+		return err
+	}
+
+	meta, err := erc20.Metadata(ctx)
+	if err != nil {
+		return err
+	}
+	amount, err := erc20.BalanceOfAt(ctx, owner, block)
+	if err != nil {
+		return err
+	}
+
+	result.Token = tokenAddr.Hex()
+	result.Symbol = meta.Symbol
+	result.Raw = amount.String()
+	result.Balance = utils.FormatUnits(amount, meta.Decimals)
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+
+	pkgclient "go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/utils"
+)
+
+// blockResult 区块信息
+type blockResult struct {
+	Number       uint64     `json:"number"`
+	Hash         string     `json:"hash"`
+	ParentHash   string     `json:"parentHash"`
+	Time         time.Time  `json:"time"`
+	Miner        string     `json:"miner"`
+	GasUsed      uint64     `json:"gasUsed"`
+	GasLimit     uint64     `json:"gasLimit"`
+	BaseFee      string     `json:"baseFee,omitempty"`
+	TxCount      int        `json:"txCount"`
+	Transactions []txResult `json:"transactions,omitempty"`
+}
+
+// txResult 交易概要
+type txResult struct {
+	Hash  string `json:"hash"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+	Nonce uint64 `json:"nonce"`
+}
+
+func newBlockCmd(opts *globalOptions) *cobra.Command {
+	var withTxs bool
+
+	cmd := &cobra.Command{
+		Use:   "block [number|hash|latest|safe|finalized|pending]",
+		Short: "查询区块信息",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector := pkgclient.LatestBlock
+			if len(args) == 1 {
+				var err error
+				if selector, err = pkgclient.ParseBlockSelector(args[0]); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			var block *types.Block
+			if hash, ok := selector.Hash(); ok {
+				block, err = s.eth.BlockByHash(ctx, hash)
+			} else {
+				block, err = s.eth.BlockByNumber(ctx, selector.Number())
+			}
+			if err != nil {
+				return fmt.Errorf("获取区块失败: %w", err)
+			}
+
+			result := newBlockResult(block, withTxs)
+			if opts.output == "json" {
+				return opts.out.json(result)
+			}
+
+			fields := []field{
+				{"区块", result.Number},
+				{"哈希", result.Hash},
+				{"父哈希", result.ParentHash},
+				{"时间", result.Time.Format(time.RFC3339)},
+				{"出块者", result.Miner},
+				{"Gas", fmt.Sprintf("%d / %d", result.GasUsed, result.GasLimit)},
+				{"交易数", result.TxCount},
+			}
+			if result.BaseFee != "" {
+				fields = append(fields, field{"BaseFee", utils.FormatGwei(block.BaseFee()) + " gwei"})
+			}
+			if err := opts.out.object(result, fields...); err != nil {
+				return err
+			}
+
+			if withTxs {
+				fmt.Fprintln(cmd.OutOrStdout())
+				rows := make([][]string, 0, len(result.Transactions))
+				for _, tx := range result.Transactions {
+					rows = append(rows, []string{tx.Hash, tx.To, tx.Value})
+				}
+				return opts.out.table(result.Transactions, []string{"交易", "接收方", "金额 (ETH)"}, rows)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&withTxs, "txs", false, "列出区块内的交易")
+	return cmd
+}
+
+func newBlockResult(block *types.Block, withTxs bool) blockResult {
+	result := blockResult{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash().Hex(),
+		ParentHash: block.ParentHash().Hex(),
+		Time:       time.Unix(int64(block.Time()), 0),
+		Miner:      block.Coinbase().Hex(),
+		GasUsed:    block.GasUsed(),
+		GasLimit:   block.GasLimit(),
+		TxCount:    len(block.Transactions()),
+	}
+	if block.BaseFee() != nil {
+		result.BaseFee = block.BaseFee().String()
+	}
+	if withTxs {
+		for _, tx := range block.Transactions() {
+			result.Transactions = append(result.Transactions, newTxResult(tx))
+		}
+	}
+	return result
+}
+
+func newTxResult(tx *types.Transaction) txResult {
+	to := "(合约创建)"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	return txResult{
+		Hash:  tx.Hash().Hex(),
+		To:    to,
+		Value: utils.FormatEther(tx.Value()),
+		Nonce: tx.Nonce(),
+	}
+}
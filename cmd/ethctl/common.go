@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	pkgclient "go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+	"go-eth-learning/pkg/utils"
+	"go-eth-learning/pkg/wallet"
+)
+
+// session 一次命令执行期间的节点连接
+type session struct {
+	eth    pkgclient.Backend
+	client *pkgclient.Client
+}
+
+// dial 连接配置中的节点
+func (o *globalOptions) dial(ctx context.Context) (*session, error) {
+	dialOpts := pkgclient.DefaultDialOptions()
+	dialOpts.Multi.Quorum = o.quorum
+	dialOpts.Retry.RateLimit = pkgclient.RateLimit{RequestsPerSecond: o.rps, MaxConcurrent: o.maxConcurrent}
+
+	backend, err := pkgclient.DialBackend(ctx, o.cfg.NodeURLs(), dialOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pkgclient.NewFromBackend(ctx, backend)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	// 节点与所选网络不一致时，签名的交易会发到错误的链上
+	if want := o.cfg.ChainID; want != 0 && client.ChainID().Cmp(big.NewInt(want)) != 0 {
+		client.Close()
+		return nil, fmt.Errorf("节点的链 ID 为 %s，与网络 %s 的链 ID %d 不一致", client.ChainID(), o.cfg.Network, want)
+	}
+	return &session{eth: backend, client: client}, nil
+}
+
+// txManager 创建交易管理器
+func (s *session) txManager() *transaction.Manager {
+	return transaction.NewManager(s.eth, s.client.ChainID())
+}
+
+func (s *session) Close() {
+	s.client.Close()
+}
+
+// keystore 打开配置中的 keystore 目录
+func (o *globalOptions) keystore() (*wallet.KeyStore, error) {
+	return wallet.NewKeyStore(o.cfg.KeystoreDir, nil)
+}
+
+// signerOptions 发送交易的签名参数
+type signerOptions struct {
+	from         string
+	passwordFile string
+	signerURL    string
+}
+
+func (so *signerOptions) register(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&so.from, "from", "", "keystore 中的发送账户（不指定时使用 PRIVATE_KEY）")
+	flags.StringVar(&so.passwordFile, "password-file", "", "keystore 密码文件（不指定时交互输入）")
+	flags.StringVar(&so.signerURL, "signer-url", "", "Clef 等外部签名器地址，需配合 --from")
+	_ = cmd.MarkFlagFilename("password-file")
+}
+
+// signer 按参数选择签名器：外部签名器 > keystore 账户 > PRIVATE_KEY
+//
+// 返回的关闭函数释放外部签名器的连接，调用方用完签名器后需调用
+func (so *signerOptions) signer(ctx context.Context, o *globalOptions) (signer.Signer, func(), error) {
+	var from common.Address
+	if so.from != "" {
+		var err error
+		if from, err = parseAddress(so.from); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case so.signerURL != "":
+		if so.from == "" {
+			return nil, nil, fmt.Errorf("使用 --signer-url 时必须指定 --from")
+		}
+		remote, err := signer.NewRemoteSigner(ctx, so.signerURL, from)
+		if err != nil {
+			return nil, nil, err
+		}
+		return remote, remote.Close, nil
+
+	case so.from != "":
+		ks, err := o.keystore()
+		if err != nil {
+			return nil, nil, err
+		}
+		password, err := readPassword(so.passwordFile, "账户密码: ")
+		if err != nil {
+			return nil, nil, err
+		}
+		ksSigner, err := signer.NewKeystoreSigner(ks, from, password)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ksSigner, func() {}, nil
+
+	case o.cfg.PrivateKey != "":
+		pkSigner, err := signer.FromHex(o.cfg.PrivateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pkSigner, func() {}, nil
+
+	default:
+		return nil, nil, fmt.Errorf("未配置签名账户：请指定 --from 或设置环境变量 PRIVATE_KEY")
+	}
+}
+
+// readPassword 从文件读取密码，未指定文件时交互输入
+func readPassword(path, message string) (string, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("读取密码文件失败: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return promptSecret(message)
+}
+
+// readNewPassword 读取新密码，交互输入时需要确认
+func readNewPassword(path string) (string, error) {
+	if path != "" {
+		password, err := readPassword(path, "")
+		if err != nil {
+			return "", err
+		}
+		if password == "" {
+			return "", fmt.Errorf("密码文件 %s 为空", path)
+		}
+		return password, nil
+	}
+
+	password, err := promptSecret("设置密码: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("密码不能为空")
+	}
+	confirm, err := promptSecret("确认密码: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != password {
+		return "", fmt.Errorf("两次输入的密码不一致")
+	}
+	return password, nil
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// prompt 在 stderr 输出提示并读取一行输入，避免干扰 stdout 上的结果输出
+func prompt(message string) (string, error) {
+	fmt.Fprint(os.Stderr, message)
+	input, err := stdin.ReadString('\n')
+	if err != nil && input == "" {
+		return "", fmt.Errorf("读取输入失败: %w", err)
+	}
+	return strings.TrimSpace(input), nil
+}
+
+// promptSecret 读取密码、私钥等敏感输入，stdin 是终端时关闭回显
+func promptSecret(message string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return prompt(message)
+	}
+
+	fmt.Fprint(os.Stderr, message)
+	input, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("读取输入失败: %w", err)
+	}
+	return strings.TrimSpace(string(input)), nil
+}
+
+// parseAddress 解析十六进制地址，大小写混合时校验 EIP-55 校验和
+func parseAddress(s string) (common.Address, error) {
+	return utils.ParseAddress(s)
+}
+
+// parseHash 解析 32 字节交易哈希
+func parseHash(s string) (common.Hash, error) {
+	b, err := decodeHex(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("无效的交易哈希: %s", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// decodeHex 解析 0x 前缀可选的十六进制数据
+func decodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	return hexutil.Decode("0x" + s)
+}
+
+// parseEther 将十进制 ETH 数量精确转换为 wei，不接受负数
+func parseEther(s string) (*big.Int, error) {
+	wei, err := utils.ParseEther(s)
+	if err != nil {
+		return nil, err
+	}
+	if wei.Sign() < 0 {
+		return nil, fmt.Errorf("无效金额: %s", s)
+	}
+	return wei, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEther(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1", "1000000000000000000"},
+		{"0.1", "100000000000000000"},
+		{"1.000000000000000001", "1000000000000000001"},
+		{"0", "0"},
+	}
+	for _, tt := range tests {
+		got, err := parseEther(tt.in)
+		if err != nil {
+			t.Fatalf("parseEther(%s) 失败: %v", tt.in, err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("parseEther(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"-1", "abc", "0.0000000000000000001"} {
+		if _, err := parseEther(in); err == nil {
+			t.Errorf("parseEther(%s) 应该报错", in)
+		}
+	}
+}
+
+func TestReadNewPasswordFromFile(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readNewPassword(empty); err == nil {
+		t.Error("密码文件为空时应该报错")
+	}
+
+	path := filepath.Join(dir, "password")
+	if err := os.WriteFile(path, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readNewPassword(path); err != nil || got != "secret" {
+		t.Errorf("readNewPassword = %q, %v, want secret", got, err)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/contract"
+	pkgclient "go-eth-learning/pkg/ethclient"
+)
+
+func newContractCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contract",
+		Short: "按 ABI 调用、发送和部署合约",
+	}
+
+	cmd.AddCommand(
+		newContractCallCmd(opts),
+		newContractSendCmd(opts),
+		newContractDeployCmd(opts),
+		newContractPredictCmd(opts),
+	)
+	return cmd
+}
+
+// abiOptions 指定合约 ABI 的参数，未指定 --abi 时使用 --data 原始调用数据
+type abiOptions struct {
+	to       string
+	abiPath  string
+	name     string
+	data     string
+	artifact *contract.Artifact
+}
+
+func (ao *abiOptions) register(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&ao.to, "to", "", "合约地址")
+	flags.StringVar(&ao.abiPath, "abi", "", "ABI JSON 或 solc / Hardhat / Foundry 编译产物")
+	flags.StringVar(&ao.name, "contract", "", "编译产物包含多个合约时选择的合约名")
+	flags.StringVar(&ao.data, "data", "", "十六进制调用数据（不使用 --abi 时）")
+	_ = cmd.MarkFlagRequired("to")
+	_ = cmd.MarkFlagFilename("abi", "json", "abi")
+
+	// 补全 ABI 中的方法名
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 || ao.abiPath == "" {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		artifact, err := contract.LoadArtifact(ao.abiPath, ao.name)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		names := make([]string, 0, len(artifact.ABI.Methods))
+		for _, m := range artifact.ABI.Methods {
+			names = append(names, m.RawName+"\t"+m.Sig)
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// encode 解析地址并编码调用数据，返回的 method 在使用原始数据时为 nil
+func (ao *abiOptions) encode(args []string) (common.Address, *abi.Method, []byte, error) {
+	to, err := parseAddress(ao.to)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+
+	if ao.abiPath == "" {
+		if len(args) > 0 {
+			return common.Address{}, nil, nil, fmt.Errorf("指定方法名时需要 --abi")
+		}
+		data, err := decodeHex(ao.data)
+		return to, nil, data, err
+	}
+	if len(args) == 0 {
+		return common.Address{}, nil, nil, fmt.Errorf("请指定要调用的方法")
+	}
+
+	ao.artifact, err = contract.LoadArtifact(ao.abiPath, ao.name)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	method, err := contract.FindMethod(ao.artifact.ABI, args[0])
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	values, err := contract.ParseArgs(method.Inputs, args[1:])
+	if err != nil {
+		return common.Address{}, nil, nil, fmt.Errorf("%s: %w", method.Sig, err)
+	}
+
+	data, err := ao.artifact.ABI.Pack(method.Name, values...)
+	if err != nil {
+		return common.Address{}, nil, nil, fmt.Errorf("编码 %s 参数失败: %w", method.Sig, err)
+	}
+	return to, &method, data, nil
+}
+
+// outputValue 解码后的返回值
+type outputValue struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// callResult eth_call 结果，未提供 ABI 时只有原始数据
+type callResult struct {
+	To      string        `json:"to"`
+	Block   string        `json:"block"`
+	Method  string        `json:"method,omitempty"`
+	Raw     string        `json:"raw"`
+	Outputs []outputValue `json:"outputs,omitempty"`
+}
+
+// callAndPrint 在指定区块上执行 eth_call 并输出（可解码时输出解码后的返回值）
+func callAndPrint(ctx context.Context, opts *globalOptions, s *session, msg ethereum.CallMsg, method *abi.Method, block pkgclient.BlockSelector) error {
+	ret, err := s.client.CallContractAt(ctx, msg, block)
+	if err != nil {
+		return fmt.Errorf("调用合约失败: %w", err)
+	}
+
+	result := callResult{To: msg.To.Hex(), Block: block.String(), Raw: hexutil.Encode(ret)}
+	if method == nil {
+		return opts.out.object(result, field{"合约", result.To}, field{"区块", result.Block}, field{"返回", result.Raw})
+	}
+
+	result.Method = method.Sig
+	values, err := method.Outputs.Unpack(ret)
+	if err != nil {
+		return fmt.Errorf("解析 %s 返回值失败: %w", method.Sig, err)
+	}
+
+	rows := make([][]string, 0, len(values))
+	for i, v := range values {
+		out := method.Outputs[i]
+		name := out.Name
+		if name == "" {
+			name = fmt.Sprintf("[%d]", i)
+		}
+		result.Outputs = append(result.Outputs, outputValue{Name: name, Type: out.Type.String(), Value: contract.FormatValue(v)})
+		rows = append(rows, []string{name, out.Type.String(), contract.FormatString(v)})
+	}
+	return opts.out.table(result, []string{"名称", "类型", "值"}, rows)
+}
+
+func newContractCallCmd(opts *globalOptions) *cobra.Command {
+	var (
+		ao       abiOptions
+		from     string
+		blockArg string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "call <method> [args...]",
+		Short: "以 eth_call 调用合约方法并解码返回值（不发送交易）",
+		Long: `以 eth_call 调用合约方法并解码返回值（不发送交易）。
+
+参数按 ABI 类型转换：整数支持十进制、0x 十六进制和 1e18；bytes 使用十六进制；
+数组和元组使用 JSON，如 '[1,2,3]'、'{"to":"0x...","amount":1}'。
+重载方法需要使用完整签名，如 'safeTransferFrom(address,address,uint256)'。`,
+		Example: `  ethctl contract call --abi SimpleStorage.json --to 0x... get
+  ethctl contract call --abi erc20.abi --to 0x... balanceOf 0x742D35CC6634c0532925A3b844BC9E7595F0BEb0
+  ethctl contract call --abi erc20.abi --to 0x... totalSupply --block 18000000
+  ethctl contract call --to 0x... --data 0x06fdde03`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, method, data, err := ao.encode(args)
+			if err != nil {
+				return err
+			}
+			msg := ethereum.CallMsg{To: &to, Data: data}
+			if from != "" {
+				if msg.From, err = parseAddress(from); err != nil {
+					return err
+				}
+			}
+			block, err := pkgclient.ParseBlockSelector(blockArg)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			return callAndPrint(ctx, opts, s, msg, method, block)
+		},
+	}
+	ao.register(cmd)
+	cmd.Flags().StringVar(&from, "from", "", "调用者地址（可选）")
+	cmd.Flags().StringVar(&blockArg, "block", "latest", "调用所基于的区块：区块号、区块哈希或 latest|safe|finalized|pending")
+	return cmd
+}
+
+func newContractSendCmd(opts *globalOptions) *cobra.Command {
+	var (
+		ao    abiOptions
+		so    signerOptions
+		wo    waitOptions
+		value string
+		wait  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send <method> [args...]",
+		Short: "签名并发送合约交易，只读方法改为 eth_call",
+		Example: `  ethctl contract send --abi SimpleStorage.json --to 0x... --from 0x... set 42 --wait
+  ethctl contract send --to 0x... --data 0x60fe47b1000000000000000000000000000000000000000000000000000000000000002a`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, method, data, err := ao.encode(args)
+			if err != nil {
+				return err
+			}
+			amount, err := parseEther(value)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			from, closeSigner, err := so.signer(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer closeSigner()
+
+			if method != nil && method.IsConstant() {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s 是只读方法，改为 eth_call 执行\n", method.Sig)
+				return callAndPrint(ctx, opts, s, ethereum.CallMsg{From: from.Address(), To: &to, Data: data}, method, pkgclient.LatestBlock)
+			}
+			if method != nil && amount.Sign() > 0 && !method.IsPayable() {
+				return fmt.Errorf("%s 不是 payable 方法，不能附带 ETH", method.Sig)
+			}
+
+			tx, err := s.txManager().Send(ctx, from, &to, amount, data)
+			if err != nil {
+				return err
+			}
+			return printSent(ctx, opts, s, tx, wait, &wo)
+		},
+	}
+	ao.register(cmd)
+	so.register(cmd)
+	wo.register(cmd)
+	cmd.Flags().StringVar(&value, "value", "0", "附带的 ETH 数量（payable 方法）")
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待交易确认")
+	return cmd
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+)
+
+// deployResult 部署结果
+type deployResult struct {
+	Contract string `json:"contract"`
+	Address  string `json:"address"`
+	TxHash   string `json:"txHash"`
+	Block    uint64 `json:"block"`
+	GasUsed  uint64 `json:"gasUsed"`
+}
+
+func newContractDeployCmd(opts *globalOptions) *cobra.Command {
+	var (
+		so    signerOptions
+		wo    waitOptions
+		name  string
+		value string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "deploy <artifact> [constructor args...]",
+		Short: "部署 solc / Hardhat / Foundry 编译产物并等待回执",
+		Example: `  solc --combined-json abi,bin contracts/ERC20.sol > build/ERC20.json
+  ethctl contract deploy build/ERC20.json --contract MyToken --from 0x... 1000000`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			artifact, err := contract.LoadArtifact(args[0], name)
+			if err != nil {
+				return err
+			}
+			ctorArgs, err := contract.ParseConstructorArgs(artifact, args[1:])
+			if err != nil {
+				return fmt.Errorf("构造参数: %w", err)
+			}
+			amount, err := parseEther(value)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			from, closeSigner, err := so.signer(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer closeSigner()
+
+			deployer := contract.NewDeployer(s.txManager(), s.client)
+			deployer.WaitOptions = ethclient.DefaultWaitOptions()
+			deployer.WaitOptions.Confirmations = wo.confirmations
+			deployer.WaitOptions.Timeout = wo.timeout
+
+			deployment, err := deployer.DeployWithValue(ctx, from, artifact, amount, ctorArgs...)
+			if err != nil {
+				return err
+			}
+
+			result := deployResult{
+				Contract: artifact.Name,
+				Address:  deployment.Address.Hex(),
+				TxHash:   deployment.Tx.Hash().Hex(),
+				Block:    deployment.Receipt.BlockNumber.Uint64(),
+				GasUsed:  deployment.Receipt.GasUsed,
+			}
+			return opts.out.object(result,
+				field{"合约", result.Contract},
+				field{"地址", result.Address},
+				field{"交易", result.TxHash},
+				field{"区块", result.Block},
+				field{"Gas 消耗", result.GasUsed},
+			)
+		},
+	}
+	so.register(cmd)
+	wo.register(cmd)
+	cmd.Flags().StringVar(&name, "contract", "", "编译产物包含多个合约时选择的合约名")
+	cmd.Flags().StringVar(&value, "value", "0", "转入 payable 构造函数的 ETH 数量")
+	return cmd
+}
+
+// predictResult 预测的合约地址
+type predictResult struct {
+	Address string `json:"address"`
+	Method  string `json:"method"`
+}
+
+func newContractPredictCmd(opts *globalOptions) *cobra.Command {
+	var (
+		deployer string
+		nonce    int64
+		factory  string
+		salt     string
+		initCode string
+		artifact string
+		name     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "predict [constructor args...]",
+		Short: "预测 CREATE / CREATE2 部署的合约地址",
+		Example: `  ethctl contract predict --deployer 0x... --nonce 7
+  ethctl contract predict --factory 0x... --salt 0x01 --artifact build/ERC20.json --contract MyToken 1000000`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if factory == "" {
+				return predictCreate(cmd, opts, deployer, nonce)
+			}
+
+			factoryAddr, err := parseAddress(factory)
+			if err != nil {
+				return err
+			}
+			saltBytes, err := decodeHex(salt)
+			if err != nil || len(saltBytes) > 32 {
+				return fmt.Errorf("无效 salt: %s", salt)
+			}
+
+			code, err := decodeHex(initCode)
+			if err != nil {
+				return err
+			}
+			if artifact != "" {
+				a, err := contract.LoadArtifact(artifact, name)
+				if err != nil {
+					return err
+				}
+				ctorArgs, err := contract.ParseConstructorArgs(a, args)
+				if err != nil {
+					return fmt.Errorf("构造参数: %w", err)
+				}
+				if code, err = contract.InitCode(a, ctorArgs...); err != nil {
+					return err
+				}
+			}
+			if len(code) == 0 {
+				return fmt.Errorf("CREATE2 需要 --init-code 或 --artifact")
+			}
+
+			address := contract.Create2Address(factoryAddr, common.BytesToHash(saltBytes), code)
+			result := predictResult{Address: address.Hex(), Method: "CREATE2"}
+			return opts.out.object(result, field{"地址", result.Address}, field{"方式", result.Method})
+		},
+	}
+	cmd.Flags().StringVar(&deployer, "deployer", "", "CREATE：部署账户")
+	cmd.Flags().Int64Var(&nonce, "nonce", -1, "CREATE：部署交易的 nonce（默认取节点的 pending nonce）")
+	cmd.Flags().StringVar(&factory, "factory", "", "CREATE2：工厂合约地址")
+	cmd.Flags().StringVar(&salt, "salt", "0x", "CREATE2：salt（不足 32 字节左侧补 0）")
+	cmd.Flags().StringVar(&initCode, "init-code", "", "CREATE2：创建字节码（含构造参数）")
+	cmd.Flags().StringVar(&artifact, "artifact", "", "CREATE2：从编译产物和构造参数生成创建字节码")
+	cmd.Flags().StringVar(&name, "contract", "", "编译产物包含多个合约时选择的合约名")
+	return cmd
+}
+
+// predictCreate 预测 CREATE 地址，未指定 nonce 时从节点读取
+func predictCreate(cmd *cobra.Command, opts *globalOptions, deployer string, nonce int64) error {
+	deployerAddr, err := parseAddress(deployer)
+	if err != nil {
+		return fmt.Errorf("CREATE 需要 --deployer: %w", err)
+	}
+
+	if nonce < 0 {
+		ctx := cmd.Context()
+		s, err := opts.dial(ctx)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+
+		pending, err := s.eth.PendingNonceAt(ctx, deployerAddr)
+		if err != nil {
+			return fmt.Errorf("获取 nonce 失败: %w", err)
+		}
+		nonce = int64(pending)
+	}
+
+	address := contract.CreateAddress(deployerAddr, uint64(nonce))
+	result := predictResult{Address: address.Hex(), Method: fmt.Sprintf("CREATE (nonce %d)", nonce)}
+	return opts.out.object(result, field{"地址", result.Address}, field{"方式", result.Method})
+}
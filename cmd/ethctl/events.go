@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/indexer"
+	"go-eth-learning/pkg/reorg"
+	"go-eth-learning/pkg/sink"
+)
+
+func newEventsCmd(opts *globalOptions) *cobra.Command {
+	var (
+		addresses     []string
+		abiPaths      []string
+		eventNames    []string
+		fromBlock     uint64
+		toBlock       uint64
+		chunk         uint64
+		concurrency   int
+		checkpoint    string
+		follow        bool
+		interval      time.Duration
+		confirmations uint64
+		sinks         sinkFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "查询并解码合约事件，--follow 持续跟踪新区块",
+		Long: `查询并解码合约事件，--follow 持续跟踪新区块。
+
+内置 ERC20、ERC721 和 ERC1155 的事件定义，--abi 可加入其他合约的 ABI 或编译产物。
+--event 按事件名或签名过滤，不指定 --abi 和 --event 时只查询 Transfer 事件。
+没有匹配 ABI 的日志按原始 topic 和 data 输出。
+
+历史区块按 --chunk 分段并发查询，节点提示结果过多时自动缩小区块段。
+指定 --checkpoint 时每处理完一段就记录进度和区块哈希，中断后重新运行会从上次的位置继续；
+若上次处理到的区块在停机期间被重组替换，先输出被回滚的事件并把检查点退回共同祖先。
+
+跟踪新区块时按父哈希检查重组：被移出主链的事件会再输出一次并标记为已回滚
+（JSON 中 removed 为 true），检查点同时回退，随后输出新主链上的事件。
+
+--sink 把事件写入 JSONL 文件、SQLite 或 Webhook，可同时指定多个。每段事件全部写入成功后
+才保存检查点，进程中断后重新运行会再次投递最后一段，下游按 (blockHash, logIndex) 去重。`,
+		Example: `  ethctl events --address 0x... --from-block 18000000 --checkpoint usdt.json --follow
+  ethctl events --abi MyToken.json --address 0x... --event Approval
+  ethctl events --abi Vault.json --event "Deposit(address,uint256)" --event Withdraw
+  ethctl events --address 0x... --checkpoint usdt.json --follow --sink jsonl:usdt.jsonl --sink sqlite:usdt.db
+  ethctl events --address 0x... --follow --sink webhook:https://example.com/hook --webhook-secret ...`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := ethereum.FilterQuery{}
+			for _, a := range addresses {
+				addr, err := parseAddress(a)
+				if err != nil {
+					return err
+				}
+				query.Addresses = append(query.Addresses, addr)
+			}
+
+			decoder, err := contract.StandardEventDecoder()
+			if err != nil {
+				return err
+			}
+			for _, path := range abiPaths {
+				artifact, err := contract.LoadArtifact(path, "")
+				if err != nil {
+					return err
+				}
+				decoder.Add(artifact.ABI)
+			}
+			if len(eventNames) == 0 && len(abiPaths) == 0 {
+				eventNames = []string{"Transfer"}
+			}
+			topics, err := eventTopics(decoder, eventNames)
+			if err != nil {
+				return err
+			}
+			if len(topics) > 0 {
+				query.Topics = [][]common.Hash{topics}
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			latest, err := s.eth.BlockNumber(ctx)
+			if err != nil {
+				return err
+			}
+			end := latest
+			if toBlock > 0 && toBlock < latest {
+				end = toBlock
+			} else if confirmations > 0 {
+				if latest < confirmations {
+					return fmt.Errorf("最新区块 %d 不足 %d 个确认", latest, confirmations)
+				}
+				end = latest - confirmations
+			}
+
+			var store indexer.CheckpointStore = &indexer.MemoryCheckpoint{}
+			if checkpoint != "" {
+				store = indexer.NewFileCheckpoint(checkpoint)
+			}
+			ixOpts := indexer.DefaultOptions()
+			ixOpts.FromBlock = fromBlock
+			ixOpts.ChunkSize = chunk
+			ixOpts.Concurrency = concurrency
+
+			out, err := sinks.open(cmd, opts)
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+
+			// deliver 输出一个区块的事件并移动检查点，回滚时检查点退到父区块
+			deliver := func(ctx context.Context, ev reorg.Event) error {
+				number := ev.Header.Number.Uint64()
+				batch := sink.Batch{From: number, To: number, Events: sink.DecodeLogs(decoder, ev.Logs)}
+				if err := out.Write(ctx, batch); err != nil {
+					return err
+				}
+				cp := indexer.Checkpoint{Block: number, Hash: ev.Header.Hash()}
+				if ev.Type == reorg.BlockReverted {
+					cp = indexer.Checkpoint{Block: number - 1, Hash: ev.Header.ParentHash}
+				}
+				return store.Save(ctx, cp)
+			}
+			resumeOpts := reorg.DefaultOptions()
+			resumeOpts.Query = &query
+
+			// 上次处理到的区块可能在停机期间被重组替换，先回滚已输出的事件和检查点
+			if cp, ok, err := store.Load(ctx); err != nil {
+				return err
+			} else if ok && cp.Hash == (common.Hash{}) {
+				if confirmations == 0 {
+					return fmt.Errorf("检查点 %s 没有记录区块哈希，无法发现停机期间的重组；请指定 --confirmations 或删除检查点后重新索引", checkpoint)
+				}
+			} else if ok {
+				if _, err := reorg.Resume(ctx, s.eth, cp.Hash, deliver, resumeOpts); err != nil {
+					return err
+				}
+			}
+
+			if fromBlock == 0 && end > chunk {
+				// 未指定起始区块时只查询最近一个区间，避免从创世区块开始扫描；有检查点时以检查点为准
+				ixOpts.FromBlock = end - chunk + 1
+			}
+			ix := indexer.New(s.eth, query, store, sink.Handler(decoder, out), ixOpts)
+
+			if err := ix.Sync(ctx, end); err != nil || !follow || toBlock > 0 {
+				return err
+			}
+
+			// 回填完成后按区块哈希跟踪新区块，从检查点的区块哈希开始，回填与跟踪之间的重组同样会回滚
+			next, err := ix.Next(ctx)
+			if err != nil {
+				return err
+			}
+			trackerOpts := reorg.DefaultOptions()
+			trackerOpts.FromBlock = next
+			trackerOpts.Confirmations = confirmations
+			trackerOpts.PollInterval = interval
+			trackerOpts.Query = &query
+			trackerOpts.OnError = func(err error) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%v，稍后重试\n", err)
+			}
+			if cp, ok, err := ix.Checkpoint(ctx); err != nil {
+				return err
+			} else if ok {
+				trackerOpts.FromHash = cp.Hash
+			}
+			tracker := reorg.NewTracker(s.eth, deliver, trackerOpts)
+
+			if err := tracker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&addresses, "address", nil, "合约地址，可重复指定（不指定时查询所有合约）")
+	cmd.Flags().StringSliceVar(&abiPaths, "abi", nil, "ABI 或编译产物文件，可重复指定")
+	cmd.Flags().StringArrayVar(&eventNames, "event", nil, "只查询指定事件（事件名或签名），可重复指定")
+	cmd.Flags().Uint64Var(&fromBlock, "from-block", 0, "起始区块（默认最近 --chunk 个区块；有检查点时从检查点继续）")
+	cmd.Flags().Uint64Var(&toBlock, "to-block", 0, "结束区块（默认最新区块）")
+	cmd.Flags().Uint64Var(&chunk, "chunk", 2000, "每次 eth_getLogs 查询的区块数")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "回填历史区块时并发查询的区块段数")
+	cmd.Flags().StringVar(&checkpoint, "checkpoint", "", "检查点文件，记录已处理的区块以便中断后继续")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "持续跟踪新区块")
+	cmd.Flags().DurationVar(&interval, "interval", 12*time.Second, "跟踪时的轮询间隔")
+	sinks.register(cmd)
+	cmd.Flags().Uint64Var(&confirmations, "confirmations", 0, "只处理有多少个确认的区块，浅于该深度的重组不会出现在输出中")
+	return cmd
+}
+
+// eventTopics 把事件名或签名解析为签名 topic，匿名事件没有签名 topic，无法按事件过滤
+func eventTopics(decoder *contract.EventDecoder, names []string) ([]common.Hash, error) {
+	var topics []common.Hash
+	seen := make(map[common.Hash]bool)
+	for _, name := range names {
+		events := decoder.Lookup(name)
+		if len(events) == 0 {
+			return nil, fmt.Errorf("未找到事件 %s，请用 --abi 指定合约 ABI", name)
+		}
+		for _, event := range events {
+			if event.Anonymous {
+				return nil, fmt.Errorf("匿名事件 %s 没有签名 topic，无法按事件过滤", event.Sig)
+			}
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				topics = append(topics, event.ID)
+			}
+		}
+	}
+	return topics, nil
+}
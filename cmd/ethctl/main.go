@@ -0,0 +1,101 @@
+// cmd/ethctl 以太坊命令行工具：钱包、余额、交易、区块、合约、事件和监控
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/internal/config"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// globalOptions 全局参数
+type globalOptions struct {
+	rpcURL        string
+	network       string
+	output        string
+	configPath    string
+	quorum        int
+	rps           float64
+	maxConcurrent int
+
+	cfg *config.Config
+	out *printer
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "ethctl",
+		Short:        "⛓️🐹 以太坊命令行工具",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.load(cmd)
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.rpcURL, "rpc", "", "节点 RPC 地址，多个节点用逗号分隔（优先于 --network 和配置文件）")
+	flags.StringVar(&opts.network, "network", "", fmt.Sprintf("预置网络 %v", config.NetworkNames()))
+	flags.StringVarP(&opts.output, "output", "o", "table", "输出格式 table|json")
+	flags.StringVar(&opts.configPath, "config", "", "env 格式的配置文件（默认读取 ./.env）")
+	flags.IntVar(&opts.quorum, "quorum", 0, "余额、收据等关键读取需要结果一致的节点数（需配置多个节点）")
+	flags.Float64Var(&opts.rps, "rps", 0, "每个节点每秒请求数上限，0 表示不限制")
+	flags.IntVar(&opts.maxConcurrent, "max-concurrent", 0, "每个节点同时进行的请求数上限，0 表示不限制")
+
+	_ = cmd.RegisterFlagCompletionFunc("network", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return config.NetworkNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("output", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.MarkPersistentFlagFilename("config")
+
+	cmd.AddCommand(
+		newWalletCmd(opts),
+		newBalanceCmd(opts),
+		newTxCmd(opts),
+		newBlockCmd(opts),
+		newContractCmd(opts),
+		newEventsCmd(opts),
+		newMonitorCmd(opts),
+	)
+	return cmd
+}
+
+// load 加载配置并应用全局参数
+func (o *globalOptions) load(cmd *cobra.Command) error {
+	if o.output != "table" && o.output != "json" {
+		return fmt.Errorf("不支持的输出格式 %q，可选 table|json", o.output)
+	}
+	o.out = newPrinter(cmd.OutOrStdout(), o.output)
+
+	cfg, err := config.LoadFile(o.configPath)
+	if err != nil {
+		return err
+	}
+	if o.network != "" {
+		if err := cfg.UseNetwork(o.network); err != nil {
+			return err
+		}
+	}
+	if o.rpcURL != "" {
+		cfg.UseNodeURL(o.rpcURL)
+	}
+
+	o.cfg = cfg
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+
+	pkgclient "go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/reorg"
+	"go-eth-learning/pkg/sink"
+)
+
+func newMonitorCmd(opts *globalOptions) *cobra.Command {
+	var (
+		interval      time.Duration
+		withTxs       bool
+		confirmations uint64
+		pending       bool
+		sinks         sinkFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "持续输出新区块（可附带交易）",
+		Long: `持续输出新区块（可附带交易）。
+
+按父哈希跟踪主链，发生重组时先输出被回滚的区块，再输出替换它们的新区块。
+--confirmations 指定区块有多少个确认后才输出，浅于该深度的重组不会出现在输出中。
+
+通过 WebSocket 连接时订阅新区块，断线后自动重连；HTTP 连接按 --interval 轮询。
+
+--sink 把区块摘要写入 JSONL 文件、SQLite 或 Webhook，写入失败的区块在下一个区块到来时重新投递。`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			subOpts := pkgclient.DefaultSubscribeOptions()
+			subOpts.PollInterval = interval
+			subOpts.OnError = func(err error) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%v，正在重连\n", err)
+			}
+			if pending {
+				return monitorPending(ctx, cmd, opts, s, subOpts)
+			}
+
+			handler := func(ctx context.Context, ev reorg.Event) error {
+				if ev.Type == reorg.BlockReverted {
+					return printReverted(opts, ev.Header)
+				}
+				return printBlock(ctx, opts, s, ev.Header.Hash(), withTxs)
+			}
+			if len(sinks.specs) > 0 {
+				out, err := sinks.open(cmd, opts)
+				if err != nil {
+					return err
+				}
+				defer out.Close()
+				handler = func(ctx context.Context, ev reorg.Event) error {
+					return deliverBlock(ctx, s, out, ev)
+				}
+			}
+
+			trackerOpts := reorg.DefaultOptions()
+			trackerOpts.Confirmations = confirmations
+			tracker := reorg.NewTracker(s.eth, handler, trackerOpts)
+
+			// 订阅到的区块头只用于唤醒跟踪器，区块由跟踪器按父哈希依次输出
+			heads := make(chan *types.Header, 16)
+			sub, err := s.client.SubscribeNewHead(ctx, heads, subOpts)
+			if err != nil {
+				return err
+			}
+			defer sub.Unsubscribe()
+
+			// 第一次检查只记录起点，之后出现的区块才输出
+			if err := tracker.Poll(ctx); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.ErrOrStderr(), "开始监控，按 Ctrl+C 停止")
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case err := <-sub.Err():
+					return err
+				case <-heads:
+				}
+
+				// 失败的区块在下一个区块到来时重新输出
+				if err := tracker.Poll(ctx); err != nil {
+					if errors.Is(err, reorg.ErrReorgTooDeep) {
+						return err
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", err)
+				}
+			}
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 12*time.Second, "节点不支持订阅（HTTP）时的轮询间隔")
+	cmd.Flags().BoolVar(&withTxs, "txs", false, "同时输出区块内的交易")
+	cmd.Flags().Uint64Var(&confirmations, "confirmations", 0, "区块有多少个确认后才输出")
+	cmd.Flags().BoolVar(&pending, "pending", false, "改为输出进入交易池的交易哈希")
+	sinks.register(cmd)
+	return cmd
+}
+
+// pendingTx 进入交易池的交易
+type pendingTx struct {
+	Hash string `json:"hash"`
+}
+
+// monitorPending 流式输出进入交易池的交易哈希
+func monitorPending(ctx context.Context, cmd *cobra.Command, opts *globalOptions, s *session, subOpts *pkgclient.SubscribeOptions) error {
+	hashes := make(chan common.Hash, 256)
+	sub, err := s.client.SubscribePendingTransactions(ctx, hashes, subOpts)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	fmt.Fprintln(cmd.ErrOrStderr(), "开始监控交易池，按 Ctrl+C 停止")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-sub.Err():
+			return err
+		case hash := <-hashes:
+			if err := opts.out.stream(pendingTx{Hash: hash.Hex()}, []string{"⏳", hash.Hex()}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// revertedBlock 被重组移出主链的区块
+type revertedBlock struct {
+	Reverted bool   `json:"reverted"`
+	Number   uint64 `json:"number"`
+	Hash     string `json:"hash"`
+}
+
+// printReverted 流式输出一个被回滚的区块
+func printReverted(opts *globalOptions, header *types.Header) error {
+	result := revertedBlock{Reverted: true, Number: header.Number.Uint64(), Hash: header.Hash().Hex()}
+	return opts.out.stream(result, []string{
+		fmt.Sprintf("↩️  #%d", result.Number),
+		"已回滚",
+		result.Hash,
+	})
+}
+
+// printBlock 流式输出一个区块
+func printBlock(ctx context.Context, opts *globalOptions, s *session, hash common.Hash, withTxs bool) error {
+	block, err := s.eth.BlockByHash(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("获取区块 %s 失败: %w", hash.Hex(), err)
+	}
+
+	result := newBlockResult(block, withTxs)
+	if err := opts.out.stream(result, []string{
+		fmt.Sprintf("📦 #%d", result.Number),
+		result.Time.Format("15:04:05"),
+		fmt.Sprintf("交易: %d", result.TxCount),
+		result.Hash,
+	}); err != nil {
+		return err
+	}
+
+	if withTxs && opts.output != "json" {
+		for _, tx := range result.Transactions {
+			fmt.Fprintf(opts.out.w, "   💸 %s -> %s  %s ETH\n", tx.Hash, tx.To, tx.Value)
+		}
+	}
+	return nil
+}
+
+// deliverBlock 把区块摘要写入投递目标，被回滚的区块不再查询交易数
+func deliverBlock(ctx context.Context, s *session, out sink.Sink, ev reorg.Event) error {
+	number := ev.Header.Number.Uint64()
+	if ev.Type == reorg.BlockReverted {
+		return out.Write(ctx, sink.Batch{From: number, To: number, Blocks: []sink.Block{sink.NewBlock(ev.Header, 0, true)}})
+	}
+
+	count, err := s.eth.TransactionCount(ctx, ev.Header.Hash())
+	if err != nil {
+		return fmt.Errorf("获取区块 %d 的交易数失败: %w", number, err)
+	}
+	return out.Write(ctx, sink.Batch{From: number, To: number, Blocks: []sink.Block{sink.NewBlock(ev.Header, int(count), false)}})
+}
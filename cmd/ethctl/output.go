@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer 按 --output 输出结果：table 为对齐的文本表格，json 为缩进的 JSON
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, format: format}
+}
+
+// field 键值对输出的一行
+type field struct {
+	key   string
+	value interface{}
+}
+
+// object 输出单个对象：json 格式输出 v，table 格式逐行输出 fields
+func (p *printer) object(v interface{}, fields ...field) error {
+	if p.format == "json" {
+		return p.json(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(tw, "%s:\t%v\n", f.key, f.value)
+	}
+	return tw.Flush()
+}
+
+// table 输出列表：json 格式输出 v，table 格式输出表头和行
+func (p *printer) table(v interface{}, header []string, rows [][]string) error {
+	if p.format == "json" {
+		return p.json(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// stream 流式输出一条记录：json 格式每行一个对象，table 格式以制表符分隔
+func (p *printer) stream(v interface{}, row []string) error {
+	if p.format == "json" {
+		return json.NewEncoder(p.w).Encode(v)
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(row, "\t"))
+	return err
+}
+
+func (p *printer) json(v interface{}) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/sink"
+)
+
+// sinkFlags 投递目标参数，events 和 monitor 共用
+type sinkFlags struct {
+	specs         []string
+	webhookSecret string
+}
+
+func (f *sinkFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.specs, "sink", nil,
+		"投递目标，可重复指定：stdout、jsonl:<文件>、sqlite:<文件>、webhook:<URL>（默认 stdout）")
+	cmd.Flags().StringVar(&f.webhookSecret, "webhook-secret", os.Getenv("ETHCTL_WEBHOOK_SECRET"),
+		"Webhook 的 HMAC 签名密钥（默认读取 ETHCTL_WEBHOOK_SECRET）")
+}
+
+// open 打开全部投递目标，没有指定时输出到终端
+func (f *sinkFlags) open(cmd *cobra.Command, opts *globalOptions) (sink.Sink, error) {
+	specs := f.specs
+	if len(specs) == 0 {
+		specs = []string{"stdout"}
+	}
+
+	var sinks []sink.Sink
+	for _, spec := range specs {
+		s, err := f.openOne(cmd, opts, spec)
+		if err != nil {
+			sink.Multi(sinks...).Close()
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	return sink.Multi(sinks...), nil
+}
+
+func (f *sinkFlags) openOne(cmd *cobra.Command, opts *globalOptions, spec string) (sink.Sink, error) {
+	if spec == "stdout" {
+		return sink.NewStdout(opts.out.w, opts.output == "json"), nil
+	}
+	kind, target, ok := strings.Cut(spec, ":")
+	if !ok || target == "" {
+		return nil, fmt.Errorf("无效的投递目标 %q，格式为 stdout、jsonl:<文件>、sqlite:<文件> 或 webhook:<URL>", spec)
+	}
+
+	switch kind {
+	case "jsonl":
+		return sink.OpenJSONL(target, nil)
+	case "sqlite":
+		return sink.OpenSQLite(target)
+	case "webhook":
+		whOpts := sink.DefaultWebhookOptions()
+		whOpts.Secret = f.webhookSecret
+		whOpts.OnError = func(err error) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Webhook 投递失败: %v，稍后重试\n", err)
+		}
+		return sink.NewWebhook(target, whOpts), nil
+	}
+	return nil, fmt.Errorf("不支持的投递目标类型 %q", kind)
+}
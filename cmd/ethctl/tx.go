@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/transaction"
+)
+
+func newTxCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "发送、查询、加速和取消交易",
+	}
+
+	cmd.AddCommand(
+		newTxSendCmd(opts),
+		newTxStatusCmd(opts),
+		newTxSpeedUpCmd(opts),
+		newTxCancelCmd(opts),
+	)
+	return cmd
+}
+
+// sentTxResult 已发送交易
+type sentTxResult struct {
+	Hash   string      `json:"hash"`
+	Nonce  uint64      `json:"nonce"`
+	Status *waitResult `json:"status,omitempty"`
+}
+
+// waitResult 交易等待结果
+type waitResult struct {
+	Status        string `json:"status"`
+	BlockNumber   uint64 `json:"blockNumber,omitempty"`
+	GasUsed       uint64 `json:"gasUsed,omitempty"`
+	Confirmations uint64 `json:"confirmations"`
+	Reorgs        int    `json:"reorgs"`
+}
+
+// waitOptions --wait 相关参数
+type waitOptions struct {
+	confirmations uint64
+	timeout       time.Duration
+}
+
+func (w *waitOptions) register(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&w.confirmations, "confirmations", 1, "需要的确认数")
+	cmd.Flags().DurationVar(&w.timeout, "timeout", 5*time.Minute, "等待超时")
+}
+
+// wait 等待交易达到确认数
+func (w *waitOptions) wait(ctx context.Context, s *session, hash common.Hash) (*waitResult, error) {
+	waitOpts := ethclient.DefaultWaitOptions()
+	waitOpts.Confirmations = w.confirmations
+	waitOpts.Timeout = w.timeout
+
+	result, err := s.client.WaitMined(ctx, hash.Hex(), waitOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &waitResult{
+		Status:        result.Status.String(),
+		Confirmations: result.Confirmations,
+		Reorgs:        result.Reorgs,
+	}
+	if result.Receipt != nil {
+		out.BlockNumber = result.Receipt.BlockNumber.Uint64()
+		out.GasUsed = result.Receipt.GasUsed
+	}
+	return out, nil
+}
+
+func (r *waitResult) fields() []field {
+	fields := []field{{"状态", r.Status}}
+	if r.BlockNumber > 0 {
+		fields = append(fields,
+			field{"区块", r.BlockNumber},
+			field{"Gas 消耗", r.GasUsed},
+			field{"确认数", r.Confirmations},
+		)
+	}
+	if r.Reorgs > 0 {
+		fields = append(fields, field{"重组次数", r.Reorgs})
+	}
+	return fields
+}
+
+// printSent 输出已发送的交易，需要时等待确认
+func printSent(ctx context.Context, opts *globalOptions, s *session, tx *types.Transaction, wait bool, w *waitOptions) error {
+	result := sentTxResult{Hash: tx.Hash().Hex(), Nonce: tx.Nonce()}
+	fields := []field{{"交易", result.Hash}, {"Nonce", result.Nonce}}
+
+	if wait {
+		status, err := w.wait(ctx, s, tx.Hash())
+		if err != nil {
+			return err
+		}
+		result.Status = status
+		fields = append(fields, status.fields()...)
+	}
+	return opts.out.object(result, fields...)
+}
+
+func newTxSendCmd(opts *globalOptions) *cobra.Command {
+	var (
+		so    signerOptions
+		wo    waitOptions
+		to    string
+		value string
+		data  string
+		wait  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "发送 ETH 或附带数据的交易",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toAddr, err := parseAddress(to)
+			if err != nil {
+				return err
+			}
+			amount, err := parseEther(value)
+			if err != nil {
+				return err
+			}
+			calldata, err := decodeHex(data)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			from, closeSigner, err := so.signer(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer closeSigner()
+			tx, err := s.txManager().Send(ctx, from, &toAddr, amount, calldata)
+			if err != nil {
+				return err
+			}
+			return printSent(ctx, opts, s, tx, wait, &wo)
+		},
+	}
+	so.register(cmd)
+	wo.register(cmd)
+	cmd.Flags().StringVar(&to, "to", "", "接收地址")
+	cmd.Flags().StringVar(&value, "value", "0", "转账金额 (ETH)")
+	cmd.Flags().StringVar(&data, "data", "", "附带的十六进制数据")
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待交易确认")
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+func newTxStatusCmd(opts *globalOptions) *cobra.Command {
+	var wo waitOptions
+
+	cmd := &cobra.Command{
+		Use:   "status <txHash>",
+		Short: "等待交易达到确认数，并报告成功、回滚、丢弃、被替换或超时",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hash, err := parseHash(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			result, err := wo.wait(ctx, s, hash)
+			if err != nil {
+				return err
+			}
+			return opts.out.object(result, append([]field{{"交易", hash.Hex()}}, result.fields()...)...)
+		},
+	}
+	wo.register(cmd)
+	return cmd
+}
+
+func newTxSpeedUpCmd(opts *globalOptions) *cobra.Command {
+	var (
+		so   signerOptions
+		wo   waitOptions
+		bump int
+		wait bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "speedup <txHash>",
+		Short: "以相同 nonce 和更高费用重发卡住的交易",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hash, err := parseHash(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			from, closeSigner, err := so.signer(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer closeSigner()
+			tx, err := s.txManager().SpeedUp(ctx, from, hash, bump)
+			if err != nil {
+				return err
+			}
+			return printSent(ctx, opts, s, tx, wait, &wo)
+		},
+	}
+	so.register(cmd)
+	wo.register(cmd)
+	cmd.Flags().IntVar(&bump, "bump", transaction.MinReplacementBump, "费用涨幅百分比")
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待交易确认")
+	return cmd
+}
+
+func newTxCancelCmd(opts *globalOptions) *cobra.Command {
+	var (
+		so   signerOptions
+		wo   waitOptions
+		wait bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel <txHash>",
+		Short: "以同 nonce 的 0 值自转账取消卡住的交易",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hash, err := parseHash(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			s, err := opts.dial(ctx)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			from, closeSigner, err := so.signer(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer closeSigner()
+			tx, err := s.txManager().Cancel(ctx, from, hash)
+			if err != nil {
+				return err
+			}
+			return printSent(ctx, opts, s, tx, wait, &wo)
+		},
+	}
+	so.register(cmd)
+	wo.register(cmd)
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待交易确认")
+	return cmd
+}
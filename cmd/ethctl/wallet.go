@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go-eth-learning/pkg/wallet"
+)
+
+func newWalletCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wallet",
+		Short: "管理 keystore 账户和助记词",
+	}
+
+	cmd.AddCommand(
+		newWalletCreateCmd(opts),
+		newWalletImportCmd(opts),
+		newWalletImportKeystoreCmd(opts),
+		newWalletListCmd(opts),
+		newWalletExportCmd(opts),
+		newWalletDeleteCmd(opts),
+		newWalletDeriveCmd(opts),
+	)
+	return cmd
+}
+
+// accountResult 单个账户的输出
+type accountResult struct {
+	Address string `json:"address"`
+	Path    string `json:"path,omitempty"`
+}
+
+func newWalletCreateCmd(opts *globalOptions) *cobra.Command {
+	var passwordFile string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "创建新账户并加密保存到 keystore",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+			password, err := readNewPassword(passwordFile)
+			if err != nil {
+				return err
+			}
+
+			address, err := ks.NewAccount(password)
+			if err != nil {
+				return err
+			}
+			return opts.out.object(accountResult{Address: address.Hex()},
+				field{"地址", address.Hex()},
+				field{"keystore", ks.Dir()},
+			)
+		},
+	}
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "新账户密码文件（不指定时交互输入）")
+	return cmd
+}
+
+func newWalletImportCmd(opts *globalOptions) *cobra.Command {
+	var passwordFile string
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "导入私钥到 keystore（私钥从标准输入读取）",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+
+			privateKey, err := promptSecret("请输入私钥 (hex, 0x 可选): ")
+			if err != nil {
+				return err
+			}
+			w, err := wallet.FromPrivateKey(strings.TrimPrefix(privateKey, "0x"))
+			if err != nil {
+				return err
+			}
+			password, err := readNewPassword(passwordFile)
+			if err != nil {
+				return err
+			}
+
+			address, err := ks.ImportWallet(w, password)
+			if err != nil {
+				return err
+			}
+			return opts.out.object(accountResult{Address: address.Hex()}, field{"地址", address.Hex()})
+		},
+	}
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "新账户密码文件（不指定时交互输入）")
+	return cmd
+}
+
+func newWalletImportKeystoreCmd(opts *globalOptions) *cobra.Command {
+	var passwordFile, newPasswordFile string
+
+	cmd := &cobra.Command{
+		Use:   "import-keystore <file>",
+		Short: "导入 keystore 文件",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+
+			keyJSON, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("读取文件失败: %w", err)
+			}
+			password, err := readPassword(passwordFile, "原密码: ")
+			if err != nil {
+				return err
+			}
+			newPassword, err := readNewPassword(newPasswordFile)
+			if err != nil {
+				return err
+			}
+
+			address, err := ks.Import(keyJSON, password, newPassword)
+			if err != nil {
+				return err
+			}
+			return opts.out.object(accountResult{Address: address.Hex()}, field{"地址", address.Hex()})
+		},
+	}
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "原密码文件")
+	cmd.Flags().StringVar(&newPasswordFile, "new-password-file", "", "新密码文件")
+	return cmd
+}
+
+func newWalletListCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "列出 keystore 中的账户",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+
+			accounts := ks.Accounts()
+			results := make([]accountResult, 0, len(accounts))
+			rows := make([][]string, 0, len(accounts))
+			for i, address := range accounts {
+				results = append(results, accountResult{Address: address.Hex()})
+				rows = append(rows, []string{fmt.Sprint(i + 1), address.Hex()})
+			}
+			return opts.out.table(results, []string{"#", "地址"}, rows)
+		},
+	}
+}
+
+func newWalletExportCmd(opts *globalOptions) *cobra.Command {
+	var passwordFile, newPasswordFile string
+
+	cmd := &cobra.Command{
+		Use:   "export <address> <file>",
+		Short: "以新密码导出 keystore 文件",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+			password, err := readPassword(passwordFile, "账户密码: ")
+			if err != nil {
+				return err
+			}
+			newPassword, err := readNewPassword(newPasswordFile)
+			if err != nil {
+				return err
+			}
+
+			keyJSON, err := ks.Export(address, password, newPassword)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[1], keyJSON, 0600); err != nil {
+				return fmt.Errorf("写入文件失败: %w", err)
+			}
+			return opts.out.object(accountResult{Address: address.Hex()},
+				field{"地址", address.Hex()},
+				field{"文件", args[1]},
+			)
+		},
+	}
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "账户密码文件")
+	cmd.Flags().StringVar(&newPasswordFile, "new-password-file", "", "导出文件的密码文件")
+	return cmd
+}
+
+func newWalletDeleteCmd(opts *globalOptions) *cobra.Command {
+	var passwordFile string
+
+	cmd := &cobra.Command{
+		Use:   "delete <address>",
+		Short: "从 keystore 删除账户",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			address, err := parseAddress(args[0])
+			if err != nil {
+				return err
+			}
+			ks, err := opts.keystore()
+			if err != nil {
+				return err
+			}
+			password, err := readPassword(passwordFile, "账户密码: ")
+			if err != nil {
+				return err
+			}
+
+			if err := ks.Delete(address, password); err != nil {
+				return err
+			}
+			return opts.out.object(accountResult{Address: address.Hex()}, field{"已删除", address.Hex()})
+		},
+	}
+	cmd.Flags().StringVar(&passwordFile, "password-file", "", "账户密码文件")
+	return cmd
+}
+
+func newWalletDeriveCmd(opts *globalOptions) *cobra.Command {
+	var (
+		mnemonic   string
+		passphrase string
+		start      uint32
+		count      uint32
+	)
+
+	cmd := &cobra.Command{
+		Use:   "derive",
+		Short: "从助记词派生地址（不指定助记词时生成新的）",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if mnemonic == "" {
+				generated, err := wallet.NewMnemonic(128, wallet.English)
+				if err != nil {
+					return err
+				}
+				mnemonic = generated
+				fmt.Fprintf(os.Stderr, "助记词: %s\n⚠️  请离线抄写保存助记词，不要泄露!\n", mnemonic)
+			}
+			if err := wallet.ValidateMnemonic(mnemonic); err != nil {
+				return err
+			}
+
+			hd, err := wallet.NewHDWalletFromMnemonic(mnemonic, passphrase)
+			if err != nil {
+				return err
+			}
+			wallets, err := hd.DeriveRange(start, count)
+			if err != nil {
+				return err
+			}
+
+			results := make([]accountResult, 0, len(wallets))
+			rows := make([][]string, 0, len(wallets))
+			for i, w := range wallets {
+				path := hd.PathAt(start + uint32(i)).String()
+				results = append(results, accountResult{Address: w.GetAddressHex(), Path: path})
+				rows = append(rows, []string{path, w.GetAddressHex()})
+			}
+			return opts.out.table(results, []string{"路径", "地址"}, rows)
+		},
+	}
+	cmd.Flags().StringVar(&mnemonic, "mnemonic", "", "BIP-39 助记词")
+	cmd.Flags().StringVar(&passphrase, "passphrase", "", "BIP-39 密码（可选）")
+	cmd.Flags().Uint32Var(&start, "start", 0, "起始索引")
+	cmd.Flags().Uint32Var(&count, "count", 5, "派生数量")
+	return cmd
+}
@@ -49,21 +49,25 @@ func main() {
 	if err != nil {
 		log.Printf("查询余额失败: %v", err)
 	} else {
-		fmt.Printf("余额: %f ETH\\n", balance)
+		fmt.Printf("余额: %s ETH\\n", utils.FormatEther(balance))
 	}
 
 	// 3. 交易管理器示例
 	fmt.Println("\\n=== 交易管理器 ===")
-	txManager := transaction.NewManager(client, client.ChainID())
+	txManager := transaction.NewManager(client.Backend(), client.ChainID())
 	_ = txManager
 
 	// 4. 工具函数示例
 	fmt.Println("\\n=== 工具函数 ===")
-	wei := utils.EtherToWei(1.5)
+	wei, err := utils.ParseEther("1.5")
+	if err != nil {
+		log.Fatalf("换算失败: %v", err)
+	}
 	fmt.Printf("1.5 ETH = %s Wei\\n", wei.String())
+	fmt.Printf("转回 ETH: %s\\n", utils.FormatEther(wei))
 
-	ether := utils.WeiToEther(wei)
-	fmt.Printf("转回 ETH: %f\\n", ether)
+	gasPrice, _ := utils.ParseGwei("30")
+	fmt.Printf("30 Gwei = %s Wei\\n", gasPrice.String())
 
 	// 验证地址
 	valid := utils.IsValidAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
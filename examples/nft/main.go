@@ -2,46 +2,122 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"go-eth-learning/internal/config"
+	"go-eth-learning/pkg/contract"
 )
 
 func main() {
-	fmt.Println("🖼️ NFT 操作示例\\n")
+	fmt.Print("🖼️ NFT 操作示例\n\n")
 
-	_, err := config.Load()
+	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("加载配置失败: %v", err)
 	}
 
-	fmt.Println("=== NFT 标准 (ERC721) ===")
-	fmt.Println()
-	fmt.Println("主要方法:")
-	fmt.Println("  - balanceOf(address): 查询持有数量")
-	fmt.Println("  - ownerOf(tokenId): 查询 NFT 所有者")
-	fmt.Println("  - transferFrom(from, to, tokenId): 转移 NFT")
-	fmt.Println("  - approve(to, tokenId): 授权转移")
-	fmt.Println("  - setApprovalForAll(operator, approved): 批量授权")
-	fmt.Println()
-	fmt.Println("元数据:")
-	fmt.Println("  - tokenURI(tokenId): 获取 NFT 元数据链接")
-	fmt.Println("  - name(): 集合名称")
-	fmt.Println("  - symbol(): 集合符号")
-	fmt.Println()
-	fmt.Println("=== NFT 标准 (ERC1155) ===")
-	fmt.Println()
-	fmt.Println("多代币标准，一个合约支持多种 NFT")
-	fmt.Println("  - balanceOf(address, tokenId): 查询特定代币余额")
-	fmt.Println("  - balanceOfBatch: 批量查询")
-	fmt.Println("  - safeTransferFrom: 安全转移")
-	fmt.Println()
-	fmt.Println("=== 流行 NFT 合约 ===")
-	fmt.Println("  - CryptoPunks: 0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB")
-	fmt.Println("  - BAYC: 0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
-	fmt.Println("  - Azuki: 0xED5AF388653567Af2F388E6224dC7C4b3241C544")
-	fmt.Println()
-	fmt.Println("✅ NFT 示例完成!")
-	fmt.Println("提示: 使用 abigen 生成完整合约绑定后可实现完整交互")
+	client, err := ethclient.Dial(cfg.EthNodeURL)
+	if err != nil {
+		log.Fatalf("连接失败: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	// Ethereum Mainnet 上的 NFT 合约
+	collections := []string{
+		"0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", // BAYC (ERC721)
+		"0x76BE3b62873462d2142405439777e971754E8E77", // Parallel Alpha (ERC1155)
+	}
+	tokenID := big.NewInt(1)
+
+	for _, address := range collections {
+		standard, err := contract.DetectNFTStandard(ctx, client, common.HexToAddress(address))
+		if err != nil {
+			log.Printf("检测合约标准失败: %v", err)
+			continue
+		}
+		fmt.Printf("=== %s (%s) ===\n", address, standard)
+
+		switch standard {
+		case contract.NFTERC721:
+			showERC721(ctx, client, address, tokenID)
+		case contract.NFTERC1155:
+			showERC1155(ctx, client, address, tokenID)
+		default:
+			fmt.Println("未实现 ERC721 或 ERC1155")
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("=== 转移说明 ===")
+	fmt.Println("1. 创建合约实例时传入 transaction.Manager 即可发送交易")
+	fmt.Println("2. SafeTransferFrom / SafeBatchTransferFrom 会先模拟执行，接收方不支持时提前报错")
+	fmt.Println("3. ParseTransfer / ParseTransferSingle / ParseTransferBatch 解码转移事件")
+
+	fmt.Println("\n✅ NFT 示例完成!")
+}
+
+// showERC721 显示 ERC721 集合信息
+func showERC721(ctx context.Context, client *ethclient.Client, address string, tokenID *big.Int) {
+	nft, err := contract.NewERC721Contract(address, client, nil)
+	if err != nil {
+		log.Printf("创建合约实例失败: %v", err)
+		return
+	}
+
+	if name, err := nft.Name(ctx); err == nil {
+		fmt.Printf("名称: %s\n", name)
+	}
+	if symbol, err := nft.Symbol(ctx); err == nil {
+		fmt.Printf("符号: %s\n", symbol)
+	}
+	if total, err := nft.TotalSupply(ctx); err == nil {
+		fmt.Printf("总量: %s\n", total)
+	} else {
+		fmt.Printf("总量: 不可用 (%v)\n", err)
+	}
+
+	owner, err := nft.OwnerOf(ctx, tokenID)
+	if err != nil {
+		log.Printf("查询所有者失败: %v", err)
+		return
+	}
+	fmt.Printf("#%s 所有者: %s\n", tokenID, owner.Hex())
+
+	if uri, err := nft.TokenURI(ctx, tokenID); err == nil {
+		fmt.Printf("#%s 元数据: %s\n", tokenID, uri)
+	}
+}
+
+// showERC1155 显示 ERC1155 集合信息
+func showERC1155(ctx context.Context, client *ethclient.Client, address string, tokenID *big.Int) {
+	multi, err := contract.NewERC1155Contract(address, client, nil)
+	if err != nil {
+		log.Printf("创建合约实例失败: %v", err)
+		return
+	}
+
+	uri, err := multi.URI(ctx, tokenID)
+	if err != nil {
+		log.Printf("查询元数据链接失败: %v", err)
+		return
+	}
+	fmt.Printf("#%s 元数据: %s\n", tokenID, uri)
+
+	holder := common.HexToAddress("0x742D35CC6634c0532925A3b844BC9E7595F0BEb0")
+	balances, err := multi.BalanceOfBatch(ctx,
+		[]common.Address{holder, holder},
+		[]*big.Int{tokenID, new(big.Int).Add(tokenID, big.NewInt(1))},
+	)
+	if err != nil {
+		log.Printf("批量查询余额失败: %v", err)
+		return
+	}
+	fmt.Printf("%s 持有 #%s: %s, #%s: %s\n", holder.Hex(), tokenID, balances[0], new(big.Int).Add(tokenID, big.NewInt(1)), balances[1])
 }
@@ -5,16 +5,16 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go-eth-learning/internal/config"
 	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/utils"
 )
 
 func main() {
-	fmt.Println("🪙 ERC20 代币操作示例\\n")
+	fmt.Print("🪙 ERC20 代币操作示例\n\n")
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -33,38 +33,50 @@ func main() {
 	usdtAddress := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
 
 	fmt.Println("=== 查询 USDT 代币信息 ===")
-	fmt.Printf("合约地址: %s\\n\\n", usdtAddress)
+	fmt.Printf("合约地址: %s\n\n", usdtAddress)
 
-	// 创建合约实例
-	token, err := contract.NewERC20Contract(usdtAddress)
+	// 创建只读合约实例（需要转账时传入 transaction.Manager）
+	token, err := contract.NewERC20Contract(usdtAddress, client, nil)
 	if err != nil {
 		log.Fatalf("创建合约实例失败: %v", err)
 	}
 
-	// 查询代币信息
-	fmt.Printf("代币地址: %s\\n", token.Address.Hex())
+	meta, err := token.Metadata(ctx)
+	if err != nil {
+		log.Fatalf("查询代币信息失败: %v", err)
+	}
+	fmt.Printf("名称: %s\n", meta.Name)
+	fmt.Printf("符号: %s\n", meta.Symbol)
+	fmt.Printf("精度: %d\n", meta.Decimals)
+
+	totalSupply, err := token.TotalSupply(ctx)
+	if err != nil {
+		log.Fatalf("查询总发行量失败: %v", err)
+	}
+	fmt.Printf("总发行量: %s %s\n", utils.FormatUnits(totalSupply, meta.Decimals), meta.Symbol)
 
 	// 查询余额示例
-	walletAddress := "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb"
-	fmt.Printf("\\n查询地址余额: %s\\n", walletAddress)
+	walletAddress := common.HexToAddress("0x742D35CC6634c0532925A3b844BC9E7595F0BEb0")
+	fmt.Printf("\n查询地址余额: %s\n", walletAddress.Hex())
+
+	balance, err := token.BalanceOf(ctx, walletAddress)
+	if err != nil {
+		log.Printf("查询代币余额失败: %v", err)
+	} else {
+		fmt.Printf("%s 余额: %s\n", meta.Symbol, utils.FormatUnits(balance, meta.Decimals))
+	}
 
-	// 使用 ethclient 查询 ETH 余额
-	balance, err := client.BalanceAt(ctx, common.HexToAddress(walletAddress), nil)
+	ethBalance, err := client.BalanceAt(ctx, walletAddress, nil)
 	if err != nil {
 		log.Printf("查询 ETH 余额失败: %v", err)
 	} else {
-		ethBalance := new(big.Float).Quo(
-			new(big.Float).SetInt(balance),
-			big.NewFloat(1e18),
-		)
-		fmt.Printf("ETH 余额: %f\\n", ethBalance)
+		fmt.Printf("ETH 余额: %s\n", utils.FormatEther(ethBalance))
 	}
 
-	fmt.Println("\\n=== 代币操作说明 ===")
-	fmt.Println("1. 查询代币余额需要调用合约的 balanceOf 方法")
-	fmt.Println("2. 转账需要调用 transfer 方法并签名交易")
-	fmt.Println("3. 授权需要调用 approve 方法")
-	fmt.Println("4. 实际交互需要完整的合约绑定代码（可用 abigen 生成）")
+	fmt.Println("\n=== 代币操作说明 ===")
+	fmt.Println("1. BalanceOf / Allowance 通过 eth_call 读取，无需签名")
+	fmt.Println("2. Transfer / Approve / TransferFrom 先模拟执行，再经 transaction.Manager 签名发送")
+	fmt.Println("3. USDT 等不返回 bool 的代币同样支持")
 
-	fmt.Println("\\n✅ ERC20 示例完成!")
+	fmt.Println("\n✅ ERC20 示例完成!")
 }
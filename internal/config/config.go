@@ -2,7 +2,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -10,29 +13,111 @@ import (
 // Config 应用配置
 type Config struct {
 	// 以太坊节点配置
-	EthNodeURL string
-	ChainID    int64
+	Network    string
+	EthNodeURL string // 多个节点用逗号分隔
+	ChainID    int64  // 期望的链 ID，连接时与节点的 eth_chainId 比较，0 表示不校验
+
+	networkSet bool // 是否显式选择了网络
 
 	// 钱包配置
-	PrivateKey string
+	PrivateKey  string
+	KeystoreDir string
 
 	// 合约地址
 	ContractAddresses map[string]string
 }
 
+// Network 预置网络
+type Network struct {
+	Name    string
+	ChainID int64
+	RPCURL  string
+}
+
+// Networks 预置网络，RPC 为无需 API Key 的公共节点，只适合开发调试
+var Networks = map[string]Network{
+	"mainnet": {Name: "mainnet", ChainID: 1, RPCURL: "https://ethereum-rpc.publicnode.com"},
+	"sepolia": {Name: "sepolia", ChainID: 11155111, RPCURL: "https://ethereum-sepolia-rpc.publicnode.com"},
+	"holesky": {Name: "holesky", ChainID: 17000, RPCURL: "https://ethereum-holesky-rpc.publicnode.com"},
+	"local":   {Name: "local", ChainID: 1337, RPCURL: "http://127.0.0.1:8545"},
+}
+
+// NetworkNames 返回预置网络名称（已排序）
+func NetworkNames() []string {
+	names := make([]string, 0, len(Networks))
+	for name := range Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Load 从环境变量加载配置
 func Load() (*Config, error) {
-	// 加载 .env 文件（如果存在）
-	_ = godotenv.Load()
+	return LoadFile("")
+}
+
+// LoadFile 从指定的 env 文件和环境变量加载配置，path 为空时读取当前目录的 .env（如果存在）
+//
+// 已设置的环境变量优先于文件中的值
+func LoadFile(path string) (*Config, error) {
+	if path == "" {
+		_ = godotenv.Load()
+	} else if err := godotenv.Load(path); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
 
-	return &Config{
-		EthNodeURL: getEnv("ETH_NODE_URL", "https://sepolia.infura.io/v3/YOUR_KEY"),
-		ChainID:    11155111, // Sepolia
-		PrivateKey: getEnv("PRIVATE_KEY", ""),
+	cfg := &Config{
+		Network:     getEnv("ETH_NETWORK", "sepolia"),
+		PrivateKey:  getEnv("PRIVATE_KEY", ""),
+		KeystoreDir: getEnv("KEYSTORE_DIR", "./keystore"),
 		ContractAddresses: map[string]string{
 			"USDT": "0xdAC17F958D2ee523a2206206994597C13D831ec7",
 		},
-	}, nil
+	}
+	if err := cfg.UseNetwork(cfg.Network); err != nil {
+		return nil, err
+	}
+	cfg.networkSet = os.Getenv("ETH_NETWORK") != ""
+
+	// 显式配置的节点地址优先于预置网络
+	if url := os.Getenv("ETH_NODE_URL"); url != "" {
+		cfg.UseNodeURL(url)
+	}
+	return cfg, nil
+}
+
+// UseNetwork 切换到预置网络
+func (c *Config) UseNetwork(name string) error {
+	network, ok := Networks[name]
+	if !ok {
+		return fmt.Errorf("未知网络 %q，可选: %v", name, NetworkNames())
+	}
+
+	c.Network = network.Name
+	c.EthNodeURL = network.RPCURL
+	c.ChainID = network.ChainID
+	c.networkSet = true
+	return nil
+}
+
+// UseNodeURL 使用自定义节点，未显式选择网络时节点可能属于任意链，不再校验链 ID
+func (c *Config) UseNodeURL(url string) {
+	c.EthNodeURL = url
+	if !c.networkSet {
+		c.ChainID = 0
+	}
+}
+
+// NodeURLs 返回节点地址列表
+func (c *Config) NodeURLs() []string {
+	var urls []string
+	for _, u := range strings.Split(c.EthNodeURL, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
 }
 
 func getEnv(key, defaultValue string) string {
@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+
 	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
 	"go-eth-learning/pkg/transaction"
+	"go-eth-learning/pkg/utils"
 	"go-eth-learning/pkg/wallet"
 )
 
@@ -21,11 +25,45 @@ func NewAccountService(client *ethclient.Client) *AccountService {
 	return &AccountService{client: client}
 }
 
-// GetBalance 获取账户余额
-func (s *AccountService) GetBalance(ctx context.Context, address string) (*big.Float, error) {
+// GetBalance 获取账户余额（wei）
+func (s *AccountService) GetBalance(ctx context.Context, address string) (*big.Int, error) {
 	return s.client.GetBalance(ctx, address)
 }
 
+// AccountBalance 批量查询余额的单条结果
+type AccountBalance struct {
+	Address string
+	Balance *big.Int // wei
+	Err     error
+}
+
+// GetBalances 批量查询账户余额（wei），无效地址和查询失败记录在对应结果的 Err 中
+func (s *AccountService) GetBalances(ctx context.Context, addresses []string) ([]AccountBalance, error) {
+	results := make([]AccountBalance, len(addresses))
+	valid := make([]common.Address, 0, len(addresses))
+	index := make([]int, 0, len(addresses))
+	for i, addr := range addresses {
+		results[i].Address = addr
+		parsed, err := utils.ParseAddress(addr)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		valid = append(valid, parsed)
+		index = append(index, i)
+	}
+
+	balances, err := s.client.GetBalances(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+	for j, b := range balances {
+		r := &results[index[j]]
+		r.Balance, r.Err = b.Balance, b.Err
+	}
+	return results, nil
+}
+
 // CreateWallet 创建新钱包
 func (s *AccountService) CreateWallet() (*wallet.Wallet, error) {
 	return wallet.NewWallet()
@@ -46,26 +84,47 @@ type TransactionService struct {
 func NewTransactionService(client *ethclient.Client) *TransactionService {
 	return &TransactionService{
 		client: client,
-		txMgr:  transaction.NewManager(client, client.ChainID()),
+		txMgr:  transaction.NewManager(client.Backend(), client.ChainID()),
 	}
 }
 
 // SendETH 发送 ETH
-func (s *TransactionService) SendETH(ctx context.Context, privateKey, to string, amount *big.Int) (string, error) {
-	txHash, err := s.txMgr.Transfer(ctx, privateKey, to, amount)
+func (s *TransactionService) SendETH(ctx context.Context, from signer.Signer, to string, amount *big.Int) (string, error) {
+	txHash, err := s.txMgr.Transfer(ctx, from, to, amount)
 	if err != nil {
 		return "", fmt.Errorf("发送 ETH 失败: %w", err)
 	}
 	return txHash, nil
 }
 
-// GetTransactionStatus 获取交易状态
-func (s *TransactionService) GetTransactionStatus(ctx context.Context, txHash string) (bool, error) {
-	receipt, err := s.client.WaitMined(ctx, txHash)
+// SpeedUpTransaction 提高费用重发卡住的交易，返回新交易哈希
+func (s *TransactionService) SpeedUpTransaction(ctx context.Context, from signer.Signer, txHash string, bumpPercent int) (string, error) {
+	tx, err := s.txMgr.SpeedUp(ctx, from, common.HexToHash(txHash), bumpPercent)
+	if err != nil {
+		return "", fmt.Errorf("加速交易失败: %w", err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// CancelTransaction 以 0 值自转账取消卡住的交易，返回新交易哈希
+func (s *TransactionService) CancelTransaction(ctx context.Context, from signer.Signer, txHash string) (string, error) {
+	tx, err := s.txMgr.Cancel(ctx, from, common.HexToHash(txHash))
+	if err != nil {
+		return "", fmt.Errorf("取消交易失败: %w", err)
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// GetTransactionStatus 等待交易达到指定确认数，返回成功、回滚、丢弃、被替换或超时
+func (s *TransactionService) GetTransactionStatus(ctx context.Context, txHash string, confirmations uint64) (*ethclient.WaitResult, error) {
+	opts := ethclient.DefaultWaitOptions()
+	opts.Confirmations = confirmations
+
+	result, err := s.client.WaitMined(ctx, txHash, opts)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("等待交易失败: %w", err)
 	}
-	return receipt.Status == 1, nil
+	return result, nil
 }
 
 // BlockService 区块服务
@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/service"
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/utils"
+)
+
+func TestTransactionService_SendETH(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	txs := service.NewTransactionService(chain.Client())
+	txHash, err := txs.SendETH(ctx, alice.Signer, bob.Address.Hex(), big.NewInt(params.Ether))
+	if err != nil {
+		t.Fatalf("发送 ETH 失败: %v", err)
+	}
+
+	result, err := txs.GetTransactionStatus(ctx, txHash, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ethclient.TxSuccess {
+		t.Errorf("交易状态 = %s, want success", result.Status)
+	}
+
+	balance, err := service.NewAccountService(chain.Client()).GetBalance(ctx, bob.Address.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := utils.FormatEther(balance); got != "10001" {
+		t.Errorf("余额 = %s ETH, want 10001", got)
+	}
+}
+
+func TestAccountService_GetBalances(t *testing.T) {
+	chain := testchain.New(t, nil)
+
+	addresses := []string{chain.Accounts[0].Address.Hex(), "0x1234", chain.Accounts[1].Address.Hex()}
+	results, err := service.NewAccountService(chain.Client()).GetBalances(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if i == 1 {
+			if r.Err == nil {
+				t.Errorf("无效地址应返回错误")
+			}
+			continue
+		}
+		if r.Err != nil || utils.FormatEther(r.Balance) != "10000" {
+			t.Errorf("余额[%d] = %v, err = %v", i, r.Balance, r.Err)
+		}
+	}
+}
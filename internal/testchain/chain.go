@@ -0,0 +1,393 @@
+// Package testchain 提供进程内的模拟以太坊链，用于离线、确定性的测试
+//
+// 链由 go-ethereum 的 SimulatedBackend 驱动，并通过进程内 JSON-RPC 服务暴露为标准的
+// *ethclient.Client，因此 pkg/ethclient、transaction.Manager 和合约绑定无需修改即可使用，
+// 包括收据、日志过滤和 newHeads / logs 订阅。
+package testchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	gethclient "github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+	"go-eth-learning/pkg/wallet"
+)
+
+// Mnemonic 预置账户使用的助记词（与 Hardhat / Anvil 默认账户相同）
+const Mnemonic = "test test test test test test test test test test test junk"
+
+// Options 模拟链参数
+type Options struct {
+	// Accounts 预置账户数量
+	Accounts int
+	// Balance 每个预置账户的初始余额（wei）
+	Balance *big.Int
+	// GasLimit 区块 gas 上限
+	GasLimit uint64
+	// ManualCommit 为 true 时交易停留在待打包区块中，需要调用 Commit 出块；默认每笔交易立即出块
+	ManualCommit bool
+}
+
+// DefaultOptions 默认参数：10 个账户，每个 10000 ETH，交易立即出块
+func DefaultOptions() *Options {
+	return &Options{
+		Accounts: 10,
+		Balance:  new(big.Int).Mul(big.NewInt(10000), big.NewInt(params.Ether)),
+		GasLimit: 30_000_000,
+	}
+}
+
+// Account 预置账户
+type Account struct {
+	Address common.Address
+	Wallet  *wallet.Wallet
+	Signer  *signer.PrivateKeySigner
+}
+
+// Chain 进程内模拟链
+type Chain struct {
+	Accounts []*Account
+
+	t      testing.TB
+	db     ethdb.Database
+	sim    *backends.SimulatedBackend
+	server *rpc.Server
+	rpc    *rpc.Client
+	eth    *gethclient.Client
+	client *ethclient.Client
+	txMgr  *transaction.Manager
+
+	mu         sync.Mutex // 串行化交易提交和出块
+	autoCommit bool
+	pending    []*types.Transaction // 待打包区块中的交易，用于同 nonce 替换
+	txFeed     event.Feed           // 进入交易池的交易哈希，供 newPendingTransactions 订阅
+}
+
+// New 启动模拟链，测试结束时自动关闭；opts 为 nil 时使用 DefaultOptions
+func New(t testing.TB, opts *Options) *Chain {
+	t.Helper()
+
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	c, err := newChain(t, opts)
+	if err != nil {
+		t.Fatalf("启动模拟链失败: %v", err)
+	}
+	t.Cleanup(c.close)
+	return c
+}
+
+func newChain(t testing.TB, opts *Options) (*Chain, error) {
+	accounts, err := deriveAccounts(opts.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	alloc := make(core.GenesisAlloc)
+	for _, a := range accounts {
+		alloc[a.Address] = core.GenesisAccount{Balance: new(big.Int).Set(opts.Balance)}
+	}
+
+	c := &Chain{
+		Accounts:   accounts,
+		t:          t,
+		db:         rawdb.NewMemoryDatabase(),
+		server:     rpc.NewServer(),
+		autoCommit: !opts.ManualCommit,
+	}
+	c.sim = backends.NewSimulatedBackendWithDatabase(c.db, alloc, opts.GasLimit)
+
+	if err := c.server.RegisterName("eth", &ethAPI{chain: c}); err != nil {
+		return nil, fmt.Errorf("注册 RPC 服务失败: %w", err)
+	}
+	if err := c.server.RegisterName("net", &netAPI{chain: c}); err != nil {
+		return nil, fmt.Errorf("注册 RPC 服务失败: %w", err)
+	}
+
+	c.rpc = rpc.DialInProc(c.server)
+	c.eth = gethclient.NewClient(c.rpc)
+	if c.client, err = ethclient.NewFromClient(context.Background(), c.eth); err != nil {
+		c.close()
+		return nil, err
+	}
+	c.txMgr = transaction.NewManager(c.eth, c.ChainID())
+	return c, nil
+}
+
+// deriveAccounts 按 BIP-44 路径 m/44'/60'/0'/0/i 派生预置账户
+func deriveAccounts(count int) ([]*Account, error) {
+	hd, err := wallet.NewHDWalletFromMnemonic(Mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := hd.DeriveRange(0, uint32(count))
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*Account, len(wallets))
+	for i, w := range wallets {
+		accounts[i] = &Account{Address: w.Address, Wallet: w, Signer: signer.FromWallet(w)}
+	}
+	return accounts, nil
+}
+
+func (c *Chain) close() {
+	c.rpc.Close()
+	c.server.Stop()
+	c.sim.Close()
+}
+
+// ChainID 返回链 ID（1337）
+func (c *Chain) ChainID() *big.Int {
+	return c.sim.Blockchain().Config().ChainID
+}
+
+// Client 返回连接到模拟链的 pkg/ethclient.Client
+func (c *Chain) Client() *ethclient.Client {
+	return c.client
+}
+
+// Eth 返回连接到模拟链的 go-ethereum ethclient
+func (c *Chain) Eth() *gethclient.Client {
+	return c.eth
+}
+
+// RPC 返回进程内 RPC 客户端，可调用已实现的任意 JSON-RPC 方法
+func (c *Chain) RPC() *rpc.Client {
+	return c.rpc
+}
+
+// Backend 返回底层的 SimulatedBackend
+func (c *Chain) Backend() *backends.SimulatedBackend {
+	return c.sim
+}
+
+// TxManager 返回使用模拟链的交易管理器，所有调用共享同一个 nonce 管理器
+func (c *Chain) TxManager() *transaction.Manager {
+	return c.txMgr
+}
+
+// SetAutoCommit 切换出块模式，返回之前的设置
+func (c *Chain) SetAutoCommit(auto bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := c.autoCommit
+	c.autoCommit = auto
+	return prev
+}
+
+// Commit 将待打包的交易打包成新区块
+func (c *Chain) Commit() *types.Block {
+	c.t.Helper()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	block, err := c.commit()
+	if err != nil {
+		c.t.Fatalf("出块失败: %v", err)
+	}
+	return block
+}
+
+// commit 出块，调用方需持有锁
+func (c *Chain) commit() (*types.Block, error) {
+	hash := c.sim.Commit()
+	c.pending = nil
+	return c.sim.BlockByHash(context.Background(), hash)
+}
+
+// Mine 连续出 n 个空块
+func (c *Chain) Mine(n int) {
+	c.t.Helper()
+
+	for i := 0; i < n; i++ {
+		c.Commit()
+	}
+}
+
+// Rollback 丢弃所有待打包的交易，模拟交易被节点丢弃
+func (c *Chain) Rollback() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sim.Rollback()
+	c.pending = nil
+}
+
+// Fork 从 parent 区块开始构建分叉链，分叉链长于当前主链后触发重组
+func (c *Chain) Fork(parent common.Hash) {
+	c.t.Helper()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.sim.Fork(context.Background(), parent); err != nil {
+		c.t.Fatalf("创建分叉失败: %v", err)
+	}
+	c.pending = nil
+}
+
+// AdjustTime 使下一个区块的时间戳额外前进 d，只能在没有待打包交易时调用
+func (c *Chain) AdjustTime(d time.Duration) {
+	c.t.Helper()
+
+	if err := c.sim.AdjustTime(d); err != nil {
+		c.t.Fatalf("调整时间失败: %v", err)
+	}
+}
+
+// Fund 从第一个预置账户向 to 转账 amount wei，并等待交易上链
+func (c *Chain) Fund(to common.Address, amount *big.Int) {
+	c.t.Helper()
+
+	tx, err := c.txMgr.Send(context.Background(), c.Accounts[0].Signer, &to, amount, nil)
+	if err != nil {
+		c.t.Fatalf("转账失败: %v", err)
+	}
+	c.MustMine(tx)
+}
+
+// MustMine 确保交易成功上链并返回收据，手动出块模式下先出块
+func (c *Chain) MustMine(tx *types.Transaction) *types.Receipt {
+	c.t.Helper()
+
+	c.mu.Lock()
+	manual := !c.autoCommit
+	c.mu.Unlock()
+	if manual {
+		c.Commit()
+	}
+
+	receipt, err := c.sim.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		c.t.Fatalf("获取交易收据失败: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		c.t.Fatalf("交易执行失败: %s", tx.Hash().Hex())
+	}
+	return receipt
+}
+
+// submit 模拟交易池接收交易：校验 nonce，支持同 nonce 替换，自动出块模式下立即打包
+func (c *Chain) submit(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from, err := types.Sender(types.LatestSignerForChainID(c.ChainID()), tx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", txpool.ErrInvalidSender, err)
+	}
+	for i, p := range c.pending {
+		if p.Hash() == tx.Hash() {
+			return txpool.ErrAlreadyKnown
+		}
+		if p.Nonce() == tx.Nonce() && c.sender(p) == from {
+			if err := c.replace(ctx, i, tx); err != nil {
+				return err
+			}
+			c.txFeed.Send(tx.Hash())
+			return nil
+		}
+	}
+
+	// 使用与 geth 交易池相同的错误，便于 nonce 管理器识别
+	next, err := c.sim.PendingNonceAt(ctx, from)
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() < next {
+		return fmt.Errorf("%w: next nonce %d, tx nonce %d", core.ErrNonceTooLow, next, tx.Nonce())
+	}
+	if tx.Nonce() > next {
+		return fmt.Errorf("%w: next nonce %d, tx nonce %d", core.ErrNonceTooHigh, next, tx.Nonce())
+	}
+
+	if err := c.include(ctx, tx); err != nil {
+		return err
+	}
+	c.txFeed.Send(tx.Hash())
+	if c.autoCommit {
+		_, err = c.commit()
+	}
+	return err
+}
+
+// replace 用新交易替换待打包区块中同 nonce 的交易，费用涨幅不足时拒绝
+func (c *Chain) replace(ctx context.Context, index int, tx *types.Transaction) error {
+	old := c.pending[index]
+	if !bumped(old.GasTipCap(), tx.GasTipCap()) || !bumped(old.GasFeeCap(), tx.GasFeeCap()) {
+		return txpool.ErrReplaceUnderpriced
+	}
+
+	original := c.pending
+	replaced := append([]*types.Transaction{}, original...)
+	replaced[index] = tx
+	if err := c.rebuild(ctx, replaced, tx); err != nil {
+		c.rebuild(ctx, original, nil)
+		return err
+	}
+	return nil
+}
+
+// rebuild 重新生成待打包区块；替换后无法执行的其他交易被丢弃，must 执行失败时返回错误
+func (c *Chain) rebuild(ctx context.Context, txs []*types.Transaction, must *types.Transaction) error {
+	c.sim.Rollback()
+	c.pending = nil
+	for _, tx := range txs {
+		if err := c.include(ctx, tx); err != nil && tx == must {
+			return err
+		}
+	}
+	return nil
+}
+
+// include 将交易加入待打包区块
+//
+// SimulatedBackend 遇到余额不足等无法执行的交易时会 panic（此时内部状态尚未修改），这里转换为错误返回
+func (c *Chain) include(ctx context.Context, tx *types.Transaction) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if err := c.sim.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.pending = append(c.pending, tx)
+	return nil
+}
+
+// sender 返回待打包交易的发送者（已校验过签名）
+func (c *Chain) sender(tx *types.Transaction) common.Address {
+	from, _ := types.Sender(types.LatestSignerForChainID(c.ChainID()), tx)
+	return from
+}
+
+// bumped 判断费用涨幅是否达到交易池的替换阈值
+func bumped(prev, next *big.Int) bool {
+	threshold := new(big.Int).Mul(prev, big.NewInt(100+transaction.MinReplacementBump))
+	return threshold.Cmp(new(big.Int).Mul(next, big.NewInt(100))) <= 0
+}
@@ -0,0 +1,190 @@
+package testchain_test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+)
+
+func TestChain_InstantCommit(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	if alice.Address != common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266") {
+		t.Fatalf("预置账户地址 = %s", alice.Address.Hex())
+	}
+
+	value := big.NewInt(params.Ether)
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &bob.Address, value, nil)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	// 自动出块模式下发送返回时交易已经上链
+	receipt, err := chain.Eth().TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatalf("获取交易收据失败: %v", err)
+	}
+	if receipt.BlockNumber.Uint64() != 1 {
+		t.Errorf("区块号 = %d, want 1", receipt.BlockNumber)
+	}
+
+	balance, err := chain.Eth().BalanceAt(ctx, bob.Address, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Add(testchain.DefaultOptions().Balance, value)
+	if balance.Cmp(want) != 0 {
+		t.Errorf("余额 = %s, want %s", balance, want)
+	}
+}
+
+func TestChain_ManualCommit(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	chain.AdjustTime(time.Hour)
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &bob.Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	if _, err := chain.Eth().TransactionReceipt(ctx, tx.Hash()); err != ethereum.NotFound {
+		t.Fatalf("出块前不应有收据: %v", err)
+	}
+
+	done := make(chan *ethclient.WaitResult, 1)
+	go func() {
+		result, err := chain.Client().WaitMined(ctx, tx.Hash().Hex(), &ethclient.WaitOptions{PollInterval: 10 * time.Millisecond})
+		if err != nil {
+			t.Errorf("等待交易失败: %v", err)
+		}
+		done <- result
+	}()
+
+	block := chain.Commit()
+	if len(block.Transactions()) != 1 {
+		t.Fatalf("区块包含 %d 笔交易, want 1", len(block.Transactions()))
+	}
+
+	select {
+	case result := <-done:
+		if result == nil || result.Status != ethclient.TxSuccess {
+			t.Errorf("等待结果 = %+v", result)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("WaitMined 没有返回")
+	}
+}
+
+func TestChain_MyToken(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	owner, spender, recipient := chain.Accounts[0], chain.Accounts[1], chain.Accounts[2]
+
+	token := chain.DeployMyToken(owner, 1000)
+
+	meta, err := token.Metadata(ctx)
+	if err != nil {
+		t.Fatalf("读取代币信息失败: %v", err)
+	}
+	if meta.Name != "MyToken" || meta.Symbol != "MTK" || meta.Decimals != 18 {
+		t.Errorf("代币信息 = %+v", meta)
+	}
+
+	supply := new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+	if total, _ := token.TotalSupply(ctx); total.Cmp(supply) != 0 {
+		t.Errorf("totalSupply = %s, want %s", total, supply)
+	}
+
+	amount := big.NewInt(params.Ether)
+	if _, err := token.Approve(ctx, owner.Signer, spender.Address, amount); err != nil {
+		t.Fatalf("approve 失败: %v", err)
+	}
+	if _, err := token.TransferFrom(ctx, spender.Signer, owner.Address, recipient.Address, amount); err != nil {
+		t.Fatalf("transferFrom 失败: %v", err)
+	}
+	if balance, _ := token.BalanceOf(ctx, recipient.Address); balance.Cmp(amount) != 0 {
+		t.Errorf("接收方余额 = %s, want %s", balance, amount)
+	}
+	if allowance, _ := token.Allowance(ctx, owner.Address, spender.Address); allowance.Sign() != 0 {
+		t.Errorf("剩余额度 = %s, want 0", allowance)
+	}
+
+	// 额度用完后再次 transferFrom 会在模拟执行时 revert
+	_, err = token.TransferFrom(ctx, spender.Signer, owner.Address, recipient.Address, amount)
+	if err == nil || !strings.Contains(err.Error(), "Allowance exceeded") {
+		t.Errorf("超出额度错误 = %v", err)
+	}
+	_, err = token.Transfer(ctx, recipient.Signer, owner.Address, supply)
+	if err == nil || !strings.Contains(err.Error(), "Insufficient balance") {
+		t.Errorf("余额不足错误 = %v", err)
+	}
+
+	logs, err := chain.Eth().FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{token.Address}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 铸造 Transfer、Approval、transferFrom 的 Transfer
+	if len(logs) != 3 {
+		t.Errorf("日志数量 = %d, want 3", len(logs))
+	}
+}
+
+func TestChain_SimpleStorage(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	owner := chain.Accounts[0]
+
+	address := chain.DeploySimpleStorage(owner)
+	artifact := testchain.SimpleStorageArtifact()
+
+	data, err := artifact.ABI.Pack("set", big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := chain.TxManager().Send(ctx, chain.Accounts[1].Signer, &address, nil, data)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	receipt, err := chain.Eth().TransactionReceipt(ctx, tx.Hash())
+	if err != nil || len(receipt.Logs) != 1 {
+		t.Fatalf("收据 = %+v, err = %v", receipt, err)
+	}
+	changed, err := artifact.ABI.Unpack("DataChanged", receipt.Logs[0].Data)
+	if err != nil || changed[0].(*big.Int).Sign() != 0 || changed[1].(*big.Int).Int64() != 42 {
+		t.Errorf("DataChanged = %v, err = %v", changed, err)
+	}
+
+	for method, want := range map[string]interface{}{
+		"get":      big.NewInt(42),
+		"getOwner": owner.Address,
+		"owner":    owner.Address,
+	} {
+		input, _ := artifact.ABI.Pack(method)
+		output, err := chain.Eth().CallContract(ctx, ethereum.CallMsg{To: &address, Data: input}, nil)
+		if err != nil {
+			t.Fatalf("%s 调用失败: %v", method, err)
+		}
+		values, err := artifact.ABI.Unpack(method, output)
+		if err != nil {
+			t.Fatalf("%s 解码失败: %v", method, err)
+		}
+		if contract.FormatString(values[0]) != contract.FormatString(want) {
+			t.Errorf("%s() = %v, want %v", method, values[0], want)
+		}
+	}
+}
@@ -0,0 +1,623 @@
+package testchain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/asm"
+
+	"go-eth-learning/pkg/contract"
+)
+
+// 测试环境中没有 solc，下面是 contracts/ 目录中合约的手写 EVM 汇编等价实现：
+// ABI、存储布局、事件和 revert 信息与 Solidity 源码一致，字节码不同于 solc 的编译结果。
+// 汇编语法见 go-ethereum core/asm：`label:` 定义跳转目标，`@label` 引用，`;;` 为注释。
+
+// 存储槽与 MyToken.sol 的声明顺序一致：name 0、symbol 1、decimals 2、_totalSupply 3、_balances 4、_allowances 5
+// name / symbol / decimals 在 Solidity 中从未修改，这里直接返回常量
+const myTokenConstructor = `
+	;; initialSupply 附加在创建字节码末尾
+	push 0x20
+	push 0x20
+	codesize
+	sub
+	push 0
+	codecopy
+	push 0
+	mload
+
+	;; _totalSupply = initialSupply * 10**18（溢出时 revert）
+	dup1
+	push 0x0de0b6b3a7640000
+	mul
+	dup1
+	push 0x0de0b6b3a7640000
+	swap1
+	div
+	dup3
+	eq
+	iszero
+	jumpi @overflow
+	swap1
+	pop
+	dup1
+	push 3
+	sstore
+
+	;; _balances[msg.sender] = _totalSupply
+	caller
+	push 0
+	mstore
+	push 4
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	dup2
+	swap1
+	sstore
+
+	;; emit Transfer(address(0), msg.sender, _totalSupply)
+	push 0
+	mstore
+	caller
+	push 0
+	push 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	push 0x20
+	push 0
+	log3
+	jump @done
+
+overflow:
+	push 0
+	dup1
+	revert
+
+done:
+`
+
+const myTokenRuntime = `
+	callvalue
+	jumpi @fail
+	push 0
+	calldataload
+	push 0xe0
+	shr
+	dup1
+	push 0x06fdde03
+	eq
+	jumpi @name
+	dup1
+	push 0x95d89b41
+	eq
+	jumpi @symbol
+	dup1
+	push 0x313ce567
+	eq
+	jumpi @decimals
+	dup1
+	push 0x18160ddd
+	eq
+	jumpi @totalSupply
+	dup1
+	push 0x70a08231
+	eq
+	jumpi @balanceOf
+	dup1
+	push 0xa9059cbb
+	eq
+	jumpi @transfer
+	dup1
+	push 0xdd62ed3e
+	eq
+	jumpi @allowance
+	dup1
+	push 0x095ea7b3
+	eq
+	jumpi @approve
+	dup1
+	push 0x23b872dd
+	eq
+	jumpi @transferFrom
+
+fail:
+	push 0
+	dup1
+	revert
+
+name:
+	push 0x20
+	push 0
+	mstore
+	push 7
+	push 0x20
+	mstore
+	push 0x4d79546f6b656e00000000000000000000000000000000000000000000000000
+	push 0x40
+	mstore
+	push 0x60
+	push 0
+	return
+
+symbol:
+	push 0x20
+	push 0
+	mstore
+	push 3
+	push 0x20
+	mstore
+	push 0x4d544b0000000000000000000000000000000000000000000000000000000000
+	push 0x40
+	mstore
+	push 0x60
+	push 0
+	return
+
+decimals:
+	push 18
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+totalSupply:
+	push 3
+	sload
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+balanceOf:
+	push 4
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	push 0
+	mstore
+	push 4
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	sload
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+allowance:
+	;; _allowances[owner][spender] = keccak256(spender . keccak256(owner . 5))
+	push 4
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	push 0
+	mstore
+	push 5
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	push 0x20
+	mstore
+	push 0x24
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	push 0
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	sload
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+approve:
+	caller
+	push 0
+	mstore
+	push 5
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	push 0x20
+	mstore
+	push 4
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	dup1
+	push 0
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	push 0x24
+	calldataload
+	swap1
+	sstore
+
+	;; emit Approval(msg.sender, spender, amount)
+	push 0x24
+	calldataload
+	push 0
+	mstore
+	caller
+	push 0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925
+	push 0x20
+	push 0
+	log3
+	jump @returnTrue
+
+transfer:
+	;; 栈：from to amount
+	caller
+	push 4
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	push 0x24
+	calldataload
+	jump @move
+
+transferFrom:
+	;; require(_balances[sender] >= amount)
+	push 4
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	dup1
+	push 0
+	mstore
+	push 4
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	sload
+	push 0x44
+	calldataload
+	gt
+	jumpi @insufficientBalance
+
+	;; require(_allowances[sender][msg.sender] >= amount) 并扣减额度
+	dup1
+	push 0
+	mstore
+	push 5
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	push 0x20
+	mstore
+	caller
+	push 0
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	dup1
+	sload
+	push 0x44
+	calldataload
+	dup1
+	dup3
+	lt
+	jumpi @allowanceExceeded
+	swap1
+	sub
+	swap1
+	sstore
+
+	;; 栈：from to amount
+	push 0x24
+	calldataload
+	push 0xffffffffffffffffffffffffffffffffffffffff
+	and
+	push 0x44
+	calldataload
+
+move:
+	;; _balances[from] -= amount
+	dup3
+	push 0
+	mstore
+	push 4
+	push 0x20
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	dup1
+	sload
+	dup1
+	dup4
+	gt
+	jumpi @insufficientBalance
+	dup3
+	swap1
+	sub
+	swap1
+	sstore
+
+	;; _balances[to] += amount
+	dup2
+	push 0
+	mstore
+	push 0x40
+	push 0
+	keccak256
+	dup1
+	sload
+	dup3
+	add
+	swap1
+	sstore
+
+	;; emit Transfer(from, to, amount)
+	push 0
+	mstore
+	swap1
+	push 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef
+	push 0x20
+	push 0
+	log3
+
+returnTrue:
+	push 1
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+insufficientBalance:
+	;; Error("Insufficient balance")
+	push 0x08c379a0
+	push 0xe0
+	shl
+	push 0
+	mstore
+	push 0x20
+	push 4
+	mstore
+	push 20
+	push 0x24
+	mstore
+	push 0x496e73756666696369656e742062616c616e6365000000000000000000000000
+	push 0x44
+	mstore
+	push 0x64
+	push 0
+	revert
+
+allowanceExceeded:
+	;; Error("Allowance exceeded")
+	push 0x08c379a0
+	push 0xe0
+	shl
+	push 0
+	mstore
+	push 0x20
+	push 4
+	mstore
+	push 18
+	push 0x24
+	mstore
+	push 0x416c6c6f77616e63652065786365656465640000000000000000000000000000
+	push 0x44
+	mstore
+	push 0x64
+	push 0
+	revert
+`
+
+const myTokenABI = `[
+	{"type":"constructor","inputs":[{"name":"initialSupply","type":"uint256"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"name","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"symbol","inputs":[],"outputs":[{"name":"","type":"string"}],"stateMutability":"view"},
+	{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"},
+	{"type":"function","name":"totalSupply","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"allowance","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"sender","type":"address"},{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false}
+]`
+
+// 存储槽与 SimpleStorage.sol 一致：storedData 0、owner 1
+const simpleStorageConstructor = `
+	caller
+	push 1
+	sstore
+`
+
+const simpleStorageRuntime = `
+	callvalue
+	jumpi @fail
+	push 0
+	calldataload
+	push 0xe0
+	shr
+	dup1
+	push 0x60fe47b1
+	eq
+	jumpi @set
+	dup1
+	push 0x6d4ce63c
+	eq
+	jumpi @get
+	dup1
+	push 0x893d20e8
+	eq
+	jumpi @owner
+	dup1
+	push 0x8da5cb5b
+	eq
+	jumpi @owner
+
+fail:
+	push 0
+	dup1
+	revert
+
+set:
+	;; emit DataChanged(storedData, x); storedData = x
+	push 0
+	sload
+	push 0
+	mstore
+	push 4
+	calldataload
+	dup1
+	push 0x20
+	mstore
+	push 0
+	sstore
+	push 0x8e28f9467fd700ad8e18df63125408ed029967c1f2c0f0e262dfb79476a99ad3
+	push 0x40
+	push 0
+	log1
+	stop
+
+get:
+	push 0
+	sload
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+
+owner:
+	push 1
+	sload
+	push 0
+	mstore
+	push 0x20
+	push 0
+	return
+`
+
+const simpleStorageABI = `[
+	{"type":"constructor","inputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"set","inputs":[{"name":"x","type":"uint256"}],"outputs":[],"stateMutability":"nonpayable"},
+	{"type":"function","name":"get","inputs":[],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"getOwner","inputs":[],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"function","name":"owner","inputs":[],"outputs":[{"name":"","type":"address"}],"stateMutability":"view"},
+	{"type":"event","name":"DataChanged","inputs":[{"name":"oldValue","type":"uint256","indexed":false},{"name":"newValue","type":"uint256","indexed":false}],"anonymous":false}
+]`
+
+// MyTokenArtifact 返回 contracts/ERC20.sol 中 MyToken 的编译产物，构造参数为 initialSupply（不含小数位）
+func MyTokenArtifact() *contract.Artifact {
+	return mustArtifact("MyToken", myTokenABI, myTokenConstructor, myTokenRuntime)
+}
+
+// SimpleStorageArtifact 返回 contracts/SimpleStorage.sol 的编译产物
+func SimpleStorageArtifact() *contract.Artifact {
+	return mustArtifact("SimpleStorage", simpleStorageABI, simpleStorageConstructor, simpleStorageRuntime)
+}
+
+// Deploy 用 from 部署合约并等待上链，手动出块模式下会自动出块
+func (c *Chain) Deploy(from *Account, artifact *contract.Artifact, args ...interface{}) *contract.Deployment {
+	c.t.Helper()
+
+	prev := c.SetAutoCommit(true)
+	defer c.SetAutoCommit(prev)
+
+	deployment, err := contract.NewDeployer(c.TxManager(), c.client).Deploy(context.Background(), from.Signer, artifact, args...)
+	if err != nil {
+		c.t.Fatalf("部署 %s 失败: %v", artifact.Name, err)
+	}
+	return deployment
+}
+
+// DeployMyToken 部署 MyToken，initialSupply 个代币（18 位小数）全部归 from 所有
+func (c *Chain) DeployMyToken(from *Account, initialSupply int64) *contract.ERC20Contract {
+	c.t.Helper()
+
+	deployment := c.Deploy(from, MyTokenArtifact(), big.NewInt(initialSupply))
+	token, err := contract.NewERC20Contract(deployment.Address.Hex(), c.eth, c.TxManager())
+	if err != nil {
+		c.t.Fatalf("创建 ERC20 绑定失败: %v", err)
+	}
+	return token
+}
+
+// DeploySimpleStorage 部署 SimpleStorage，返回合约地址
+func (c *Chain) DeploySimpleStorage(from *Account) common.Address {
+	c.t.Helper()
+
+	return c.Deploy(from, SimpleStorageArtifact()).Address
+}
+
+// mustArtifact 汇编构造代码和运行时代码，生成创建字节码
+func mustArtifact(name, abiJSON, constructor, runtime string) *contract.Artifact {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(fmt.Sprintf("%s ABI 无效: %v", name, err))
+	}
+	return &contract.Artifact{
+		Name:     name,
+		ABI:      parsed,
+		Bytecode: creationCode(mustAssemble(name, constructor), mustAssemble(name, runtime)),
+	}
+}
+
+// creationCode 在构造代码之后追加返回运行时代码的指令，构造参数位于字节码末尾
+//
+//	PUSH2 len  DUP1  PUSH2 offset  PUSH1 0  CODECOPY  PUSH1 0  RETURN  <runtime>
+func creationCode(constructor, runtime []byte) []byte {
+	const suffixLen = 13
+	offset := len(constructor) + suffixLen
+
+	code := append([]byte{}, constructor...)
+	code = append(code,
+		0x61, byte(len(runtime)>>8), byte(len(runtime)),
+		0x80,
+		0x61, byte(offset>>8), byte(offset),
+		0x60, 0x00,
+		0x39,
+		0x60, 0x00,
+		0xf3,
+	)
+	return append(code, runtime...)
+}
+
+// mustAssemble 汇编 EVM 代码，源码错误属于编程错误，直接 panic
+func mustAssemble(name, source string) []byte {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(strings.TrimSpace(source)+"\n"), false))
+	code, errs := compiler.Compile()
+	if len(errs) > 0 {
+		panic(fmt.Sprintf("%s 汇编失败: %v", name, errs))
+	}
+	bytecode, err := hex.DecodeString(code)
+	if err != nil {
+		panic(fmt.Sprintf("%s 汇编失败: %v", name, err))
+	}
+	return bytecode
+}
@@ -0,0 +1,591 @@
+package testchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gomath "math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// errHeaderNotFound 与 geth 返回的错误信息一致
+var errHeaderNotFound = errors.New("header not found")
+
+// ethAPI 在 SimulatedBackend 之上实现 eth 命名空间中本仓库用到的方法
+//
+// 与真实节点的差异：pending 余额按最新区块返回；
+// safe / finalized 等同于 latest。
+type ethAPI struct {
+	chain *Chain
+}
+
+// ChainId eth_chainId
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.chain.ChainID())
+}
+
+// BlockNumber eth_blockNumber
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(api.chain.sim.Blockchain().CurrentBlock().Number.Uint64())
+}
+
+// GetBalance eth_getBalance
+func (api *ethAPI) GetBalance(address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	st, err := api.state(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(st.GetBalance(address)), nil
+}
+
+// GetCode eth_getCode
+func (api *ethAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if isPending(blockNrOrHash) {
+		return api.chain.sim.PendingCodeAt(ctx, address)
+	}
+	st, err := api.state(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return st.GetCode(address), nil
+}
+
+// GetStorageAt eth_getStorageAt
+func (api *ethAPI) GetStorageAt(address common.Address, key common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	st, err := api.state(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	value := st.GetState(address, key)
+	return value[:], nil
+}
+
+// GetTransactionCount eth_getTransactionCount
+func (api *ethAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if isPending(blockNrOrHash) {
+		nonce, err := api.chain.sim.PendingNonceAt(ctx, address)
+		return hexutil.Uint64(nonce), err
+	}
+	st, err := api.state(blockNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(st.GetNonce(address)), nil
+}
+
+// Call eth_call
+func (api *ethAPI) Call(ctx context.Context, args callArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	bnh := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bnh = *blockNrOrHash
+	}
+	if isPending(bnh) {
+		return api.chain.sim.PendingCallContract(ctx, args.message())
+	}
+
+	header, err := api.header(bnh)
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() == api.chain.sim.Blockchain().CurrentBlock().Hash() {
+		return api.chain.sim.CallContract(ctx, args.message(), nil)
+	}
+	return api.callAt(header, args.message())
+}
+
+// callAt 在历史区块的状态上执行 eth_call，SimulatedBackend 只支持最新区块
+func (api *ethAPI) callAt(header *types.Header, call ethereum.CallMsg) (hexutil.Bytes, error) {
+	bc := api.chain.sim.Blockchain()
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &core.Message{
+		From:              call.From,
+		To:                call.To,
+		Value:             new(big.Int),
+		GasLimit:          call.Gas,
+		GasPrice:          new(big.Int),
+		GasFeeCap:         new(big.Int),
+		GasTipCap:         new(big.Int),
+		Data:              call.Data,
+		AccessList:        call.AccessList,
+		SkipAccountChecks: true,
+	}
+	if call.Value != nil {
+		msg.Value = call.Value
+	}
+	if msg.GasLimit == 0 {
+		msg.GasLimit = header.GasLimit
+	}
+
+	evm := vm.NewEVM(core.NewEVMBlockContext(header, bc, nil), core.NewEVMTxContext(msg), statedb, bc.Config(), vm.Config{NoBaseFee: true})
+	res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(gomath.MaxUint64))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Revert()) > 0 {
+		return nil, newRevertError(res.Revert())
+	}
+	return res.Return(), res.Err
+}
+
+// revertError 带 revert 数据的 JSON-RPC 错误，格式与 geth 一致
+type revertError struct {
+	error
+	data string
+}
+
+func newRevertError(data []byte) *revertError {
+	err := errors.New("execution reverted")
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		err = fmt.Errorf("execution reverted: %v", reason)
+	}
+	return &revertError{error: err, data: hexutil.Encode(data)}
+}
+
+// ErrorCode JSON-RPC 错误码
+func (e *revertError) ErrorCode() int { return 3 }
+
+// ErrorData revert 数据
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// EstimateGas eth_estimateGas，总是基于 pending 状态估算
+func (api *ethAPI) EstimateGas(ctx context.Context, args callArgs, _ *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	gas, err := api.chain.sim.EstimateGas(ctx, args.message())
+	return hexutil.Uint64(gas), err
+}
+
+// GasPrice eth_gasPrice：下一个区块的 baseFee 加建议小费
+func (api *ethAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	baseFee, err := api.chain.sim.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := api.chain.sim.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(new(big.Int).Add(baseFee, tip)), nil
+}
+
+// MaxPriorityFeePerGas eth_maxPriorityFeePerGas
+func (api *ethAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tip, err := api.chain.sim.SuggestGasTipCap(ctx)
+	return (*hexutil.Big)(tip), err
+}
+
+// feeHistoryResult eth_feeHistory 返回值
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory eth_feeHistory
+func (api *ethAPI) FeeHistory(blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	bc := api.chain.sim.Blockchain()
+	last, err := api.headerByNumber(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	count := uint64(blockCount)
+	if n := last.Number.Uint64() + 1; count > n {
+		count = n
+	}
+	oldest := last.Number.Uint64() + 1 - count
+
+	result := &feeHistoryResult{OldestBlock: (*hexutil.Big)(new(big.Int).SetUint64(oldest))}
+	for number := oldest; number <= last.Number.Uint64(); number++ {
+		block := bc.GetBlockByNumber(number)
+		result.BaseFee = append(result.BaseFee, (*hexutil.Big)(block.BaseFee()))
+		result.GasUsedRatio = append(result.GasUsedRatio, float64(block.GasUsed())/float64(block.GasLimit()))
+		if len(rewardPercentiles) > 0 {
+			result.Reward = append(result.Reward, blockRewards(block, bc.GetReceiptsByHash(block.Hash()), rewardPercentiles))
+		}
+	}
+	// 最后一项为下一个区块的 baseFee
+	result.BaseFee = append(result.BaseFee, (*hexutil.Big)(eip1559.CalcBaseFee(bc.Config(), last)))
+	return result, nil
+}
+
+// blockRewards 按 gas 用量加权计算区块内小费的百分位数，算法与 geth 一致
+func blockRewards(block *types.Block, receipts types.Receipts, percentiles []float64) []*hexutil.Big {
+	rewards := make([]*hexutil.Big, len(percentiles))
+	txs := block.Transactions()
+	if len(txs) == 0 || len(receipts) != len(txs) {
+		for i := range rewards {
+			rewards[i] = (*hexutil.Big)(new(big.Int))
+		}
+		return rewards
+	}
+
+	type txGas struct {
+		gasUsed uint64
+		reward  *big.Int
+	}
+	sorted := make([]txGas, len(txs))
+	for i, tx := range txs {
+		reward, _ := tx.EffectiveGasTip(block.BaseFee())
+		sorted[i] = txGas{gasUsed: receipts[i].GasUsed, reward: reward}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].reward.Cmp(sorted[j].reward) < 0 })
+
+	var index int
+	sumGasUsed := sorted[0].gasUsed
+	for i, p := range percentiles {
+		threshold := uint64(float64(block.GasUsed()) * p / 100)
+		for sumGasUsed < threshold && index < len(sorted)-1 {
+			index++
+			sumGasUsed += sorted[index].gasUsed
+		}
+		rewards[i] = (*hexutil.Big)(sorted[index].reward)
+	}
+	return rewards
+}
+
+// SendRawTransaction eth_sendRawTransaction
+func (api *ethAPI) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.chain.submit(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// GetTransactionByHash eth_getTransactionByHash，交易不存在时返回 null
+func (api *ethAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, isPending, err := api.chain.sim.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isPending {
+		return api.marshalTx(tx, common.Hash{}, nil, 0)
+	}
+
+	_, blockHash, number, index := rawdb.ReadTransaction(api.chain.db, hash)
+	return api.marshalTx(tx, blockHash, new(big.Int).SetUint64(number), index)
+}
+
+// GetTransactionReceipt eth_getTransactionReceipt，收据不存在时返回 null
+func (api *ethAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	receipt, err := api.chain.sim.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Logs == nil {
+		receipt.Logs = []*types.Log{}
+	}
+	return receipt, nil
+}
+
+// GetBlockByNumber eth_getBlockByNumber，pending 返回最新区块
+func (api *ethAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	header, err := api.headerByNumber(number)
+	if errors.Is(err, errHeaderNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return api.marshalBlock(api.chain.sim.Blockchain().GetBlock(header.Hash(), header.Number.Uint64()), fullTx)
+}
+
+// GetBlockByHash eth_getBlockByHash
+func (api *ethAPI) GetBlockByHash(hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block := api.chain.sim.Blockchain().GetBlockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+	return api.marshalBlock(block, fullTx)
+}
+
+// GetLogs eth_getLogs
+func (api *ethAPI) GetLogs(ctx context.Context, crit filters.FilterCriteria) ([]types.Log, error) {
+	logs, err := api.chain.sim.FilterLogs(ctx, ethereum.FilterQuery(crit))
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []types.Log{}
+	}
+	return logs, nil
+}
+
+// NewHeads eth_subscribe("newHeads")
+func (api *ethAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	headers := make(chan *types.Header, 16)
+	source, err := api.chain.sim.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return nil, err
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer source.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				notifier.Notify(sub.ID, header)
+			case <-sub.Err():
+				return
+			case <-source.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Logs eth_subscribe("logs")
+func (api *ethAPI) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	logs := make(chan types.Log, 16)
+	source, err := api.chain.sim.SubscribeFilterLogs(ctx, ethereum.FilterQuery(crit), logs)
+	if err != nil {
+		return nil, err
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer source.Unsubscribe()
+		for {
+			select {
+			case l := <-logs:
+				notifier.Notify(sub.ID, &l)
+			case <-sub.Err():
+				return
+			case <-source.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// NewPendingTransactions eth_subscribe("newPendingTransactions")，只推送交易哈希
+func (api *ethAPI) NewPendingTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	hashes := make(chan common.Hash, 16)
+	source := api.chain.txFeed.Subscribe(hashes)
+	sub := notifier.CreateSubscription()
+	go func() {
+		defer source.Unsubscribe()
+		for {
+			select {
+			case hash := <-hashes:
+				notifier.Notify(sub.ID, hash)
+			case <-sub.Err():
+				return
+			case <-source.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// headerByNumber 解析区块号，区块不存在时返回 errHeaderNotFound
+func (api *ethAPI) headerByNumber(number rpc.BlockNumber) (*types.Header, error) {
+	bc := api.chain.sim.Blockchain()
+	switch number {
+	case rpc.PendingBlockNumber, rpc.LatestBlockNumber, rpc.SafeBlockNumber, rpc.FinalizedBlockNumber:
+		return bc.CurrentBlock(), nil
+	case rpc.EarliestBlockNumber:
+		number = 0
+	}
+	if header := bc.GetHeaderByNumber(uint64(number)); header != nil {
+		return header, nil
+	}
+	return nil, errHeaderNotFound
+}
+
+// header 解析区块号或区块哈希
+func (api *ethAPI) header(blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		return api.headerByNumber(number)
+	}
+
+	hash, _ := blockNrOrHash.Hash()
+	bc := api.chain.sim.Blockchain()
+	header := bc.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errHeaderNotFound
+	}
+	if blockNrOrHash.RequireCanonical && bc.GetCanonicalHash(header.Number.Uint64()) != hash {
+		return nil, fmt.Errorf("hash %s is not currently canonical", hash.Hex())
+	}
+	return header, nil
+}
+
+// state 返回指定区块的状态
+func (api *ethAPI) state(blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, error) {
+	header, err := api.header(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return api.chain.sim.Blockchain().StateAt(header.Root)
+}
+
+// marshalTx 生成 RPC 格式的交易，附带发送者和所在区块信息
+func (api *ethAPI) marshalTx(tx *types.Transaction, blockHash common.Hash, blockNumber *big.Int, index uint64) (map[string]interface{}, error) {
+	fields, err := toMap(tx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(api.chain.ChainID()), tx)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["from"] = from
+	fields["blockHash"] = nil
+	fields["blockNumber"] = nil
+	fields["transactionIndex"] = nil
+	if blockNumber != nil {
+		fields["blockHash"] = blockHash
+		fields["blockNumber"] = (*hexutil.Big)(blockNumber)
+		fields["transactionIndex"] = hexutil.Uint64(index)
+	}
+	return fields, nil
+}
+
+// marshalBlock 生成 RPC 格式的区块
+func (api *ethAPI) marshalBlock(block *types.Block, fullTx bool) (map[string]interface{}, error) {
+	fields, err := toMap(block.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !fullTx {
+			txs[i] = tx.Hash()
+			continue
+		}
+		if txs[i], err = api.marshalTx(tx, block.Hash(), block.Number(), uint64(i)); err != nil {
+			return nil, err
+		}
+	}
+	uncles := make([]common.Hash, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+
+	fields["size"] = hexutil.Uint64(block.Size())
+	fields["transactions"] = txs
+	fields["uncles"] = uncles
+	if block.Withdrawals() != nil {
+		fields["withdrawals"] = block.Withdrawals()
+	}
+	return fields, nil
+}
+
+// toMap 将实现了 json.Marshaler 的类型转换为可追加字段的 map
+func toMap(v json.Marshaler) (map[string]interface{}, error) {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// isPending 判断是否查询 pending 状态
+func isPending(blockNrOrHash rpc.BlockNumberOrHash) bool {
+	number, ok := blockNrOrHash.Number()
+	return ok && number == rpc.PendingBlockNumber
+}
+
+// callArgs eth_call / eth_estimateGas 参数
+type callArgs struct {
+	From                 *common.Address   `json:"from"`
+	To                   *common.Address   `json:"to"`
+	Gas                  *hexutil.Uint64   `json:"gas"`
+	GasPrice             *hexutil.Big      `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big      `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big      `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big      `json:"value"`
+	Data                 *hexutil.Bytes    `json:"data"`
+	Input                *hexutil.Bytes    `json:"input"`
+	AccessList           *types.AccessList `json:"accessList"`
+}
+
+// message 转换为 ethereum.CallMsg
+func (args *callArgs) message() ethereum.CallMsg {
+	msg := ethereum.CallMsg{
+		To:        args.To,
+		GasPrice:  (*big.Int)(args.GasPrice),
+		GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+		GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+		Value:     (*big.Int)(args.Value),
+	}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	if args.AccessList != nil {
+		msg.AccessList = *args.AccessList
+	}
+	return msg
+}
+
+// netAPI net 命名空间
+type netAPI struct {
+	chain *Chain
+}
+
+// Version net_version
+func (api *netAPI) Version() string {
+	return strconv.FormatUint(api.chain.ChainID().Uint64(), 10)
+}
@@ -0,0 +1,377 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go-eth-learning/pkg/utils"
+)
+
+// FindMethod 按名称或完整签名查找方法，重载方法需使用签名，如 "safeTransferFrom(address,address,uint256)"
+func FindMethod(parsed abi.ABI, name string) (abi.Method, error) {
+	if strings.Contains(name, "(") {
+		sig := strings.ReplaceAll(name, " ", "")
+		for _, m := range parsed.Methods {
+			if m.Sig == sig {
+				return m, nil
+			}
+		}
+		return abi.Method{}, fmt.Errorf("ABI 中没有方法 %s", name)
+	}
+
+	var overloads []string
+	for _, m := range parsed.Methods {
+		if m.RawName == name {
+			overloads = append(overloads, m.Sig)
+		}
+	}
+	switch len(overloads) {
+	case 0:
+		return abi.Method{}, fmt.Errorf("ABI 中没有方法 %s", name)
+	case 1:
+		return FindMethod(parsed, overloads[0])
+	default:
+		sort.Strings(overloads)
+		return abi.Method{}, fmt.Errorf("方法 %s 有多个重载，请使用完整签名: %v", name, overloads)
+	}
+}
+
+// ParseArgs 将字符串参数按 ABI 类型逐个转换，数量必须与参数定义一致
+func ParseArgs(args abi.Arguments, values []string) ([]interface{}, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("参数数量不匹配: 需要 %d 个 %s，实际 %d 个", len(args), argTypes(args), len(values))
+	}
+
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := ParseValue(arg.Type, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("参数 %d (%s %s): %w", i+1, arg.Type, arg.Name, err)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// ParseValue 将字符串转换为 ABI 类型对应的 Go 值
+//
+// 整数支持十进制、0x 十六进制和 1e18 形式；bytes 使用十六进制；
+// 数组与元组使用 JSON 语法，如 [1,2,3]、["0xab...", 5] 或 {"to": "0x...", "amount": 1}
+func ParseValue(t abi.Type, s string) (interface{}, error) {
+	v, err := convertValue(t, s)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// convertValue 递归转换，raw 为字符串或 JSON 解码得到的值
+func convertValue(t abi.Type, raw interface{}) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := parseInteger(t, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		typ := t.GetType()
+		if typ == reflect.TypeOf(n) {
+			return reflect.ValueOf(n), nil
+		}
+		if t.T == abi.IntTy {
+			return reflect.ValueOf(n.Int64()).Convert(typ), nil
+		}
+		return reflect.ValueOf(n.Uint64()).Convert(typ), nil
+
+	case abi.BoolTy:
+		if b, ok := raw.(bool); ok {
+			return reflect.ValueOf(b), nil
+		}
+		b, err := strconv.ParseBool(scalarString(raw))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无效布尔值: %v", raw)
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		return reflect.ValueOf(scalarString(raw)), nil
+
+	case abi.AddressTy:
+		// 大小写混合时校验 EIP-55 校验和，错误为 *utils.AddressError
+		addr, err := utils.ParseAddress(scalarString(raw))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(addr), nil
+
+	case abi.BytesTy:
+		b, err := decodeHexArg(scalarString(raw))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.FixedBytesTy, abi.FunctionTy:
+		b, err := decodeHexArg(scalarString(raw))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		size := t.GetType().Len()
+		if len(b) != size {
+			return reflect.Value{}, fmt.Errorf("需要 %d 字节，实际 %d 字节", size, len(b))
+		}
+		v := reflect.New(t.GetType()).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		items, err := jsonList(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.T == abi.ArrayTy {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("需要 %d 个元素，实际 %d 个", t.Size, len(items))
+			}
+			v = reflect.New(t.GetType()).Elem()
+		} else {
+			v = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		}
+		for i, item := range items {
+			ev, err := convertValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("元素 %d: %w", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+
+	case abi.TupleTy:
+		items, err := tupleItems(t, raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v := reflect.New(t.GetType()).Elem()
+		for i, elem := range t.TupleElems {
+			fv, err := convertValue(*elem, items[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("字段 %s: %w", t.TupleRawNames[i], err)
+			}
+			v.Field(i).Set(fv)
+		}
+		return v, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("不支持的参数类型 %s", t)
+	}
+}
+
+// decimalPattern 十进制数，可带小数和指数，如 1e18、1.5e6
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// parseInteger 解析整数并检查是否在类型范围内
+//
+// 只有 0x 前缀表示十六进制，其余一律按十进制解析：010 是 10 而不是八进制的 8，0b、0o 前缀视为无效
+func parseInteger(t abi.Type, raw interface{}) (*big.Int, error) {
+	s := strings.ReplaceAll(scalarString(raw), "_", "")
+	digits := strings.TrimLeft(s, "+-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		base = 0
+	}
+	n, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		// 1e18、1.5e6 等科学计数法
+		if base != 10 || !decimalPattern.MatchString(s) {
+			return nil, fmt.Errorf("无效整数: %s", s)
+		}
+		r, ok := new(big.Rat).SetString(s)
+		if !ok || !r.IsInt() {
+			return nil, fmt.Errorf("无效整数: %s", s)
+		}
+		n = new(big.Int).Set(r.Num())
+	}
+
+	if t.T == abi.UintTy {
+		if n.Sign() < 0 || n.BitLen() > t.Size {
+			return nil, fmt.Errorf("%s 超出 %s 范围", n, t)
+		}
+		return n, nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("%s 超出 %s 范围", n, t)
+	}
+	return n, nil
+}
+
+// tupleItems 元组可以写成按顺序的 JSON 数组，也可以写成按字段名的 JSON 对象
+func tupleItems(t abi.Type, raw interface{}) ([]interface{}, error) {
+	if s, ok := raw.(string); ok {
+		decoded, err := decodeJSON(s)
+		if err != nil {
+			return nil, err
+		}
+		raw = decoded
+	}
+
+	switch v := raw.(type) {
+	case []interface{}:
+		if len(v) != len(t.TupleElems) {
+			return nil, fmt.Errorf("元组需要 %d 个字段，实际 %d 个", len(t.TupleElems), len(v))
+		}
+		return v, nil
+	case map[string]interface{}:
+		items := make([]interface{}, len(t.TupleElems))
+		for i, name := range t.TupleRawNames {
+			item, ok := v[name]
+			if !ok {
+				return nil, fmt.Errorf("元组缺少字段 %s", name)
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("元组需要 JSON 数组或对象")
+	}
+}
+
+// jsonList 将 JSON 数组字符串或已解码的数组转换为元素列表
+func jsonList(raw interface{}) ([]interface{}, error) {
+	if s, ok := raw.(string); ok {
+		decoded, err := decodeJSON(s)
+		if err != nil {
+			return nil, err
+		}
+		raw = decoded
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("数组需要 JSON 数组，如 [1,2,3]")
+	}
+	return items, nil
+}
+
+// decodeJSON 解码 JSON，数字保留原始文本以免丢失精度
+func decodeJSON(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("无效 JSON %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// scalarString 取标量的文本形式
+func scalarString(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// decodeHexArg 解析十六进制字节参数，0x 前缀可选
+func decodeHexArg(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("无效十六进制数据 %s: %w", s, err)
+	}
+	return b, nil
+}
+
+// argTypes 参数类型列表，用于错误提示
+func argTypes(args abi.Arguments) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type.String()
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
+
+// FormatValue 将 ABI 解码得到的值转换为便于打印和 JSON 序列化的形式：
+// 大整数转十进制字符串，地址和字节转十六进制，元组转为以字段名为键的对象
+func FormatValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case *big.Int:
+		return x.String()
+	case common.Address:
+		return x.Hex()
+	case common.Hash:
+		return x.Hex()
+	case []byte:
+		return hexutil.Encode(x)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			f := rv.Type().Field(i)
+			name := f.Tag.Get("json")
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = FormatValue(rv.Field(i).Interface())
+		}
+		return fields
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil
+		}
+		return FormatValue(rv.Elem().Interface())
+	}
+	return v
+}
+
+// FormatString 将值格式化为单行文本，复合类型使用 JSON
+func FormatString(v interface{}) string {
+	formatted := FormatValue(v)
+	switch formatted.(type) {
+	case []interface{}, map[string]interface{}:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(formatted); err == nil {
+			return strings.TrimSpace(buf.String())
+		}
+	}
+	return fmt.Sprint(formatted)
+}
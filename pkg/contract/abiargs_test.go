@@ -0,0 +1,154 @@
+package contract_test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/utils"
+)
+
+const testABI = `[
+	{"type": "function", "name": "set", "stateMutability": "nonpayable", "inputs": [{"name": "x", "type": "uint256"}], "outputs": []},
+	{"type": "function", "name": "mixed", "stateMutability": "view",
+		"inputs": [
+			{"name": "small", "type": "uint8"},
+			{"name": "signed", "type": "int256"},
+			{"name": "who", "type": "address"},
+			{"name": "flag", "type": "bool"},
+			{"name": "key", "type": "bytes32"},
+			{"name": "blob", "type": "bytes"},
+			{"name": "ids", "type": "uint64[]"},
+			{"name": "pair", "type": "address[2]"},
+			{"name": "order", "type": "tuple", "components": [
+				{"name": "to", "type": "address"},
+				{"name": "amounts", "type": "uint256[]"}
+			]}
+		],
+		"outputs": [{"name": "", "type": "bool"}]},
+	{"type": "function", "name": "f", "stateMutability": "view", "inputs": [{"name": "a", "type": "uint256"}], "outputs": []},
+	{"type": "function", "name": "f", "stateMutability": "view", "inputs": [{"name": "a", "type": "address"}], "outputs": []}
+]`
+
+func parseTestABI(t *testing.T) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseArgs(t *testing.T) {
+	parsed := parseTestABI(t)
+	method, err := contract.FindMethod(parsed, "mixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	who := "0x742D35CC6634c0532925A3b844BC9E7595F0BEb0"
+	args, err := contract.ParseArgs(method.Inputs, []string{
+		"255",
+		"-1e18",
+		who,
+		"true",
+		"0x" + strings.Repeat("ab", 32),
+		"0xdeadbeef",
+		"[1, 2, 3]",
+		`["` + who + `", "0x0000000000000000000000000000000000000001"]`,
+		`{"to": "` + who + `", "amounts": [1, "0x10"]}`,
+	})
+	if err != nil {
+		t.Fatalf("转换参数失败: %v", err)
+	}
+
+	// 转换结果必须能被 go-ethereum 编码
+	if _, err := parsed.Pack(method.Name, args...); err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	if args[0].(uint8) != 255 {
+		t.Errorf("uint8 = %v", args[0])
+	}
+	if want, _ := new(big.Int).SetString("-1000000000000000000", 10); args[1].(*big.Int).Cmp(want) != 0 {
+		t.Errorf("int256 = %v", args[1])
+	}
+	if got := contract.FormatString(args[8]); got != `{"amounts":["1","16"],"to":"`+common.HexToAddress(who).Hex()+`"}` {
+		t.Errorf("tuple = %s", got)
+	}
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	parsed := parseTestABI(t)
+	uint8Type, _ := abi.NewType("uint8", "", nil)
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+
+	if _, err := contract.ParseValue(uint8Type, "256"); err == nil {
+		t.Error("256 超出 uint8 范围应该报错")
+	}
+	// 只有 0x 前缀按十六进制解析，前导零不是八进制，不支持 0b / 0o
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	for in, want := range map[string]int64{"010": 10, "0x10": 16, "1e3": 1000, "007": 7} {
+		if v, err := contract.ParseValue(uint256Type, in); err != nil || v.(*big.Int).Int64() != want {
+			t.Errorf("ParseValue(uint256, %s) = %v, %v, want %d", in, v, err, want)
+		}
+	}
+	for _, in := range []string{"0b11", "0o17", "1/2"} {
+		if v, err := contract.ParseValue(uint256Type, in); err == nil {
+			t.Errorf("ParseValue(uint256, %s) = %v，应该报错", in, v)
+		}
+	}
+	// 大小写混合的地址必须通过 EIP-55 校验
+	addressType, _ := abi.NewType("address", "", nil)
+	var addrErr *utils.AddressError
+	if _, err := contract.ParseValue(addressType, "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0"); !errors.As(err, &addrErr) || !errors.Is(err, utils.ErrBadChecksum) {
+		t.Errorf("校验和错误的地址 err = %v, want ErrBadChecksum", err)
+	}
+	if _, err := contract.ParseValue(bytes32Type, "0x1234"); err == nil {
+		t.Error("bytes32 长度不足应该报错")
+	}
+	if _, err := contract.ParseArgs(parsed.Methods["set"].Inputs, nil); err == nil {
+		t.Error("参数数量不足应该报错")
+	}
+
+	// 重载方法必须使用签名
+	if _, err := contract.FindMethod(parsed, "f"); err == nil {
+		t.Error("重载方法按名称查找应该报错")
+	}
+	if m, err := contract.FindMethod(parsed, "f(address)"); err != nil || m.Inputs[0].Type.T != abi.AddressTy {
+		t.Errorf("按签名查找失败: %v", err)
+	}
+}
+
+func TestParseArtifact(t *testing.T) {
+	abiJSON := `[{"type": "function", "name": "get", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint256"}]}]`
+
+	tests := map[string]string{
+		"abi":      abiJSON,
+		"hardhat":  `{"contractName": "SimpleStorage", "abi": ` + abiJSON + `, "bytecode": "0x6080"}`,
+		"foundry":  `{"abi": ` + abiJSON + `, "bytecode": {"object": "0x6080"}}`,
+		"combined": `{"contracts": {"contracts/SimpleStorage.sol:SimpleStorage": {"abi": ` + abiJSON + `, "bin": "6080"}}}`,
+		"standard": `{"contracts": {"SimpleStorage.sol": {"SimpleStorage": {"abi": ` + abiJSON + `, "evm": {"bytecode": {"object": "6080"}}}}}}`,
+	}
+	for format, data := range tests {
+		artifact, err := contract.ParseArtifact([]byte(data), "SimpleStorage")
+		if err != nil {
+			t.Errorf("%s: %v", format, err)
+			continue
+		}
+		if _, ok := artifact.ABI.Methods["get"]; !ok {
+			t.Errorf("%s: 缺少 get 方法", format)
+		}
+		if format != "abi" && common.Bytes2Hex(artifact.Bytecode) != "6080" {
+			t.Errorf("%s: 字节码 = %x", format, artifact.Bytecode)
+		}
+	}
+
+	multi := `{"contracts": {"a.sol:A": {"abi": []}, "b.sol:B": {"abi": []}}}`
+	if _, err := contract.ParseArtifact([]byte(multi), "C"); err == nil {
+		t.Error("找不到合约时应该报错")
+	}
+}
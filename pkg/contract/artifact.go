@@ -0,0 +1,192 @@
+package contract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrContractNotFound 编译产物中没有指定的合约
+var ErrContractNotFound = errors.New("编译产物中没有指定的合约")
+
+// Artifact 合约编译产物
+type Artifact struct {
+	Name     string
+	ABI      abi.ABI
+	Bytecode []byte // 创建字节码，只有 ABI 时为空
+}
+
+// LoadArtifact 读取 ABI 或编译产物文件，name 用于在包含多个合约的产物中选择合约
+//
+// 支持的格式：
+//   - ABI JSON 数组（solc --abi），同目录存在同名 .bin 文件时一并读取字节码
+//   - Hardhat / Truffle 产物：{"contractName", "abi", "bytecode": "0x..."}
+//   - Foundry 产物：{"abi", "bytecode": {"object": "0x..."}}
+//   - solc --combined-json abi,bin 输出：{"contracts": {"file.sol:Name": {"abi", "bin"}}}
+//   - solc 标准 JSON 输出：{"contracts": {"file.sol": {"Name": {"abi", "evm": {"bytecode": {"object"}}}}}}
+func LoadArtifact(path, name string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取合约文件失败: %w", err)
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	artifact, err := ParseArtifact(data, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// solc --abi --bin -o 输出的 ABI 与字节码是两个文件
+	if len(artifact.Bytecode) == 0 {
+		binPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".bin"
+		if bin, err := os.ReadFile(binPath); err == nil {
+			if artifact.Bytecode, err = decodeBytecode(string(bin)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return artifact, nil
+}
+
+// ParseArtifact 解析 ABI 或编译产物 JSON，格式见 LoadArtifact
+func ParseArtifact(data []byte, name string) (*Artifact, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		parsed, err := abi.JSON(strings.NewReader(trimmed))
+		if err != nil {
+			return nil, fmt.Errorf("解析 ABI 失败: %w", err)
+		}
+		return &Artifact{Name: name, ABI: parsed}, nil
+	}
+
+	var doc struct {
+		ContractName string                     `json:"contractName"`
+		Contracts    map[string]json.RawMessage `json:"contracts"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("解析合约文件失败: %w", err)
+	}
+
+	if doc.Contracts == nil {
+		if doc.ContractName != "" {
+			name = doc.ContractName
+		}
+		return parseContractJSON(data, name)
+	}
+	return parseSolcOutput(doc.Contracts, name)
+}
+
+// parseSolcOutput 从 solc 输出中选择合约：只有一个合约时直接使用，否则按名称匹配
+func parseSolcOutput(contracts map[string]json.RawMessage, name string) (*Artifact, error) {
+	candidates := make(map[string]json.RawMessage)
+	for key, raw := range contracts {
+		if strings.Contains(key, ":") {
+			// combined-json：键为 "file.sol:Name"
+			candidates[key[strings.LastIndex(key, ":")+1:]] = raw
+			continue
+		}
+
+		// 标准 JSON：先按源文件再按合约名嵌套
+		var byName map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &byName); err != nil {
+			return nil, fmt.Errorf("解析 solc 输出失败: %w", err)
+		}
+		for contractName, contractRaw := range byName {
+			candidates[contractName] = contractRaw
+		}
+	}
+
+	if raw, ok := candidates[name]; ok {
+		return parseContractJSON(raw, name)
+	}
+	if len(candidates) == 1 {
+		for only, raw := range candidates {
+			return parseContractJSON(raw, only)
+		}
+	}
+
+	names := make([]string, 0, len(candidates))
+	for n := range candidates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("%w: %q，可选: %v", ErrContractNotFound, name, names)
+}
+
+// parseContractJSON 解析单个合约对象，兼容各工具对 abi 与字节码字段的不同写法
+func parseContractJSON(data []byte, name string) (*Artifact, error) {
+	var obj struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode json.RawMessage `json:"bytecode"`
+		Bin      string          `json:"bin"`
+		EVM      struct {
+			Bytecode struct {
+				Object string `json:"object"`
+			} `json:"bytecode"`
+		} `json:"evm"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("解析合约 %s 失败: %w", name, err)
+	}
+	if len(obj.ABI) == 0 {
+		return nil, fmt.Errorf("合约 %s 缺少 abi 字段", name)
+	}
+
+	// 早期 solc 的 combined-json 中 abi 是字符串
+	abiJSON := obj.ABI
+	var abiString string
+	if json.Unmarshal(obj.ABI, &abiString) == nil {
+		abiJSON = json.RawMessage(abiString)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("解析合约 %s 的 ABI 失败: %w", name, err)
+	}
+
+	bytecode := obj.Bin
+	if bytecode == "" {
+		bytecode = obj.EVM.Bytecode.Object
+	}
+	if bytecode == "" && len(obj.Bytecode) > 0 {
+		// Hardhat 为字符串，Foundry 为 {"object": "0x..."}
+		var foundry struct {
+			Object string `json:"object"`
+		}
+		if json.Unmarshal(obj.Bytecode, &bytecode) != nil {
+			if err := json.Unmarshal(obj.Bytecode, &foundry); err != nil {
+				return nil, fmt.Errorf("解析合约 %s 的字节码失败: %w", name, err)
+			}
+			bytecode = foundry.Object
+		}
+	}
+
+	code, err := decodeBytecode(bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("解析合约 %s 的字节码失败: %w", name, err)
+	}
+	return &Artifact{Name: name, ABI: parsed, Bytecode: code}, nil
+}
+
+// decodeBytecode 解析十六进制字节码，0x 前缀可选
+func decodeBytecode(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0x" {
+		return nil, nil
+	}
+	if strings.Contains(s, "__") {
+		return nil, errors.New("字节码包含未链接的库占位符")
+	}
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	return hexutil.Decode(s)
+}
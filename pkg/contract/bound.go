@@ -0,0 +1,181 @@
+package contract
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+)
+
+// boundContract 合约绑定的公共部分：eth_call 读取与经交易管理器发送
+type boundContract struct {
+	Address common.Address
+	ABI     abi.ABI
+
+	backend ethclient.Backend
+	txMgr   *transaction.Manager
+}
+
+// newBoundContract 解析 ABI 并创建绑定
+func newBoundContract(address string, abiJSON string, backend ethclient.Backend, txMgr *transaction.Manager) (boundContract, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return boundContract{}, fmt.Errorf("解析 ABI 失败: %w", err)
+	}
+
+	return boundContract{
+		Address: common.HexToAddress(address),
+		ABI:     parsedABI,
+		backend: backend,
+		txMgr:   txMgr,
+	}, nil
+}
+
+// call 调用只读方法并解码返回值
+func (b *boundContract) call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	return b.callAt(ctx, ethclient.LatestBlock, method, args...)
+}
+
+// callAt 在指定区块的状态上调用只读方法并解码返回值
+func (b *boundContract) callAt(ctx context.Context, block ethclient.BlockSelector, method string, args ...interface{}) ([]interface{}, error) {
+	ret, err := b.rawCallAt(ctx, block, method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := b.ABI.Unpack(method, ret)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 返回值失败: %w", method, err)
+	}
+	return out, nil
+}
+
+// rawCall 调用只读方法并返回原始数据
+func (b *boundContract) rawCall(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	return b.rawCallAt(ctx, ethclient.LatestBlock, method, args...)
+}
+
+// rawCallAt 在指定区块的状态上调用只读方法并返回原始数据
+func (b *boundContract) rawCallAt(ctx context.Context, block ethclient.BlockSelector, method string, args ...interface{}) ([]byte, error) {
+	data, err := b.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码 %s 参数失败: %w", method, err)
+	}
+
+	ret, err := ethclient.CallAt(ctx, b.backend, ethereum.CallMsg{To: &b.Address, Data: data}, block)
+	if err != nil {
+		return nil, fmt.Errorf("调用 %s 失败: %w", method, err)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("调用 %s 无返回数据，地址可能不是合约", method)
+	}
+	return ret, nil
+}
+
+// callBigInt 调用返回 uint256 的方法
+func (b *boundContract) callBigInt(ctx context.Context, method string, args ...interface{}) (*big.Int, error) {
+	out, err := b.call(ctx, method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("解析 %s 返回值失败: %T", method, out[0])
+	}
+	return value, nil
+}
+
+// callString 调用返回字符串的方法，兼容返回 bytes32 的早期代币（如 MKR）
+func (b *boundContract) callString(ctx context.Context, method string) (string, error) {
+	ret, err := b.rawCall(ctx, method)
+	if err != nil {
+		return "", err
+	}
+
+	if len(ret) == 32 {
+		return string(bytes.TrimRight(ret, "\x00")), nil
+	}
+
+	out, err := b.ABI.Unpack(method, ret)
+	if err != nil {
+		return "", fmt.Errorf("解析 %s 返回值失败: %w", method, err)
+	}
+	return out[0].(string), nil
+}
+
+// callBool 调用返回 bool 的方法
+func (b *boundContract) callBool(ctx context.Context, method string, args ...interface{}) (bool, error) {
+	out, err := b.call(ctx, method, args...)
+	if err != nil {
+		return false, err
+	}
+
+	value, ok := out[0].(bool)
+	if !ok {
+		return false, fmt.Errorf("解析 %s 返回值失败: %T", method, out[0])
+	}
+	return value, nil
+}
+
+// callAddress 调用返回 address 的方法
+func (b *boundContract) callAddress(ctx context.Context, method string, args ...interface{}) (common.Address, error) {
+	out, err := b.call(ctx, method, args...)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	value, ok := out[0].(common.Address)
+	if !ok {
+		return common.Address{}, fmt.Errorf("解析 %s 返回值失败: %T", method, out[0])
+	}
+	return value, nil
+}
+
+// transact 编码参数、模拟执行后发送交易
+func (b *boundContract) transact(ctx context.Context, s signer.Signer, method string, args ...interface{}) (*types.Transaction, error) {
+	data, err := b.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码 %s 参数失败: %w", method, err)
+	}
+	if _, err := b.simulate(ctx, s.Address(), method, data); err != nil {
+		return nil, err
+	}
+	return b.send(ctx, s, method, data)
+}
+
+// simulate 以发送者身份 eth_call 执行，提前暴露 revert
+func (b *boundContract) simulate(ctx context.Context, from common.Address, method string, data []byte) ([]byte, error) {
+	if b.txMgr == nil {
+		return nil, fmt.Errorf("合约实例未配置交易管理器，无法发送 %s", method)
+	}
+
+	ret, err := b.backend.CallContract(ctx, ethereum.CallMsg{
+		From: from,
+		To:   &b.Address,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("模拟执行 %s 失败: %w", method, err)
+	}
+	return ret, nil
+}
+
+// send 构建、签名并发送交易
+func (b *boundContract) send(ctx context.Context, s signer.Signer, method string, data []byte) (*types.Transaction, error) {
+	tx, err := b.txMgr.Send(ctx, s, &b.Address, nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("发送 %s 交易失败: %w", method, err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,109 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+)
+
+// ErrDeployFailed 创建合约的交易未成功执行
+var ErrDeployFailed = errors.New("合约部署失败")
+
+// ReceiptWaiter 等待交易上链，pkg/ethclient.Client 实现了该接口
+type ReceiptWaiter interface {
+	WaitMined(ctx context.Context, txHash string, opts *ethclient.WaitOptions) (*ethclient.WaitResult, error)
+}
+
+// Deployment 部署结果
+type Deployment struct {
+	Address common.Address
+	Tx      *types.Transaction
+	Receipt *types.Receipt
+}
+
+// Deployer 部署编译好的合约
+type Deployer struct {
+	txMgr  *transaction.Manager
+	waiter ReceiptWaiter
+
+	// WaitOptions 等待回执的参数，为 nil 时使用默认值
+	WaitOptions *ethclient.WaitOptions
+}
+
+// NewDeployer 创建部署器
+func NewDeployer(txMgr *transaction.Manager, waiter ReceiptWaiter) *Deployer {
+	return &Deployer{txMgr: txMgr, waiter: waiter}
+}
+
+// Deploy 编码构造参数、估算 gas 并发送创建交易，等待回执后返回合约地址
+func (d *Deployer) Deploy(ctx context.Context, s signer.Signer, artifact *Artifact, args ...interface{}) (*Deployment, error) {
+	return d.DeployWithValue(ctx, s, artifact, nil, args...)
+}
+
+// DeployWithValue 同 Deploy，并向 payable 构造函数转入 value
+func (d *Deployer) DeployWithValue(ctx context.Context, s signer.Signer, artifact *Artifact, value *big.Int, args ...interface{}) (*Deployment, error) {
+	initCode, err := InitCode(artifact, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := d.txMgr.Send(ctx, s, nil, value, initCode)
+	if err != nil {
+		return nil, fmt.Errorf("发送 %s 创建交易失败: %w", artifact.Name, err)
+	}
+
+	result, err := d.waiter.WaitMined(ctx, tx.Hash().Hex(), d.WaitOptions)
+	if err != nil {
+		return nil, err
+	}
+	if result.Status != ethclient.TxSuccess {
+		return nil, fmt.Errorf("%w: %s 交易 %s 状态 %s", ErrDeployFailed, artifact.Name, tx.Hash().Hex(), result.Status)
+	}
+
+	return &Deployment{
+		Address: result.Receipt.ContractAddress,
+		Tx:      tx,
+		Receipt: result.Receipt,
+	}, nil
+}
+
+// InitCode 拼接创建字节码与 ABI 编码的构造参数
+func InitCode(artifact *Artifact, args ...interface{}) ([]byte, error) {
+	if len(artifact.Bytecode) == 0 {
+		return nil, fmt.Errorf("合约 %s 没有字节码（只有 ABI 的文件无法部署）", artifact.Name)
+	}
+
+	packed, err := artifact.ABI.Pack("", args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码 %s 构造参数失败: %w", artifact.Name, err)
+	}
+
+	initCode := make([]byte, 0, len(artifact.Bytecode)+len(packed))
+	initCode = append(initCode, artifact.Bytecode...)
+	return append(initCode, packed...), nil
+}
+
+// ParseConstructorArgs 将字符串参数按构造函数的参数类型转换
+func ParseConstructorArgs(artifact *Artifact, values []string) ([]interface{}, error) {
+	return ParseArgs(artifact.ABI.Constructor.Inputs, values)
+}
+
+// CreateAddress 预测 deployer 以指定 nonce 通过 CREATE 部署的合约地址
+func CreateAddress(deployer common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(deployer, nonce)
+}
+
+// Create2Address 预测工厂合约通过 CREATE2 部署的合约地址：
+// keccak256(0xff ++ factory ++ salt ++ keccak256(initCode))[12:]
+func Create2Address(factory common.Address, salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(factory, salt, crypto.Keccak256(initCode))
+}
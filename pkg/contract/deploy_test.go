@@ -0,0 +1,78 @@
+package contract_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/pkg/contract"
+)
+
+func TestCreateAddress(t *testing.T) {
+	deployer := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	if got := contract.CreateAddress(deployer, 0); got != common.HexToAddress("0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d") {
+		t.Errorf("nonce 0: %s", got.Hex())
+	}
+	if got := contract.CreateAddress(deployer, 1); got != common.HexToAddress("0x343c43a37d37dff08ae8c4a11544c718abb4fcf8") {
+		t.Errorf("nonce 1: %s", got.Hex())
+	}
+}
+
+func TestCreate2Address(t *testing.T) {
+	// EIP-1014 示例
+	tests := []struct {
+		factory  string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0x0000000000000000000000000000000000000000000000000000000000000000",
+			"0x00",
+			"0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			"0x00000000000000000000000000000000deadbeef",
+			"0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			"0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+	}
+	for _, tt := range tests {
+		got := contract.Create2Address(common.HexToAddress(tt.factory), common.HexToHash(tt.salt), common.FromHex(tt.initCode))
+		if got != common.HexToAddress(tt.want) {
+			t.Errorf("Create2Address = %s, want %s", got.Hex(), tt.want)
+		}
+	}
+}
+
+func TestInitCode(t *testing.T) {
+	artifact, err := contract.ParseArtifact([]byte(`{
+		"abi": [{"type": "constructor", "stateMutability": "nonpayable", "inputs": [{"name": "initialSupply", "type": "uint256"}]}],
+		"bytecode": "0x6080"
+	}`), "MyToken")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := contract.ParseConstructorArgs(artifact, []string{"1000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initCode, err := contract.InitCode(artifact, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := append(common.FromHex("0x6080"), common.LeftPadBytes(big.NewInt(1000000).Bytes(), 32)...)
+	if common.Bytes2Hex(initCode) != common.Bytes2Hex(want) {
+		t.Errorf("initCode = %x", initCode)
+	}
+
+	abiOnly, _ := contract.ParseArtifact([]byte(`[]`), "Empty")
+	if _, err := contract.InitCode(abiOnly); err == nil {
+		t.Error("没有字节码时应该报错")
+	}
+}
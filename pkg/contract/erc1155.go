@@ -0,0 +1,313 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+)
+
+// ERC1155 标准接口 ABI（含 Metadata URI 扩展）
+const ERC1155ABI = `
+[
+	{
+		"constant": true,
+		"inputs": [{"name": "interfaceId", "type": "bytes4"}],
+		"name": "supportsInterface",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_id", "type": "uint256"}
+		],
+		"name": "balanceOf",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owners", "type": "address[]"},
+			{"name": "_ids", "type": "uint256[]"}
+		],
+		"name": "balanceOfBatch",
+		"outputs": [{"name": "", "type": "uint256[]"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_id", "type": "uint256"}],
+		"name": "uri",
+		"outputs": [{"name": "", "type": "string"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_operator", "type": "address"}
+		],
+		"name": "isApprovedForAll",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_operator", "type": "address"},
+			{"name": "_approved", "type": "bool"}
+		],
+		"name": "setApprovalForAll",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_from", "type": "address"},
+			{"name": "_to", "type": "address"},
+			{"name": "_id", "type": "uint256"},
+			{"name": "_value", "type": "uint256"},
+			{"name": "_data", "type": "bytes"}
+		],
+		"name": "safeTransferFrom",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_from", "type": "address"},
+			{"name": "_to", "type": "address"},
+			{"name": "_ids", "type": "uint256[]"},
+			{"name": "_values", "type": "uint256[]"},
+			{"name": "_data", "type": "bytes"}
+		],
+		"name": "safeBatchTransferFrom",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "operator", "type": "address"},
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": false, "name": "id", "type": "uint256"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		],
+		"name": "TransferSingle",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "operator", "type": "address"},
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": false, "name": "ids", "type": "uint256[]"},
+			{"indexed": false, "name": "values", "type": "uint256[]"}
+		],
+		"name": "TransferBatch",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "operator", "type": "address"},
+			{"indexed": false, "name": "approved", "type": "bool"}
+		],
+		"name": "ApprovalForAll",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": false, "name": "value", "type": "string"},
+			{"indexed": true, "name": "id", "type": "uint256"}
+		],
+		"name": "URI",
+		"type": "event"
+	}
+]`
+
+// ERC1155TransferSingle TransferSingle 事件
+type ERC1155TransferSingle struct {
+	Operator common.Address
+	From     common.Address
+	To       common.Address
+	ID       *big.Int
+	Value    *big.Int
+	Raw      types.Log
+}
+
+// ERC1155TransferBatch TransferBatch 事件
+type ERC1155TransferBatch struct {
+	Operator common.Address
+	From     common.Address
+	To       common.Address
+	IDs      []*big.Int
+	Values   []*big.Int
+	Raw      types.Log
+}
+
+// ERC1155Contract ERC1155 合约封装
+type ERC1155Contract struct {
+	boundContract
+
+	erc165 erc165Cache
+}
+
+// NewERC1155Contract 创建 ERC1155 合约实例，txMgr 为 nil 时只能读取
+func NewERC1155Contract(address string, backend ethclient.Backend, txMgr *transaction.Manager) (*ERC1155Contract, error) {
+	bound, err := newBoundContract(address, ERC1155ABI, backend, txMgr)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC1155Contract{boundContract: bound}, nil
+}
+
+// SupportsInterface 通过 ERC165 检测接口，结果会被缓存
+func (c *ERC1155Contract) SupportsInterface(ctx context.Context, interfaceID [4]byte) (bool, error) {
+	return c.erc165.supports(ctx, c.backend, c.Address, interfaceID)
+}
+
+// BalanceOf 查询地址持有的某个代币数量
+func (c *ERC1155Contract) BalanceOf(ctx context.Context, owner common.Address, id *big.Int) (*big.Int, error) {
+	return c.callBigInt(ctx, "balanceOf", owner, id)
+}
+
+// BalanceOfBatch 批量查询余额，owners 与 ids 一一对应
+func (c *ERC1155Contract) BalanceOfBatch(ctx context.Context, owners []common.Address, ids []*big.Int) ([]*big.Int, error) {
+	if len(owners) != len(ids) {
+		return nil, fmt.Errorf("owners 与 ids 长度不一致: %d != %d", len(owners), len(ids))
+	}
+
+	out, err := c.call(ctx, "balanceOfBatch", owners, ids)
+	if err != nil {
+		return nil, err
+	}
+	balances, ok := out[0].([]*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("解析 balanceOfBatch 返回值失败: %T", out[0])
+	}
+	return balances, nil
+}
+
+// URI 查询代币元数据链接，并替换其中的 {id} 占位符
+func (c *ERC1155Contract) URI(ctx context.Context, id *big.Int) (string, error) {
+	out, err := c.call(ctx, "uri", id)
+	if err != nil {
+		return "", err
+	}
+	return ExpandURI(out[0].(string), id), nil
+}
+
+// IsApprovedForAll 查询 operator 是否获得 owner 的全部授权
+func (c *ERC1155Contract) IsApprovedForAll(ctx context.Context, owner, operator common.Address) (bool, error) {
+	return c.callBool(ctx, "isApprovedForAll", owner, operator)
+}
+
+// SetApprovalForAll 授权或撤销 operator 转移全部代币
+func (c *ERC1155Contract) SetApprovalForAll(ctx context.Context, s signer.Signer, operator common.Address, approved bool) (*types.Transaction, error) {
+	return c.transact(ctx, s, "setApprovalForAll", operator, approved)
+}
+
+// SafeTransferFrom 转移单个代币
+func (c *ERC1155Contract) SafeTransferFrom(ctx context.Context, s signer.Signer, from, to common.Address, id, amount *big.Int, data []byte) (*types.Transaction, error) {
+	if data == nil {
+		data = []byte{}
+	}
+	return c.transact(ctx, s, "safeTransferFrom", from, to, id, amount, data)
+}
+
+// SafeBatchTransferFrom 批量转移代币，ids 与 amounts 一一对应
+func (c *ERC1155Contract) SafeBatchTransferFrom(ctx context.Context, s signer.Signer, from, to common.Address, ids, amounts []*big.Int, data []byte) (*types.Transaction, error) {
+	if len(ids) != len(amounts) {
+		return nil, fmt.Errorf("ids 与 amounts 长度不一致: %d != %d", len(ids), len(amounts))
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return c.transact(ctx, s, "safeBatchTransferFrom", from, to, ids, amounts, data)
+}
+
+// ParseTransferSingle 解码 TransferSingle 事件
+func (c *ERC1155Contract) ParseTransferSingle(log types.Log) (*ERC1155TransferSingle, error) {
+	out, err := c.unpackTransfer("TransferSingle", log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ERC1155TransferSingle{
+		Operator: common.BytesToAddress(log.Topics[1].Bytes()),
+		From:     common.BytesToAddress(log.Topics[2].Bytes()),
+		To:       common.BytesToAddress(log.Topics[3].Bytes()),
+		ID:       out[0].(*big.Int),
+		Value:    out[1].(*big.Int),
+		Raw:      log,
+	}, nil
+}
+
+// ParseTransferBatch 解码 TransferBatch 事件
+func (c *ERC1155Contract) ParseTransferBatch(log types.Log) (*ERC1155TransferBatch, error) {
+	out, err := c.unpackTransfer("TransferBatch", log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ERC1155TransferBatch{
+		Operator: common.BytesToAddress(log.Topics[1].Bytes()),
+		From:     common.BytesToAddress(log.Topics[2].Bytes()),
+		To:       common.BytesToAddress(log.Topics[3].Bytes()),
+		IDs:      out[0].([]*big.Int),
+		Values:   out[1].([]*big.Int),
+		Raw:      log,
+	}, nil
+}
+
+// unpackTransfer 校验事件 topic 并解码非 indexed 参数
+func (c *ERC1155Contract) unpackTransfer(name string, log types.Log) ([]interface{}, error) {
+	event := c.ABI.Events[name]
+	if len(log.Topics) != 4 || log.Topics[0] != event.ID {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnexpectedEvent)
+	}
+
+	out, err := c.ABI.Unpack(name, log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 事件失败: %w", name, err)
+	}
+	return out, nil
+}
+
+// ExpandURI 按 ERC1155 规范把 {id} 替换为 64 位小写十六进制 tokenId（不带 0x）
+func ExpandURI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
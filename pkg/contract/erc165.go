@@ -0,0 +1,157 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ERC165 接口 ID
+var (
+	InterfaceERC165             = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	InterfaceERC721             = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceERC721Metadata     = [4]byte{0x5b, 0x5e, 0x13, 0x9f}
+	InterfaceERC721Enumerable   = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+	InterfaceERC1155            = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+	InterfaceERC1155MetadataURI = [4]byte{0x0e, 0x89, 0x34, 0x1c}
+
+	// interfaceInvalid 按 ERC165 规范必须返回 false
+	interfaceInvalid = [4]byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// ErrNotSupported 合约未实现所需接口
+var ErrNotSupported = errors.New("合约未实现该接口")
+
+// NFTStandard NFT 合约标准
+type NFTStandard int
+
+const (
+	// NFTUnknown 未实现 ERC721 或 ERC1155
+	NFTUnknown NFTStandard = iota
+	// NFTERC721 ERC721 合约
+	NFTERC721
+	// NFTERC1155 ERC1155 合约
+	NFTERC1155
+)
+
+// String 返回标准名称
+func (s NFTStandard) String() string {
+	switch s {
+	case NFTERC721:
+		return "ERC721"
+	case NFTERC1155:
+		return "ERC1155"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectNFTStandard 通过 ERC165 判断合约是 ERC721 还是 ERC1155
+func DetectNFTStandard(ctx context.Context, caller ethereum.ContractCaller, address common.Address) (NFTStandard, error) {
+	var cache erc165Cache
+
+	ok, err := cache.supports(ctx, caller, address, InterfaceERC721)
+	if err != nil {
+		return NFTUnknown, err
+	}
+	if ok {
+		return NFTERC721, nil
+	}
+
+	ok, err = cache.supports(ctx, caller, address, InterfaceERC1155)
+	if err != nil {
+		return NFTUnknown, err
+	}
+	if ok {
+		return NFTERC1155, nil
+	}
+	return NFTUnknown, nil
+}
+
+// SupportsInterface 按 ERC165 规范检测合约是否实现接口
+func SupportsInterface(ctx context.Context, caller ethereum.ContractCaller, address common.Address, interfaceID [4]byte) (bool, error) {
+	var cache erc165Cache
+	return cache.supports(ctx, caller, address, interfaceID)
+}
+
+// erc165Cache 缓存 supportsInterface 的查询结果，零值可用
+type erc165Cache struct {
+	mu      sync.Mutex
+	results map[[4]byte]bool
+}
+
+// supports 先确认合约实现了 ERC165（支持 0x01ffc9a7 且不支持 0xffffffff），再查询目标接口
+func (c *erc165Cache) supports(ctx context.Context, caller ethereum.ContractCaller, address common.Address, interfaceID [4]byte) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results == nil {
+		c.results = make(map[[4]byte]bool)
+	}
+	if ok, cached := c.results[interfaceID]; cached {
+		return ok, nil
+	}
+
+	erc165, cached := c.results[InterfaceERC165]
+	if !cached {
+		ok, err := querySupportsInterface(ctx, caller, address, InterfaceERC165)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			invalid, err := querySupportsInterface(ctx, caller, address, interfaceInvalid)
+			if err != nil {
+				return false, err
+			}
+			ok = !invalid
+		}
+		erc165 = ok
+		c.results[InterfaceERC165] = erc165
+	}
+	if !erc165 || interfaceID == InterfaceERC165 {
+		return erc165, nil
+	}
+
+	ok, err := querySupportsInterface(ctx, caller, address, interfaceID)
+	if err != nil {
+		return false, err
+	}
+	c.results[interfaceID] = ok
+	return ok, nil
+}
+
+// querySupportsInterface 调用 supportsInterface(bytes4)，revert 或返回格式不对视为不支持
+func querySupportsInterface(ctx context.Context, caller ethereum.ContractCaller, address common.Address, interfaceID [4]byte) (bool, error) {
+	// supportsInterface 的选择器恰好就是 ERC165 接口 ID，bytes4 参数左对齐
+	data := make([]byte, 4+32)
+	copy(data, InterfaceERC165[:])
+	copy(data[4:], interfaceID[:])
+
+	ret, err := caller.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if err != nil {
+		if isRevert(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("调用 supportsInterface 失败: %w", err)
+	}
+	if len(ret) < 32 {
+		return false, nil
+	}
+
+	for _, b := range ret[:31] {
+		if b != 0 {
+			return false, nil
+		}
+	}
+	return ret[31] == 1, nil
+}
+
+// isRevert 判断调用错误是否为合约执行 revert
+func isRevert(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "revert")
+}
@@ -1,13 +1,24 @@
+// Package contract 提供智能合约绑定
 package contract
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
 )
 
-// ERC20 标准接口 ABI（简化版）
+// ERC20 标准接口 ABI
 const ERC20ABI = `[
 	{
 		"constant": true,
@@ -36,6 +47,15 @@ const ERC20ABI = `[
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "totalSupply",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"constant": true,
 		"inputs": [{"name": "_owner", "type": "address"}],
@@ -45,6 +65,18 @@ const ERC20ABI = `[
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_spender", "type": "address"}
+		],
+		"name": "allowance",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"constant": false,
 		"inputs": [
@@ -57,6 +89,31 @@ const ERC20ABI = `[
 		"stateMutability": "nonpayable",
 		"type": "function"
 	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_spender", "type": "address"},
+			{"name": "_value", "type": "uint256"}
+		],
+		"name": "approve",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_from", "type": "address"},
+			{"name": "_to", "type": "address"},
+			{"name": "_value", "type": "uint256"}
+		],
+		"name": "transferFrom",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
 	{
 		"anonymous": false,
 		"inputs": [
@@ -66,6 +123,16 @@ const ERC20ABI = `[
 		],
 		"name": "Transfer",
 		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "spender", "type": "address"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		],
+		"name": "Approval",
+		"type": "event"
 	}
 ]`
 
@@ -74,22 +141,166 @@ func ParseERC20ABI() (abi.ABI, error) {
 	return abi.JSON(strings.NewReader(ERC20ABI))
 }
 
+// ErrTokenOperationFailed 代币合约返回 false
+var ErrTokenOperationFailed = errors.New("代币合约返回 false")
+
+// TokenMetadata 代币元数据
+type TokenMetadata struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
 // ERC20Contract ERC20 合约封装
 type ERC20Contract struct {
-	Address common.Address
-	ABI     abi.ABI
-	// Client  *ethclient.Client // 实际使用时添加
+	boundContract
+
+	mu       sync.Mutex
+	metadata *TokenMetadata
 }
 
-// NewERC20Contract 创建 ERC20 合约实例
-func NewERC20Contract(address string) (*ERC20Contract, error) {
-	parsedABI, err := ParseERC20ABI()
+// NewERC20Contract 创建 ERC20 合约实例，txMgr 为 nil 时只能读取
+func NewERC20Contract(address string, backend ethclient.Backend, txMgr *transaction.Manager) (*ERC20Contract, error) {
+	bound, err := newBoundContract(address, ERC20ABI, backend, txMgr)
 	if err != nil {
 		return nil, err
 	}
+	return &ERC20Contract{boundContract: bound}, nil
+}
+
+// Metadata 查询名称、符号和精度，首次读取后缓存
+func (c *ERC20Contract) Metadata(ctx context.Context) (*TokenMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	name, err := c.callString(ctx, "name")
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := c.callString(ctx, "symbol")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.call(ctx, "decimals")
+	if err != nil {
+		return nil, err
+	}
+	decimals, ok := out[0].(uint8)
+	if !ok {
+		return nil, fmt.Errorf("解析 decimals 失败: %T", out[0])
+	}
+
+	c.metadata = &TokenMetadata{Name: name, Symbol: symbol, Decimals: decimals}
+	return c.metadata, nil
+}
+
+// Name 代币名称
+func (c *ERC20Contract) Name(ctx context.Context) (string, error) {
+	meta, err := c.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	return meta.Name, nil
+}
+
+// Symbol 代币符号
+func (c *ERC20Contract) Symbol(ctx context.Context) (string, error) {
+	meta, err := c.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	return meta.Symbol, nil
+}
+
+// Decimals 代币精度
+func (c *ERC20Contract) Decimals(ctx context.Context) (uint8, error) {
+	meta, err := c.Metadata(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return meta.Decimals, nil
+}
+
+// TotalSupply 总发行量（最小单位）
+func (c *ERC20Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
+	return c.callBigInt(ctx, "totalSupply")
+}
+
+// BalanceOf 查询地址余额（最小单位）
+func (c *ERC20Contract) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	return c.callBigInt(ctx, "balanceOf", owner)
+}
+
+// BalanceOfAt 查询指定区块的地址余额（最小单位），用于快照、对账等历史查询
+func (c *ERC20Contract) BalanceOfAt(ctx context.Context, owner common.Address, block ethclient.BlockSelector) (*big.Int, error) {
+	out, err := c.callAt(ctx, block, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := out[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("解析 balanceOf 返回值失败: %T", out[0])
+	}
+	return value, nil
+}
+
+// Allowance 查询 owner 授权给 spender 的额度
+func (c *ERC20Contract) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+	return c.callBigInt(ctx, "allowance", owner, spender)
+}
+
+// Transfer 转账代币
+func (c *ERC20Contract) Transfer(ctx context.Context, s signer.Signer, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, s, "transfer", to, amount)
+}
+
+// Approve 授权 spender 使用代币
+func (c *ERC20Contract) Approve(ctx context.Context, s signer.Signer, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, s, "approve", spender, amount)
+}
+
+// TransferFrom 使用授权额度从 from 转账到 to
+func (c *ERC20Contract) TransferFrom(ctx context.Context, s signer.Signer, from, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, s, "transferFrom", from, to, amount)
+}
+
+// transact 先以 eth_call 模拟执行，再构建、签名并发送交易
+//
+// 兼容不返回 bool 的非标准代币（如 USDT）：返回数据为空视为成功
+func (c *ERC20Contract) transact(ctx context.Context, s signer.Signer, method string, args ...interface{}) (*types.Transaction, error) {
+	data, err := c.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("编码 %s 参数失败: %w", method, err)
+	}
+
+	ret, err := c.simulate(ctx, s.Address(), method, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) > 0 {
+		out, err := c.ABI.Unpack(method, ret)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 返回值失败: %w", method, err)
+		}
+		if ok, _ := out[0].(bool); !ok {
+			return nil, fmt.Errorf("%s: %w", method, ErrTokenOperationFailed)
+		}
+	} else {
+		// 不返回值的代币（如 USDT）调用成功时也没有返回数据，但对没有代码的地址调用同样如此
+		code, err := c.backend.CodeAt(ctx, c.Address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("查询合约代码失败: %w", err)
+		}
+		if len(code) == 0 {
+			return nil, fmt.Errorf("调用 %s 无返回数据，%s 不是合约", method, c.Address.Hex())
+		}
+	}
 
-	return &ERC20Contract{
-		Address: common.HexToAddress(address),
-		ABI:     parsedABI,
-	}, nil
+	return c.send(ctx, s, method, data)
 }
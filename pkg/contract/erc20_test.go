@@ -0,0 +1,247 @@
+package contract_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+)
+
+// fakeCaller 按方法选择器返回预置数据，并统计调用次数；只实现 CallContract
+type fakeCaller struct {
+	ethclient.Backend
+
+	abi     abi.ABI
+	results map[string][]byte
+	calls   map[string]int
+}
+
+func (f *fakeCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	method, err := f.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	f.calls[method.Name]++
+	return f.results[method.Name], nil
+}
+
+func newFakeCaller(t *testing.T) *fakeCaller {
+	parsed, err := contract.ParseERC20ABI()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pack := func(method string, values ...interface{}) []byte {
+		data, err := parsed.Methods[method].Outputs.Pack(values...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	// 早期代币的 name/symbol 返回 bytes32
+	var symbol [32]byte
+	copy(symbol[:], "MKR")
+
+	return &fakeCaller{
+		abi: parsed,
+		results: map[string][]byte{
+			"name":        pack("name", "Maker"),
+			"symbol":      symbol[:],
+			"decimals":    pack("decimals", uint8(18)),
+			"totalSupply": pack("totalSupply", big.NewInt(1000)),
+		},
+		calls: make(map[string]int),
+	}
+}
+
+func TestERC20Metadata(t *testing.T) {
+	caller := newFakeCaller(t)
+	token, err := contract.NewERC20Contract("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2", caller, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	meta, err := token.Metadata(ctx)
+	if err != nil {
+		t.Fatalf("查询元数据失败: %v", err)
+	}
+	if meta.Name != "Maker" || meta.Symbol != "MKR" || meta.Decimals != 18 {
+		t.Errorf("元数据 = %+v", meta)
+	}
+
+	// 再次读取应命中缓存
+	if _, err := token.Symbol(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if caller.calls["symbol"] != 1 {
+		t.Errorf("symbol 调用次数 = %d, want 1", caller.calls["symbol"])
+	}
+
+	supply, err := token.TotalSupply(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supply.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("总发行量 = %s, want 1000", supply)
+	}
+}
+
+func TestERC20CallEmptyResult(t *testing.T) {
+	caller := newFakeCaller(t)
+	token, _ := contract.NewERC20Contract("0x0000000000000000000000000000000000000001", caller, nil)
+
+	// 非合约地址返回空数据
+	if _, err := token.BalanceOf(context.Background(), common.Address{}); err == nil {
+		t.Error("空返回数据应该报错")
+	}
+}
+
+// 非标准代币的运行时代码，任何调用都按下面的方式处理
+var (
+	// noReturnToken 像 USDT 一样不返回数据：记录调用数据的第一个字（含选择器）到槽 0、最后一个字（金额）到槽 1
+	//
+	//	PUSH1 0 CALLDATALOAD PUSH1 0 SSTORE
+	//	PUSH1 0x20 CALLDATASIZE SUB CALLDATALOAD PUSH1 1 SSTORE
+	//	STOP
+	noReturnToken = common.FromHex("0x600035600055602036033560015500")
+	// falseToken 总是返回 ABI 编码的 false
+	//
+	//	PUSH1 0x20 PUSH1 0 RETURN
+	falseToken = common.FromHex("0x60206000f3")
+)
+
+// deployToken 部署运行时代码为 runtime 的合约，用 ERC20 ABI 绑定
+func deployToken(t *testing.T, chain *testchain.Chain, runtime []byte) *contract.ERC20Contract {
+	t.Helper()
+	parsed, err := contract.ParseERC20ABI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// PUSH1 len DUP1 PUSH1 11 PUSH1 0 CODECOPY PUSH1 0 RETURN <runtime>
+	code := append([]byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, runtime...)
+	deployment := chain.Deploy(chain.Accounts[0], &contract.Artifact{Name: "Token", ABI: parsed, Bytecode: code})
+
+	token, err := contract.NewERC20Contract(deployment.Address.Hex(), chain.Eth(), chain.TxManager())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestERC20TransactNoReturnData(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	token := deployToken(t, chain, noReturnToken)
+
+	tests := []struct {
+		method string
+		send   func() error
+		amount int64
+	}{
+		{"transfer", func() error {
+			_, err := token.Transfer(ctx, alice.Signer, bob.Address, big.NewInt(1))
+			return err
+		}, 1},
+		{"approve", func() error {
+			_, err := token.Approve(ctx, alice.Signer, bob.Address, big.NewInt(2))
+			return err
+		}, 2},
+		{"transferFrom", func() error {
+			_, err := token.TransferFrom(ctx, bob.Signer, alice.Address, bob.Address, big.NewInt(3))
+			return err
+		}, 3},
+	}
+	for _, tt := range tests {
+		// 返回数据为空视为成功，交易发送并上链
+		if err := tt.send(); err != nil {
+			t.Fatalf("%s 失败: %v", tt.method, err)
+		}
+
+		slot0, err := chain.Eth().StorageAt(ctx, token.Address, common.Hash{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selector := token.ABI.Methods[tt.method].ID; !bytes.Equal(slot0[:4], selector) {
+			t.Errorf("%s: 合约收到的选择器 = %x, want %x", tt.method, slot0[:4], selector)
+		}
+		slot1, err := chain.Eth().StorageAt(ctx, token.Address, common.BigToHash(big.NewInt(1)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if amount := new(big.Int).SetBytes(slot1); amount.Int64() != tt.amount {
+			t.Errorf("%s: 合约收到的金额 = %s, want %d", tt.method, amount, tt.amount)
+		}
+	}
+}
+
+func TestERC20TransactReturnsFalse(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	token := deployToken(t, chain, falseToken)
+
+	nonce, err := chain.Eth().PendingNonceAt(ctx, alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = token.Transfer(ctx, alice.Signer, bob.Address, big.NewInt(1))
+	if !errors.Is(err, contract.ErrTokenOperationFailed) {
+		t.Errorf("transfer 错误 = %v, want ErrTokenOperationFailed", err)
+	}
+	_, err = token.Approve(ctx, alice.Signer, bob.Address, big.NewInt(1))
+	if !errors.Is(err, contract.ErrTokenOperationFailed) {
+		t.Errorf("approve 错误 = %v, want ErrTokenOperationFailed", err)
+	}
+	_, err = token.TransferFrom(ctx, alice.Signer, bob.Address, alice.Address, big.NewInt(1))
+	if !errors.Is(err, contract.ErrTokenOperationFailed) {
+		t.Errorf("transferFrom 错误 = %v, want ErrTokenOperationFailed", err)
+	}
+
+	// 模拟执行返回 false 时不发送交易，nonce 没有被占用
+	if after, err := chain.Eth().PendingNonceAt(ctx, alice.Address); err != nil || after != nonce {
+		t.Errorf("nonce = %d, want %d (err=%v)", after, nonce, err)
+	}
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &bob.Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != nonce {
+		t.Errorf("下一笔交易 nonce = %d, want %d", tx.Nonce(), nonce)
+	}
+}
+
+func TestERC20TransactNotContract(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	// 绑定到普通账户，eth_call 同样没有返回数据
+	token, err := contract.NewERC20Contract(bob.Address.Hex(), chain.Eth(), chain.TxManager())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := chain.Eth().PendingNonceAt(ctx, alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := token.Transfer(ctx, alice.Signer, bob.Address, big.NewInt(1)); err == nil {
+		t.Error("向非合约地址发送 transfer 应该报错")
+	}
+	if after, err := chain.Eth().PendingNonceAt(ctx, alice.Address); err != nil || after != nonce {
+		t.Errorf("nonce = %d, want %d (err=%v)", after, nonce, err)
+	}
+}
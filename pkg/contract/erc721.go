@@ -0,0 +1,384 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+)
+
+// ERC721 标准接口 ABI（含 Metadata 与 Enumerable 扩展）
+const ERC721ABI = `
+[
+	{
+		"constant": true,
+		"inputs": [{"name": "interfaceId", "type": "bytes4"}],
+		"name": "supportsInterface",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "name",
+		"outputs": [{"name": "", "type": "string"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [{"name": "", "type": "string"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_tokenId", "type": "uint256"}],
+		"name": "tokenURI",
+		"outputs": [{"name": "", "type": "string"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_owner", "type": "address"}],
+		"name": "balanceOf",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_tokenId", "type": "uint256"}],
+		"name": "ownerOf",
+		"outputs": [{"name": "", "type": "address"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_tokenId", "type": "uint256"}],
+		"name": "getApproved",
+		"outputs": [{"name": "", "type": "address"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_operator", "type": "address"}
+		],
+		"name": "isApprovedForAll",
+		"outputs": [{"name": "", "type": "bool"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "totalSupply",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [{"name": "_index", "type": "uint256"}],
+		"name": "tokenByIndex",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_index", "type": "uint256"}
+		],
+		"name": "tokenOfOwnerByIndex",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"payable": false,
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_approved", "type": "address"},
+			{"name": "_tokenId", "type": "uint256"}
+		],
+		"name": "approve",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_operator", "type": "address"},
+			{"name": "_approved", "type": "bool"}
+		],
+		"name": "setApprovalForAll",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_from", "type": "address"},
+			{"name": "_to", "type": "address"},
+			{"name": "_tokenId", "type": "uint256"}
+		],
+		"name": "transferFrom",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_from", "type": "address"},
+			{"name": "_to", "type": "address"},
+			{"name": "_tokenId", "type": "uint256"},
+			{"name": "data", "type": "bytes"}
+		],
+		"name": "safeTransferFrom",
+		"outputs": [],
+		"payable": false,
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": true, "name": "tokenId", "type": "uint256"}
+		],
+		"name": "Transfer",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "approved", "type": "address"},
+			{"indexed": true, "name": "tokenId", "type": "uint256"}
+		],
+		"name": "Approval",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "operator", "type": "address"},
+			{"indexed": false, "name": "approved", "type": "bool"}
+		],
+		"name": "ApprovalForAll",
+		"type": "event"
+	}
+]`
+
+// ErrUnexpectedEvent 日志不是期望的事件
+var ErrUnexpectedEvent = errors.New("日志与事件不匹配")
+
+// ERC721Transfer Transfer 事件
+type ERC721Transfer struct {
+	From    common.Address
+	To      common.Address
+	TokenID *big.Int
+	Raw     types.Log
+}
+
+// ERC721Contract ERC721 合约封装
+type ERC721Contract struct {
+	boundContract
+
+	erc165 erc165Cache
+}
+
+// NewERC721Contract 创建 ERC721 合约实例，txMgr 为 nil 时只能读取
+func NewERC721Contract(address string, backend ethclient.Backend, txMgr *transaction.Manager) (*ERC721Contract, error) {
+	bound, err := newBoundContract(address, ERC721ABI, backend, txMgr)
+	if err != nil {
+		return nil, err
+	}
+	return &ERC721Contract{boundContract: bound}, nil
+}
+
+// SupportsInterface 通过 ERC165 检测接口，结果会被缓存
+func (c *ERC721Contract) SupportsInterface(ctx context.Context, interfaceID [4]byte) (bool, error) {
+	return c.erc165.supports(ctx, c.backend, c.Address, interfaceID)
+}
+
+// Name 集合名称
+func (c *ERC721Contract) Name(ctx context.Context) (string, error) {
+	return c.callString(ctx, "name")
+}
+
+// Symbol 集合符号
+func (c *ERC721Contract) Symbol(ctx context.Context) (string, error) {
+	return c.callString(ctx, "symbol")
+}
+
+// TokenURI NFT 元数据链接
+func (c *ERC721Contract) TokenURI(ctx context.Context, tokenID *big.Int) (string, error) {
+	out, err := c.call(ctx, "tokenURI", tokenID)
+	if err != nil {
+		return "", err
+	}
+	return out[0].(string), nil
+}
+
+// BalanceOf 查询地址持有的 NFT 数量
+func (c *ERC721Contract) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	return c.callBigInt(ctx, "balanceOf", owner)
+}
+
+// OwnerOf 查询 NFT 所有者
+func (c *ERC721Contract) OwnerOf(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+	return c.callAddress(ctx, "ownerOf", tokenID)
+}
+
+// GetApproved 查询单个 NFT 的授权地址
+func (c *ERC721Contract) GetApproved(ctx context.Context, tokenID *big.Int) (common.Address, error) {
+	return c.callAddress(ctx, "getApproved", tokenID)
+}
+
+// IsApprovedForAll 查询 operator 是否获得 owner 的全部授权
+func (c *ERC721Contract) IsApprovedForAll(ctx context.Context, owner, operator common.Address) (bool, error) {
+	return c.callBool(ctx, "isApprovedForAll", owner, operator)
+}
+
+// TotalSupply 总发行量，需要 ERC721Enumerable
+func (c *ERC721Contract) TotalSupply(ctx context.Context) (*big.Int, error) {
+	if err := c.requireEnumerable(ctx); err != nil {
+		return nil, err
+	}
+	return c.callBigInt(ctx, "totalSupply")
+}
+
+// TokenByIndex 按全局索引查询 tokenId，需要 ERC721Enumerable
+func (c *ERC721Contract) TokenByIndex(ctx context.Context, index *big.Int) (*big.Int, error) {
+	if err := c.requireEnumerable(ctx); err != nil {
+		return nil, err
+	}
+	return c.callBigInt(ctx, "tokenByIndex", index)
+}
+
+// TokenOfOwnerByIndex 按持有者索引查询 tokenId，需要 ERC721Enumerable
+func (c *ERC721Contract) TokenOfOwnerByIndex(ctx context.Context, owner common.Address, index *big.Int) (*big.Int, error) {
+	if err := c.requireEnumerable(ctx); err != nil {
+		return nil, err
+	}
+	return c.callBigInt(ctx, "tokenOfOwnerByIndex", owner, index)
+}
+
+// MaxTokensOfOwner TokensOfOwner 逐个查询的 tokenId 数量上限，持有更多时应分页调用 TokenOfOwnerByIndex
+const MaxTokensOfOwner = 10000
+
+// TokensOfOwner 列出地址持有的全部 tokenId，需要 ERC721Enumerable
+func (c *ERC721Contract) TokensOfOwner(ctx context.Context, owner common.Address) ([]*big.Int, error) {
+	if err := c.requireEnumerable(ctx); err != nil {
+		return nil, err
+	}
+
+	balance, err := c.BalanceOf(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	// 余额由合约返回，不可信，异常值会导致超大内存分配或几乎无穷的 RPC 调用
+	if !balance.IsInt64() || balance.Int64() > MaxTokensOfOwner {
+		return nil, fmt.Errorf("持有的 NFT 数量 %s 超过上限 %d", balance, MaxTokensOfOwner)
+	}
+	count := balance.Int64()
+
+	tokens := make([]*big.Int, 0, count)
+	for i := int64(0); i < count; i++ {
+		tokenID, err := c.TokenOfOwnerByIndex(ctx, owner, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tokenID)
+	}
+	return tokens, nil
+}
+
+// Approve 授权 to 转移单个 NFT
+func (c *ERC721Contract) Approve(ctx context.Context, s signer.Signer, to common.Address, tokenID *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, s, "approve", to, tokenID)
+}
+
+// SetApprovalForAll 授权或撤销 operator 转移全部 NFT
+func (c *ERC721Contract) SetApprovalForAll(ctx context.Context, s signer.Signer, operator common.Address, approved bool) (*types.Transaction, error) {
+	return c.transact(ctx, s, "setApprovalForAll", operator, approved)
+}
+
+// TransferFrom 转移 NFT，不检查接收方能否处理 NFT
+func (c *ERC721Contract) TransferFrom(ctx context.Context, s signer.Signer, from, to common.Address, tokenID *big.Int) (*types.Transaction, error) {
+	return c.transact(ctx, s, "transferFrom", from, to, tokenID)
+}
+
+// SafeTransferFrom 安全转移 NFT，接收方为合约时要求实现 onERC721Received
+func (c *ERC721Contract) SafeTransferFrom(ctx context.Context, s signer.Signer, from, to common.Address, tokenID *big.Int, data []byte) (*types.Transaction, error) {
+	if data == nil {
+		data = []byte{}
+	}
+	return c.transact(ctx, s, "safeTransferFrom", from, to, tokenID, data)
+}
+
+// ParseTransfer 解码 Transfer 事件
+//
+// ERC20 与 ERC721 的 Transfer 事件签名相同，ERC721 的 tokenId 为 indexed，日志有 4 个 topic
+func (c *ERC721Contract) ParseTransfer(log types.Log) (*ERC721Transfer, error) {
+	event := c.ABI.Events["Transfer"]
+	if len(log.Topics) != 4 || log.Topics[0] != event.ID {
+		return nil, fmt.Errorf("Transfer: %w", ErrUnexpectedEvent)
+	}
+
+	return &ERC721Transfer{
+		From:    common.BytesToAddress(log.Topics[1].Bytes()),
+		To:      common.BytesToAddress(log.Topics[2].Bytes()),
+		TokenID: new(big.Int).SetBytes(log.Topics[3].Bytes()),
+		Raw:     log,
+	}, nil
+}
+
+// requireEnumerable 确认合约实现了 ERC721Enumerable
+func (c *ERC721Contract) requireEnumerable(ctx context.Context) error {
+	ok, err := c.SupportsInterface(ctx, InterfaceERC721Enumerable)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("ERC721Enumerable: %w", ErrNotSupported)
+	}
+	return nil
+}
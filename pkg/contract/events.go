@@ -0,0 +1,278 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventArg 解码后的一个事件参数
+type EventArg struct {
+	Name    string      // 参数名，ABI 中未命名时为 arg0、arg1…
+	Type    string      // ABI 类型，如 uint256、string、(address,uint256)[]
+	Indexed bool        // 参数来自 topic
+	Hashed  bool        // indexed 的动态类型（string、bytes、数组、元组）在 topic 中只保存 keccak256 哈希
+	Value   interface{} // ABI 解码得到的 Go 值，Hashed 为 true 时为 common.Hash
+}
+
+// EventArgs 按 ABI 定义顺序排列的事件参数，JSON 序列化为保持参数顺序的对象
+type EventArgs []EventArg
+
+// Get 按参数名查找参数值
+func (a EventArgs) Get(name string) (interface{}, bool) {
+	for _, arg := range a {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return nil, false
+}
+
+// String 格式化为单行文本，如 from=0x… to=0x… value=100
+func (a EventArgs) String() string {
+	parts := make([]string, len(a))
+	for i, arg := range a {
+		parts[i] = arg.Name + "=" + FormatString(arg.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// MarshalJSON 按参数顺序输出 {"名称": 值}，值的格式同 FormatValue
+func (a EventArgs) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, arg := range a {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(arg.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(FormatValue(arg.Value))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 解析 MarshalJSON 的输出，供接收方使用；只能恢复参数名和格式化后的值，没有类型信息
+func (a *EventArgs) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("事件参数应为 JSON 对象")
+	}
+	var args EventArgs
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		args = append(args, EventArg{Name: tok.(string), Value: value})
+	}
+	*a = args
+	return nil
+}
+
+// DecodedEvent 解码后的日志，没有匹配的 ABI 时 Name 为空，只能使用 Log 中的原始 topic 和 data
+type DecodedEvent struct {
+	Name      string    // 事件名，重载事件也是 ABI 中的原名
+	Signature string    // 事件签名，如 Transfer(address,address,uint256)
+	Anonymous bool      // 匿名事件没有签名 topic，按 topic 数量和数据长度匹配
+	Args      EventArgs // 参数，indexed 与非 indexed 参数按 ABI 定义顺序合并
+	Log       types.Log
+}
+
+// Known 是否匹配到了 ABI 中的事件
+func (e *DecodedEvent) Known() bool {
+	return e.Name != ""
+}
+
+// EventDecoder 用一组 ABI 解码任意合约的日志
+//
+// 同一签名可能对应不同的 indexed 位置（如 ERC20 与 ERC721 的 Transfer），按 topic 数量区分；
+// 仍有多个候选时依次尝试，使用第一个能解码数据的事件
+type EventDecoder struct {
+	events    map[common.Hash][]abi.Event
+	anonymous []abi.Event
+	all       []abi.Event // 按加入顺序
+	seen      map[string]bool
+}
+
+// NewEventDecoder 创建事件解码器
+func NewEventDecoder(abis ...abi.ABI) *EventDecoder {
+	d := &EventDecoder{
+		events: make(map[common.Hash][]abi.Event),
+		seen:   make(map[string]bool),
+	}
+	for _, parsed := range abis {
+		d.Add(parsed)
+	}
+	return d
+}
+
+// StandardEventDecoder 创建已包含 ERC20、ERC721 和 ERC1155 事件的解码器
+func StandardEventDecoder() (*EventDecoder, error) {
+	d := NewEventDecoder()
+	for _, abiJSON := range []string{ERC20ABI, ERC721ABI, ERC1155ABI} {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("解析 ABI 失败: %w", err)
+		}
+		d.Add(parsed)
+	}
+	return d, nil
+}
+
+// Add 加入一个 ABI 中的全部事件，签名和 indexed 位置都相同的事件只保留先加入的
+func (d *EventDecoder) Add(parsed abi.ABI) {
+	names := make([]string, 0, len(parsed.Events))
+	for name := range parsed.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		event := parsed.Events[name]
+		key := eventKey(event)
+		if d.seen[key] {
+			continue
+		}
+		d.seen[key] = true
+		d.all = append(d.all, event)
+
+		if event.Anonymous {
+			d.anonymous = append(d.anonymous, event)
+		} else {
+			d.events[event.ID] = append(d.events[event.ID], event)
+		}
+	}
+}
+
+// Lookup 按事件名或完整签名（如 "Transfer(address,address,uint256)"）查找已加入的事件
+func (d *EventDecoder) Lookup(name string) []abi.Event {
+	sig := strings.ReplaceAll(name, " ", "")
+	var found []abi.Event
+	for _, event := range d.all {
+		if event.RawName == name || event.Sig == sig {
+			found = append(found, event)
+		}
+	}
+	return found
+}
+
+// eventKey 区分签名相同但 indexed 位置不同的事件
+func eventKey(event abi.Event) string {
+	var b strings.Builder
+	b.WriteString(event.Sig)
+	for _, in := range event.Inputs {
+		if in.Indexed {
+			b.WriteByte('i')
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	if event.Anonymous {
+		b.WriteString("anonymous")
+	}
+	return b.String()
+}
+
+// Decode 解码日志，先按签名 topic 匹配普通事件，再尝试匿名事件；都不匹配时返回只包含原始日志的结果
+func (d *EventDecoder) Decode(log types.Log) *DecodedEvent {
+	if len(log.Topics) > 0 {
+		for _, event := range d.events[log.Topics[0]] {
+			if args, err := decodeEvent(event, log.Topics[1:], log.Data); err == nil {
+				return newDecodedEvent(event, args, log)
+			}
+		}
+	}
+	for _, event := range d.anonymous {
+		if args, err := decodeEvent(event, log.Topics, log.Data); err == nil {
+			return newDecodedEvent(event, args, log)
+		}
+	}
+	return &DecodedEvent{Log: log}
+}
+
+func newDecodedEvent(event abi.Event, args EventArgs, log types.Log) *DecodedEvent {
+	return &DecodedEvent{
+		Name:      event.RawName,
+		Signature: event.Sig,
+		Anonymous: event.Anonymous,
+		Args:      args,
+		Log:       log,
+	}
+}
+
+// decodeEvent 按事件定义解码 indexed 参数的 topic 和非 indexed 参数的 data，topics 不含签名 topic
+func decodeEvent(event abi.Event, topics []common.Hash, data []byte) (EventArgs, error) {
+	var indexed int
+	for _, in := range event.Inputs {
+		if in.Indexed {
+			indexed++
+		}
+	}
+	if len(topics) != indexed {
+		return nil, fmt.Errorf("%s 需要 %d 个 indexed topic，实际 %d 个", event.Sig, indexed, len(topics))
+	}
+
+	values, err := event.Inputs.NonIndexed().UnpackValues(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 数据失败: %w", event.Sig, err)
+	}
+
+	args := make(EventArgs, len(event.Inputs))
+	for i, in := range event.Inputs {
+		arg := EventArg{Name: in.Name, Type: in.Type.String(), Indexed: in.Indexed}
+		if arg.Name == "" {
+			arg.Name = fmt.Sprintf("arg%d", i)
+		}
+
+		if in.Indexed {
+			topic := topics[0]
+			topics = topics[1:]
+			arg.Value, arg.Hashed, err = decodeTopic(in.Type, topic)
+			if err != nil {
+				return nil, fmt.Errorf("解析 %s 参数 %s 失败: %w", event.Sig, arg.Name, err)
+			}
+		} else {
+			arg.Value = values[0]
+			values = values[1:]
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// decodeTopic 解码 indexed 参数：静态类型按 32 字节 ABI 编码解码，动态类型只能返回哈希
+func decodeTopic(t abi.Type, topic common.Hash) (interface{}, bool, error) {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic, true, nil
+	}
+
+	values, err := abi.Arguments{{Type: t}}.UnpackValues(topic.Bytes())
+	if err != nil {
+		return nil, false, err
+	}
+	return values[0], false, nil
+}
@@ -0,0 +1,110 @@
+package contract_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"go-eth-learning/pkg/contract"
+)
+
+const eventsABI = `[
+	{"type": "event", "name": "Registered", "inputs": [
+		{"name": "label", "type": "string", "indexed": true},
+		{"name": "note", "type": "string", "indexed": false},
+		{"name": "owner", "type": "address", "indexed": true},
+		{"name": "amount", "type": "uint256", "indexed": false}
+	]},
+	{"type": "event", "name": "Ping", "anonymous": true, "inputs": [
+		{"name": "", "type": "address", "indexed": true},
+		{"name": "n", "type": "uint64", "indexed": false}
+	]}
+]`
+
+// makeLog 按事件定义构造日志，indexed 参数通过 abi.MakeTopics 编码（动态类型取哈希）
+func makeLog(t *testing.T, event abi.Event, indexed []interface{}, data ...interface{}) types.Log {
+	t.Helper()
+	var topics []common.Hash
+	if !event.Anonymous {
+		topics = append(topics, event.ID)
+	}
+	for _, v := range indexed {
+		encoded, err := abi.MakeTopics([]interface{}{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+		topics = append(topics, encoded[0][0])
+	}
+	packed, err := event.Inputs.NonIndexed().Pack(data...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.Log{Topics: topics, Data: packed}
+}
+
+func isInt(v interface{}, want int64) bool {
+	n, ok := v.(*big.Int)
+	return ok && n.Int64() == want
+}
+
+func TestEventDecoder(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(eventsABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := contract.StandardEventDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Add(parsed)
+
+	owner := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	// indexed 与非 indexed 参数交错，indexed string 只能得到哈希
+	ev := d.Decode(makeLog(t, parsed.Events["Registered"], []interface{}{"alice", owner}, "hello", big.NewInt(7)))
+	if ev.Name != "Registered" || len(ev.Args) != 4 {
+		t.Fatalf("解码结果 = %+v", ev)
+	}
+	label := ev.Args[0]
+	if !label.Indexed || !label.Hashed || label.Value != crypto.Keccak256Hash([]byte("alice")) {
+		t.Errorf("label = %+v", label)
+	}
+	data, err := json.Marshal(ev.Args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"label":"` + crypto.Keccak256Hash([]byte("alice")).Hex() + `","note":"hello","owner":"` + owner.Hex() + `","amount":"7"}`
+	if string(data) != want {
+		t.Errorf("JSON = %s\nwant %s", data, want)
+	}
+
+	// ERC20 与 ERC721 的 Transfer 签名相同，按 topic 数量区分
+	erc20, _ := contract.ParseERC20ABI()
+	ev = d.Decode(makeLog(t, erc20.Events["Transfer"], []interface{}{owner, owner}, big.NewInt(100)))
+	if v, _ := ev.Args.Get("value"); ev.Name != "Transfer" || !isInt(v, 100) {
+		t.Errorf("ERC20 Transfer = %s", ev.Args)
+	}
+	nft := types.Log{Topics: []common.Hash{erc20.Events["Transfer"].ID, {}, common.BytesToHash(owner.Bytes()), common.BigToHash(big.NewInt(42))}}
+	ev = d.Decode(nft)
+	if v, _ := ev.Args.Get("tokenId"); ev.Name != "Transfer" || !isInt(v, 42) {
+		t.Errorf("ERC721 Transfer = %s", ev.Args)
+	}
+
+	// 匿名事件没有签名 topic，未命名参数按位置命名
+	ev = d.Decode(makeLog(t, parsed.Events["Ping"], []interface{}{owner}, uint64(3)))
+	if !ev.Anonymous || ev.Args.String() != "arg0="+owner.Hex()+" n=3" {
+		t.Errorf("匿名事件 = %+v", ev)
+	}
+
+	// 未知事件保留原始日志
+	unknown := types.Log{Topics: []common.Hash{crypto.Keccak256Hash([]byte("Unknown()")), {1}}, Data: []byte{1, 2}}
+	if ev = d.Decode(unknown); ev.Known() || len(ev.Log.Topics) != 2 || len(ev.Log.Data) != 2 {
+		t.Errorf("未知事件 = %+v", ev)
+	}
+}
@@ -0,0 +1,147 @@
+package contract_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+)
+
+// erc165Caller 模拟只实现了部分接口的 ERC165 合约
+type erc165Caller struct {
+	ethclient.Backend
+
+	interfaces [][4]byte
+	balance    *big.Int // 其他调用（balanceOf 等）统一返回的值
+}
+
+func (f *erc165Caller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	ret := make([]byte, 32)
+	if !bytes.Equal(msg.Data[:4], contract.InterfaceERC165[:]) {
+		if f.balance != nil {
+			f.balance.FillBytes(ret)
+		}
+		return ret, nil
+	}
+	for _, id := range f.interfaces {
+		if bytes.Equal(msg.Data[4:8], id[:]) {
+			ret[31] = 1
+		}
+	}
+	return ret, nil
+}
+
+func TestDetectNFTStandard(t *testing.T) {
+	ctx := context.Background()
+	address := common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+
+	tests := []struct {
+		interfaces [][4]byte
+		want       contract.NFTStandard
+	}{
+		{[][4]byte{contract.InterfaceERC165, contract.InterfaceERC721}, contract.NFTERC721},
+		{[][4]byte{contract.InterfaceERC165, contract.InterfaceERC1155}, contract.NFTERC1155},
+		// 未声明支持 ERC165 时其他接口的结果不可信
+		{[][4]byte{contract.InterfaceERC721}, contract.NFTUnknown},
+	}
+	for _, tt := range tests {
+		got, err := contract.DetectNFTStandard(ctx, &erc165Caller{interfaces: tt.interfaces}, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("DetectNFTStandard = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestERC721EnumerableNotSupported(t *testing.T) {
+	caller := &erc165Caller{interfaces: [][4]byte{contract.InterfaceERC165, contract.InterfaceERC721}}
+	nft, err := contract.NewERC721Contract("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", caller, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := nft.TotalSupply(context.Background()); err == nil {
+		t.Error("未实现 Enumerable 时应该报错")
+	}
+}
+
+func TestERC721TokensOfOwnerTooMany(t *testing.T) {
+	ctx := context.Background()
+	owner := common.HexToAddress("0x742D35CC6634c0532925A3b844BC9E7595F0BEb0")
+	huge := new(big.Int).Lsh(big.NewInt(1), 255)
+
+	for _, balance := range []*big.Int{huge, big.NewInt(contract.MaxTokensOfOwner + 1)} {
+		caller := &erc165Caller{
+			interfaces: [][4]byte{contract.InterfaceERC165, contract.InterfaceERC721, contract.InterfaceERC721Enumerable},
+			balance:    balance,
+		}
+		nft, err := contract.NewERC721Contract("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D", caller, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := nft.TokensOfOwner(ctx, owner); err == nil {
+			t.Errorf("余额 %s 超过上限时应该报错", balance)
+		}
+	}
+}
+
+func TestExpandURI(t *testing.T) {
+	got := contract.ExpandURI("https://token-cdn-domain/{id}.json", big.NewInt(314592))
+	want := "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json"
+	if got != want {
+		t.Errorf("ExpandURI = %s, want %s", got, want)
+	}
+}
+
+func TestParseTransferEvents(t *testing.T) {
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	nft, _ := contract.NewERC721Contract("0x0000000000000000000000000000000000000001", nil, nil)
+	transfer, err := nft.ParseTransfer(types.Log{Topics: []common.Hash{
+		nft.ABI.Events["Transfer"].ID,
+		common.BytesToHash(from.Bytes()),
+		common.BytesToHash(to.Bytes()),
+		common.BigToHash(big.NewInt(7)),
+	}})
+	if err != nil {
+		t.Fatalf("解码 Transfer 失败: %v", err)
+	}
+	if transfer.From != from || transfer.To != to || transfer.TokenID.Int64() != 7 {
+		t.Errorf("Transfer = %+v", transfer)
+	}
+
+	// ERC20 的 Transfer 只有 3 个 topic
+	if _, err := nft.ParseTransfer(types.Log{Topics: []common.Hash{nft.ABI.Events["Transfer"].ID, {}, {}}}); err == nil {
+		t.Error("ERC20 Transfer 不应被解码为 ERC721 事件")
+	}
+
+	multi, _ := contract.NewERC1155Contract("0x0000000000000000000000000000000000000002", nil, nil)
+	event := multi.ABI.Events["TransferBatch"]
+	data, err := event.Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]*big.Int{big.NewInt(10), big.NewInt(20)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch, err := multi.ParseTransferBatch(types.Log{
+		Topics: []common.Hash{event.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	})
+	if err != nil {
+		t.Fatalf("解码 TransferBatch 失败: %v", err)
+	}
+	if len(batch.IDs) != 2 || batch.IDs[1].Int64() != 2 || batch.Values[1].Int64() != 20 || batch.To != to {
+		t.Errorf("TransferBatch = %+v", batch)
+	}
+}
@@ -0,0 +1,54 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var _ Backend = (*ethclient.Client)(nil)
+
+// Backend 节点访问接口，覆盖本项目用到的读取、发送、调用、日志和订阅
+//
+// go-ethereum 的 *ethclient.Client 直接实现了该接口；模拟链、mock、多节点、
+// 缓存等实现只要满足该接口即可替换到 Client、transaction.Manager 和合约绑定中
+type Backend interface {
+	ethereum.ChainReader
+	ethereum.TransactionReader
+	ethereum.ChainStateReader
+	ethereum.PendingStateReader
+	ethereum.ContractCaller
+	ethereum.PendingContractCaller
+	ethereum.LogFilterer
+	ethereum.TransactionSender
+	ethereum.GasPricer
+	ethereum.GasEstimator
+
+	// ChainID 返回链 ID
+	ChainID(ctx context.Context) (*big.Int, error)
+	// BlockNumber 返回最新区块号
+	BlockNumber(ctx context.Context) (uint64, error)
+	// SuggestGasTipCap 返回建议的 EIP-1559 小费
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	// FeeHistory 返回 eth_feeHistory 结果
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+
+	// BalanceAtHash 查询指定区块哈希处的余额（EIP-1898）
+	BalanceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (*big.Int, error)
+	// StorageAtHash 查询指定区块哈希处的存储槽
+	StorageAtHash(ctx context.Context, account common.Address, key common.Hash, blockHash common.Hash) ([]byte, error)
+	// CodeAtHash 查询指定区块哈希处的合约代码
+	CodeAtHash(ctx context.Context, account common.Address, blockHash common.Hash) ([]byte, error)
+	// NonceAtHash 查询指定区块哈希处的 nonce
+	NonceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (uint64, error)
+	// CallContractAtHash 在指定区块哈希处的状态上执行 eth_call
+	CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error)
+}
+
+// closer 持有连接、需要关闭的 Backend
+type closer interface {
+	Close()
+}
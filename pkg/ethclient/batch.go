@@ -0,0 +1,250 @@
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchSize 单个批量请求的默认条数，多数节点服务商的上限不低于 100
+const DefaultBatchSize = 100
+
+// ErrBatchUnsupported Backend 不支持 JSON-RPC 批量请求
+var ErrBatchUnsupported = errors.New("节点不支持批量请求")
+
+// BatchCaller 支持 JSON-RPC 批量请求的 Backend
+//
+// *rpc.Client、RetryBackend 和 MultiBackend 实现了该接口，
+// go-ethereum 的 *ethclient.Client 通过 Client() 暴露底层 *rpc.Client
+type BatchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// batchCallerOf 取 Backend 的批量请求能力
+func batchCallerOf(b Backend) (BatchCaller, bool) {
+	switch v := b.(type) {
+	case BatchCaller:
+		return v, true
+	case interface{ Client() *rpc.Client }:
+		return v.Client(), true
+	}
+	return nil, false
+}
+
+// BalanceResult 批量查询余额的单条结果
+type BalanceResult struct {
+	Address common.Address
+	Balance *big.Int // 单位 wei
+	Err     error
+}
+
+// ReceiptResult 批量查询收据的单条结果，交易未上链时 Err 为 ethereum.NotFound
+type ReceiptResult struct {
+	TxHash  common.Hash
+	Receipt *types.Receipt
+	Err     error
+}
+
+// BlockResult 批量查询区块的单条结果，区块不存在时 Err 为 ethereum.NotFound
+type BlockResult struct {
+	Number uint64
+	Block  *types.Block
+	Err    error
+}
+
+// CodeResult 批量查询合约代码的单条结果，普通账户的 Code 为空
+type CodeResult struct {
+	Address common.Address
+	Code    []byte
+	Err     error
+}
+
+// SetBatchSize 设置单个批量请求的最大条数，超出时自动分块
+func (c *Client) SetBatchSize(size int) {
+	c.batchSize = size
+}
+
+// batchCall 按批量上限分块发送请求，每条请求的错误写入对应的 BatchElem.Error
+//
+// Backend 不支持批量请求时对每条请求调用 fallback；只有 ctx 结束时才返回错误，
+// 某一块整体失败（如连接中断）时该块内的每条请求都记录这个错误
+func (c *Client) batchCall(ctx context.Context, elems []rpc.BatchElem, fallback func(i int) error) error {
+	size := c.batchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+
+	bc, ok := batchCallerOf(c.client)
+	for start := 0; start < len(elems); start += size {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		chunk := elems[start:min(start+size, len(elems))]
+
+		var err error
+		if ok {
+			err = bc.BatchCallContext(ctx, chunk)
+		}
+		if !ok || errors.Is(err, ErrBatchUnsupported) {
+			ok = false
+			for i := range chunk {
+				chunk[i].Error = fallback(start + i)
+			}
+			continue
+		}
+		if err != nil {
+			for i := range chunk {
+				chunk[i].Error = err
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// GetBalances 批量查询最新区块的余额（wei），结果顺序与 addresses 一致
+func (c *Client) GetBalances(ctx context.Context, addresses []common.Address) ([]BalanceResult, error) {
+	return c.GetBalancesAt(ctx, addresses, LatestBlock)
+}
+
+// GetBalancesAt 批量查询指定区块的余额（wei），结果顺序与 addresses 一致
+func (c *Client) GetBalancesAt(ctx context.Context, addresses []common.Address, block BlockSelector) ([]BalanceResult, error) {
+	balances := make([]hexutil.Big, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, addr := range addresses {
+		elems[i] = rpc.BatchElem{Method: "eth_getBalance", Args: []interface{}{addr, block.rpcArg()}, Result: &balances[i]}
+	}
+
+	err := c.batchCall(ctx, elems, func(i int) error {
+		balance, err := BalanceAt(ctx, c.client, addresses[i], block)
+		if err == nil {
+			balances[i] = hexutil.Big(*balance)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("批量查询余额失败: %w", err)
+	}
+
+	results := make([]BalanceResult, len(addresses))
+	for i, addr := range addresses {
+		results[i] = BalanceResult{Address: addr, Err: elems[i].Error}
+		if elems[i].Error == nil {
+			results[i].Balance = balances[i].ToInt()
+		}
+	}
+	return results, nil
+}
+
+// GetReceipts 批量查询交易收据，结果顺序与 txHashes 一致
+func (c *Client) GetReceipts(ctx context.Context, txHashes []common.Hash) ([]ReceiptResult, error) {
+	receipts := make([]*types.Receipt, len(txHashes))
+	elems := make([]rpc.BatchElem, len(txHashes))
+	for i, hash := range txHashes {
+		elems[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hash}, Result: &receipts[i]}
+	}
+
+	err := c.batchCall(ctx, elems, func(i int) error {
+		receipt, err := c.client.TransactionReceipt(ctx, txHashes[i])
+		receipts[i] = receipt
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("批量查询收据失败: %w", err)
+	}
+
+	results := make([]ReceiptResult, len(txHashes))
+	for i, hash := range txHashes {
+		results[i] = ReceiptResult{TxHash: hash, Receipt: receipts[i], Err: elems[i].Error}
+		if results[i].Err == nil && receipts[i] == nil {
+			results[i].Err = ethereum.NotFound
+		}
+	}
+	return results, nil
+}
+
+// GetBlocksByNumber 批量查询区块（包含完整交易），结果顺序与 numbers 一致
+//
+// 为减少请求数不会加载叔块头，Block.Uncles() 为空，区块哈希不受影响
+func (c *Client) GetBlocksByNumber(ctx context.Context, numbers []uint64) ([]BlockResult, error) {
+	raws := make([]json.RawMessage, len(numbers))
+	blocks := make([]*types.Block, len(numbers))
+	elems := make([]rpc.BatchElem, len(numbers))
+	for i, n := range numbers {
+		elems[i] = rpc.BatchElem{Method: "eth_getBlockByNumber", Args: []interface{}{hexutil.EncodeUint64(n), true}, Result: &raws[i]}
+	}
+
+	err := c.batchCall(ctx, elems, func(i int) error {
+		block, err := c.client.BlockByNumber(ctx, new(big.Int).SetUint64(numbers[i]))
+		blocks[i] = block
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("批量查询区块失败: %w", err)
+	}
+
+	results := make([]BlockResult, len(numbers))
+	for i, n := range numbers {
+		results[i] = BlockResult{Number: n, Block: blocks[i], Err: elems[i].Error}
+		if results[i].Err == nil && results[i].Block == nil {
+			results[i].Block, results[i].Err = decodeBlock(raws[i])
+		}
+	}
+	return results, nil
+}
+
+// GetCodes 批量查询最新区块的合约代码，结果顺序与 addresses 一致
+func (c *Client) GetCodes(ctx context.Context, addresses []common.Address) ([]CodeResult, error) {
+	return c.GetCodesAt(ctx, addresses, LatestBlock)
+}
+
+// GetCodesAt 批量查询指定区块的合约代码，结果顺序与 addresses 一致
+func (c *Client) GetCodesAt(ctx context.Context, addresses []common.Address, block BlockSelector) ([]CodeResult, error) {
+	codes := make([]hexutil.Bytes, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i, addr := range addresses {
+		elems[i] = rpc.BatchElem{Method: "eth_getCode", Args: []interface{}{addr, block.rpcArg()}, Result: &codes[i]}
+	}
+
+	err := c.batchCall(ctx, elems, func(i int) error {
+		code, err := codeAt(ctx, c.client, addresses[i], block)
+		codes[i] = code
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("批量查询合约代码失败: %w", err)
+	}
+
+	results := make([]CodeResult, len(addresses))
+	for i, addr := range addresses {
+		results[i] = CodeResult{Address: addr, Code: codes[i], Err: elems[i].Error}
+	}
+	return results, nil
+}
+
+// decodeBlock 解析 eth_getBlockByNumber 返回的区块
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, fmt.Errorf("解析区块头失败: %w", err)
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+
+	var body struct {
+		Transactions []*types.Transaction `json:"transactions"`
+		Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("解析区块交易失败: %w", err)
+	}
+	return types.NewBlockWithHeader(head).WithBody(body.Transactions, nil).WithWithdrawals(body.Withdrawals), nil
+}
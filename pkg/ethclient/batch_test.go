@@ -0,0 +1,85 @@
+package ethclient_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/ethclient"
+)
+
+func TestClient_Batch(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &bob.Address, big.NewInt(params.Ether), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := chain.DeploySimpleStorage(alice)
+
+	// 只实现 Backend 的包装没有批量能力，走逐条请求的兜底路径
+	fallback, err := ethclient.NewFromBackend(ctx, struct{ ethclient.Backend }{chain.Eth()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, client := range map[string]*ethclient.Client{"batch": chain.Client(), "fallback": fallback} {
+		t.Run(name, func(t *testing.T) {
+			client.SetBatchSize(3)
+
+			addresses := make([]common.Address, 0, len(chain.Accounts))
+			for _, acc := range chain.Accounts {
+				addresses = append(addresses, acc.Address)
+			}
+			balances, err := client.GetBalances(ctx, addresses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(balances) != len(addresses) {
+				t.Fatalf("结果数量 = %d, want %d", len(balances), len(addresses))
+			}
+			want := new(big.Int).Add(testchain.DefaultOptions().Balance, big.NewInt(params.Ether))
+			if balances[1].Err != nil || balances[1].Balance.Cmp(want) != 0 {
+				t.Errorf("余额[1] = %+v, want %s", balances[1], want)
+			}
+
+			receipts, err := client.GetReceipts(ctx, []common.Hash{tx.Hash(), {0x01}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if receipts[0].Err != nil || receipts[0].Receipt.TxHash != tx.Hash() {
+				t.Errorf("收据[0] = %+v", receipts[0])
+			}
+			if !errors.Is(receipts[1].Err, ethereum.NotFound) {
+				t.Errorf("不存在的收据错误 = %v, want NotFound", receipts[1].Err)
+			}
+
+			blocks, err := client.GetBlocksByNumber(ctx, []uint64{0, 1, 99})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if blocks[1].Err != nil || len(blocks[1].Block.Transactions()) != 1 || blocks[1].Block.Hash() != receipts[0].Receipt.BlockHash {
+				t.Errorf("区块[1] = %+v", blocks[1])
+			}
+			if !errors.Is(blocks[2].Err, ethereum.NotFound) {
+				t.Errorf("不存在的区块错误 = %v, want NotFound", blocks[2].Err)
+			}
+
+			codes, err := client.GetCodes(ctx, []common.Address{storage, alice.Address})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(codes[0].Code) == 0 || len(codes[1].Code) != 0 {
+				t.Errorf("合约代码长度 = %d, %d", len(codes[0].Code), len(codes[1].Code))
+			}
+		})
+	}
+}
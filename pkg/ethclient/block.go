@@ -0,0 +1,215 @@
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockSelector 查询所基于的区块：区块号、区块哈希或 latest/safe/finalized/pending 标签，零值表示 latest
+//
+// 查询较早区块的状态需要连接归档节点
+type BlockSelector struct {
+	number *rpc.BlockNumber
+	hash   *common.Hash
+}
+
+var (
+	// LatestBlock 最新区块
+	LatestBlock = BlockSelector{}
+	// PendingBlock 包含交易池交易的待出块状态
+	PendingBlock = blockTag(rpc.PendingBlockNumber)
+	// SafeBlock 共识层标记为 safe 的区块
+	SafeBlock = blockTag(rpc.SafeBlockNumber)
+	// FinalizedBlock 已最终确定的区块
+	FinalizedBlock = blockTag(rpc.FinalizedBlockNumber)
+)
+
+func blockTag(tag rpc.BlockNumber) BlockSelector {
+	return BlockSelector{number: &tag}
+}
+
+// AtBlock 指定区块号
+//
+// rpc.BlockNumber 是 int64，负数表示 latest 等标签，超过 math.MaxInt64 的区块号会变成标签，因此返回错误
+func AtBlock(number uint64) (BlockSelector, error) {
+	if number > math.MaxInt64 {
+		return BlockSelector{}, fmt.Errorf("区块号 %d 超出范围", number)
+	}
+	return blockTag(rpc.BlockNumber(number)), nil
+}
+
+// AtBlockHash 指定区块哈希（EIP-1898），重组后也能精确定位到该区块的状态
+func AtBlockHash(hash common.Hash) BlockSelector {
+	return BlockSelector{hash: &hash}
+}
+
+// ParseBlockSelector 解析区块参数：latest、safe、finalized、pending、十进制或 0x 十六进制区块号、32 字节区块哈希
+func ParseBlockSelector(s string) (BlockSelector, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "latest":
+		return LatestBlock, nil
+	case "pending":
+		return PendingBlock, nil
+	case "safe":
+		return SafeBlock, nil
+	case "finalized":
+		return FinalizedBlock, nil
+	}
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		if len(s) == 66 {
+			hash, err := hexutil.Decode(s)
+			if err != nil {
+				return BlockSelector{}, fmt.Errorf("无效的区块哈希 %q: %w", s, err)
+			}
+			return AtBlockHash(common.BytesToHash(hash)), nil
+		}
+		number, err := hexutil.DecodeUint64(strings.ToLower(s))
+		if err != nil {
+			return BlockSelector{}, fmt.Errorf("无效的区块号 %q: %w", s, err)
+		}
+		return AtBlock(number)
+	}
+
+	number, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return BlockSelector{}, fmt.Errorf("无效的区块参数 %q，可选 latest|safe|finalized|pending、区块号或区块哈希", s)
+	}
+	return AtBlock(number)
+}
+
+// String 返回区块参数的文本形式
+func (b BlockSelector) String() string {
+	switch {
+	case b.hash != nil:
+		return b.hash.Hex()
+	case b.number == nil:
+		return "latest"
+	case *b.number >= 0:
+		return strconv.FormatInt(b.number.Int64(), 10)
+	default:
+		return b.number.String()
+	}
+}
+
+// Hash 返回按哈希指定的区块哈希
+func (b BlockSelector) Hash() (common.Hash, bool) {
+	if b.hash == nil {
+		return common.Hash{}, false
+	}
+	return *b.hash, true
+}
+
+// Number 返回传给 Backend 的区块号：latest 为 nil，标签为 go-ethereum 约定的负数
+func (b BlockSelector) Number() *big.Int {
+	if b.number == nil || b.hash != nil {
+		return nil
+	}
+	return big.NewInt(b.number.Int64())
+}
+
+// rpcArg 返回 JSON-RPC 请求中的区块参数
+func (b BlockSelector) rpcArg() interface{} {
+	if b.hash != nil {
+		return rpc.BlockNumberOrHashWithHash(*b.hash, false)
+	}
+	if b.number == nil {
+		return rpc.LatestBlockNumber
+	}
+	return *b.number
+}
+
+// BalanceAt 按区块参数在 Backend 上查询余额（wei）
+func BalanceAt(ctx context.Context, b Backend, account common.Address, block BlockSelector) (*big.Int, error) {
+	if hash, ok := block.Hash(); ok {
+		return b.BalanceAtHash(ctx, account, hash)
+	}
+	return b.BalanceAt(ctx, account, block.Number())
+}
+
+// codeAt 按区块参数查询合约代码
+func codeAt(ctx context.Context, b Backend, account common.Address, block BlockSelector) ([]byte, error) {
+	if hash, ok := block.Hash(); ok {
+		return b.CodeAtHash(ctx, account, hash)
+	}
+	return b.CodeAt(ctx, account, block.Number())
+}
+
+// GetBalanceAt 查询指定区块的地址余额（wei）
+func (c *Client) GetBalanceAt(ctx context.Context, address string, block BlockSelector) (*big.Int, error) {
+	balance, err := BalanceAt(ctx, c.client, common.HexToAddress(address), block)
+	if err != nil {
+		return nil, fmt.Errorf("查询余额失败: %w", err)
+	}
+	return balance, nil
+}
+
+// GetNonceAt 查询指定区块的账户 nonce
+func (c *Client) GetNonceAt(ctx context.Context, address string, block BlockSelector) (uint64, error) {
+	account := common.HexToAddress(address)
+
+	var (
+		nonce uint64
+		err   error
+	)
+	if hash, ok := block.Hash(); ok {
+		nonce, err = c.client.NonceAtHash(ctx, account, hash)
+	} else {
+		nonce, err = c.client.NonceAt(ctx, account, block.Number())
+	}
+	if err != nil {
+		return 0, fmt.Errorf("查询 nonce 失败: %w", err)
+	}
+	return nonce, nil
+}
+
+// GetCodeAt 查询指定区块的合约代码
+func (c *Client) GetCodeAt(ctx context.Context, address string, block BlockSelector) ([]byte, error) {
+	code, err := codeAt(ctx, c.client, common.HexToAddress(address), block)
+	if err != nil {
+		return nil, fmt.Errorf("查询合约代码失败: %w", err)
+	}
+	return code, nil
+}
+
+// GetStorageAt 查询指定区块的合约存储槽
+func (c *Client) GetStorageAt(ctx context.Context, address string, slot common.Hash, block BlockSelector) ([]byte, error) {
+	account := common.HexToAddress(address)
+
+	var (
+		value []byte
+		err   error
+	)
+	if hash, ok := block.Hash(); ok {
+		value, err = c.client.StorageAtHash(ctx, account, slot, hash)
+	} else {
+		value, err = c.client.StorageAt(ctx, account, slot, block.Number())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询存储槽失败: %w", err)
+	}
+	return value, nil
+}
+
+// CallContractAt 在指定区块的状态上执行 eth_call
+func (c *Client) CallContractAt(ctx context.Context, msg ethereum.CallMsg, block BlockSelector) ([]byte, error) {
+	return CallAt(ctx, c.client, msg, block)
+}
+
+// CallAt 按区块参数在 Backend 上执行 eth_call，供合约绑定等直接持有 Backend 的调用方使用
+func CallAt(ctx context.Context, b Backend, msg ethereum.CallMsg, block BlockSelector) ([]byte, error) {
+	if hash, ok := block.Hash(); ok {
+		return b.CallContractAtHash(ctx, msg, hash)
+	}
+	return b.CallContract(ctx, msg, block.Number())
+}
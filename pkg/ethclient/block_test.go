@@ -0,0 +1,173 @@
+package ethclient_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/utils"
+)
+
+func TestParseBlockSelector(t *testing.T) {
+	hash := "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6"
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "latest"},
+		{"latest", "latest"},
+		{"Finalized", "finalized"},
+		{"safe", "safe"},
+		{"pending", "pending"},
+		{"18000000", "18000000"},
+		{"0x10", "16"},
+		{hash, hash},
+	}
+	for _, tt := range tests {
+		block, err := ethclient.ParseBlockSelector(tt.in)
+		if err != nil {
+			t.Errorf("ParseBlockSelector(%q) 失败: %v", tt.in, err)
+			continue
+		}
+		if block.String() != tt.want {
+			t.Errorf("ParseBlockSelector(%q) = %s, want %s", tt.in, block, tt.want)
+		}
+	}
+
+	// 超过 int64 的区块号会被 rpc.BlockNumber 当作标签
+	for _, in := range []string{"earliest", "-1", "0xzz", "12abc", "9223372036854775808", "0xffffffffffffffff"} {
+		if _, err := ethclient.ParseBlockSelector(in); err == nil {
+			t.Errorf("ParseBlockSelector(%q) 应返回错误", in)
+		}
+	}
+}
+
+func TestAtBlock(t *testing.T) {
+	if _, err := ethclient.AtBlock(math.MaxInt64 + 1); err == nil {
+		t.Error("超过 int64 的区块号应返回错误")
+	}
+	block, err := ethclient.AtBlock(math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Number().Int64() != math.MaxInt64 {
+		t.Errorf("AtBlock(MaxInt64) = %s", block)
+	}
+}
+
+// atBlock 测试用的 AtBlock，区块号无效时终止测试
+func atBlock(t *testing.T, number uint64) ethclient.BlockSelector {
+	t.Helper()
+	block, err := ethclient.AtBlock(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+func TestClient_HistoricalQueries(t *testing.T) {
+	chain := testchain.New(t, nil)
+	client := chain.Client()
+	alice := chain.Accounts[0]
+	bob := common.HexToAddress("0x00000000000000000000000000000000000b0b00")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	chain.Fund(bob, big.NewInt(params.Ether))
+	first, err := chain.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Fund(bob, big.NewInt(params.Ether))
+
+	tests := []struct {
+		block   ethclient.BlockSelector
+		balance string
+		nonce   uint64
+	}{
+		{atBlock(t, 0), "0", 0},
+		{atBlock(t, first.Number.Uint64()), "1", 1},
+		{ethclient.AtBlockHash(first.Hash()), "1", 1},
+		{ethclient.LatestBlock, "2", 2},
+		{ethclient.FinalizedBlock, "2", 2},
+	}
+	for _, tt := range tests {
+		balance, err := client.GetBalanceAt(ctx, bob.Hex(), tt.block)
+		if err != nil {
+			t.Fatalf("区块 %s 查询余额失败: %v", tt.block, err)
+		}
+		if got := utils.FormatEther(balance); got != tt.balance {
+			t.Errorf("区块 %s 余额 = %s ETH, want %s", tt.block, got, tt.balance)
+		}
+
+		nonce, err := client.GetNonceAt(ctx, alice.Address.Hex(), tt.block)
+		if err != nil {
+			t.Fatalf("区块 %s 查询 nonce 失败: %v", tt.block, err)
+		}
+		if nonce != tt.nonce {
+			t.Errorf("区块 %s nonce = %d, want %d", tt.block, nonce, tt.nonce)
+		}
+	}
+
+	// 批量查询同样按区块参数读取
+	results, err := client.GetBalancesAt(ctx, []common.Address{bob}, ethclient.AtBlockHash(first.Hash()))
+	if err != nil || results[0].Err != nil {
+		t.Fatalf("批量查询余额失败: %v, %v", err, results)
+	}
+	if results[0].Balance.Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("批量查询余额 = %s, want 1 ETH", results[0].Balance)
+	}
+}
+
+func TestClient_CallContractAt(t *testing.T) {
+	chain := testchain.New(t, nil)
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token := chain.DeployMyToken(alice, 1000)
+	deployed, err := chain.Client().GetBlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := token.Transfer(ctx, alice.Signer, bob.Address, big.NewInt(params.Ether))
+	if err != nil {
+		t.Fatalf("转账失败: %v", err)
+	}
+	chain.MustMine(tx)
+
+	before, err := token.BalanceOfAt(ctx, bob.Address, atBlock(t, deployed))
+	if err != nil {
+		t.Fatalf("查询历史余额失败: %v", err)
+	}
+	if before.Sign() != 0 {
+		t.Errorf("转账前余额 = %s, want 0", before)
+	}
+
+	after, err := token.BalanceOfAt(ctx, bob.Address, ethclient.LatestBlock)
+	if err != nil {
+		t.Fatalf("查询最新余额失败: %v", err)
+	}
+	if after.Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("转账后余额 = %s, want 1e18", after)
+	}
+
+	// 部署前合约代码为空
+	code, err := chain.Client().GetCodeAt(ctx, token.Address.Hex(), atBlock(t, deployed-1))
+	if err != nil {
+		t.Fatalf("查询合约代码失败: %v", err)
+	}
+	if len(code) != 0 {
+		t.Errorf("部署前合约代码长度 = %d, want 0", len(code))
+	}
+}
@@ -13,18 +13,89 @@ import (
 
 // Client 封装以太坊客户端
 type Client struct {
-	client  *ethclient.Client
-	chainID *big.Int
+	client    Backend
+	chainID   *big.Int
+	batchSize int
 }
 
-// New 创建新的客户端
-func New(nodeURL string) (*Client, error) {
-	client, err := ethclient.Dial(nodeURL)
+// DialOptions 连接参数
+type DialOptions struct {
+	// Retry 重试参数，其中的 RateLimit 是每个节点的请求预算
+	Retry *RetryOptions
+	// Multi 多节点参数，只在传入多个地址或启用 quorum 时使用
+	Multi *MultiOptions
+}
+
+// DefaultDialOptions 默认连接参数
+func DefaultDialOptions() *DialOptions {
+	return &DialOptions{
+		Retry: DefaultRetryOptions(),
+		Multi: DefaultMultiOptions(),
+	}
+}
+
+// New 创建新的客户端，传入多个节点地址时做健康检查和故障切换
+func New(nodeURLs ...string) (*Client, error) {
+	ctx := context.Background()
+
+	backend, err := DialBackend(ctx, nodeURLs, DefaultDialOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewFromBackend(ctx, backend)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// DialBackend 连接节点并加上重试和限流：单个节点直接连接，多个节点组成 MultiBackend
+func DialBackend(ctx context.Context, nodeURLs []string, opts *DialOptions) (*RetryBackend, error) {
+	if opts == nil {
+		opts = DefaultDialOptions()
+	}
+	retryOpts := opts.Retry
+	if retryOpts == nil {
+		retryOpts = DefaultRetryOptions()
+	}
+	multiOpts := opts.Multi
+	if multiOpts == nil {
+		multiOpts = DefaultMultiOptions()
+	}
+
+	if len(nodeURLs) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if len(nodeURLs) == 1 && multiOpts.Quorum <= 1 {
+		client, err := ethclient.DialContext(ctx, nodeURLs[0])
+		if err != nil {
+			return nil, fmt.Errorf("连接以太坊节点失败: %w", err)
+		}
+		return NewRetryBackend(client, retryOpts), nil
+	}
+
+	// 限流按节点生效，外层只负责在所有节点都失败后整体重试
+	perEndpoint := *multiOpts
+	perEndpoint.RateLimit = retryOpts.RateLimit
+	multi, err := DialMulti(ctx, nodeURLs, &perEndpoint)
 	if err != nil {
-		return nil, fmt.Errorf("连接以太坊节点失败: %w", err)
+		return nil, err
 	}
+	outer := *retryOpts
+	outer.RateLimit = RateLimit{}
+	return NewRetryBackend(multi, &outer), nil
+}
+
+// NewFromClient 使用已建立的连接创建客户端
+func NewFromClient(ctx context.Context, client *ethclient.Client) (*Client, error) {
+	return NewFromBackend(ctx, client)
+}
 
-	chainID, err := client.NetworkID(context.Background())
+// NewFromBackend 使用任意 Backend 实现创建客户端
+func NewFromBackend(ctx context.Context, client Backend) (*Client, error) {
+	chainID, err := client.ChainID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("获取 Chain ID 失败: %w", err)
 	}
@@ -35,9 +106,16 @@ func New(nodeURL string) (*Client, error) {
 	}, nil
 }
 
-// Close 关闭客户端连接
+// Close 关闭客户端连接，Backend 不持有连接时为空操作
 func (c *Client) Close() {
-	c.client.Close()
+	if cl, ok := c.client.(closer); ok {
+		cl.Close()
+	}
+}
+
+// Backend 返回底层节点接口，用于创建 transaction.Manager 和合约绑定
+func (c *Client) Backend() Backend {
+	return c.client
 }
 
 // ChainID 返回当前链 ID
@@ -45,21 +123,9 @@ func (c *Client) ChainID() *big.Int {
 	return c.chainID
 }
 
-// GetBalance 查询地址余额（ETH）
-func (c *Client) GetBalance(ctx context.Context, address string) (*big.Float, error) {
-	addr := common.HexToAddress(address)
-	balance, err := c.client.BalanceAt(ctx, addr, nil)
-	if err != nil {
-		return nil, fmt.Errorf("查询余额失败: %w", err)
-	}
-
-	// 转换为 ETH
-	ethBalance := new(big.Float).Quo(
-		new(big.Float).SetInt(balance),
-		big.NewFloat(1e18),
-	)
-
-	return ethBalance, nil
+// GetBalance 查询地址余额（wei），显示时用 utils.FormatEther 换算为 ETH
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return c.GetBalanceAt(ctx, address, LatestBlock)
 }
 
 // GetBlockNumber 获取最新区块号
@@ -77,16 +143,6 @@ func (c *Client) GetTransaction(ctx context.Context, txHash string) (*types.Tran
 	return c.client.TransactionByHash(ctx, hash)
 }
 
-// WaitMined 等待交易被确认
-func (c *Client) WaitMined(ctx context.Context, txHash string) (*types.Receipt, error) {
-	hash := common.HexToHash(txHash)
-	receipt, err := c.client.TransactionReceipt(ctx, hash)
-	if err != nil {
-		return nil, fmt.Errorf("获取交易收据失败: %w", err)
-	}
-	return receipt, nil
-}
-
 // SendRawTransaction 发送原始交易
 func (c *Client) SendRawTransaction(ctx context.Context, tx *types.Transaction) error {
 	return c.client.SendTransaction(ctx, tx)
@@ -0,0 +1,125 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// errCodeLimitExceeded EIP-1474 定义的请求超限错误码
+const errCodeLimitExceeded = -32005
+
+// laggingNodeErrors 节点刚收到新区块头、状态尚未就绪时返回的错误，稍后重试即可
+var laggingNodeErrors = []string{
+	"header not found",
+	"unknown block",
+}
+
+// tooManyResultsErrors 节点拒绝 eth_getLogs 查询范围过大时的错误信息（各服务商措辞不同）
+var tooManyResultsErrors = []string{
+	"query returned more than",
+	"response size exceeded",
+	"response size should not",
+	"block range is too wide",
+	"block range too large",
+	"exceed maximum block range",
+	"range is too large",
+	"is limited to a",
+	"too many results",
+	"logs matched by query exceeds",
+}
+
+// alreadySentErrors 重复发送同一笔交易时节点返回的错误（交易已在交易池中或已上链，各节点措辞不同）
+var alreadySentErrors = []string{
+	"already known",
+	"known transaction",
+	"already_exists",
+	"nonce too low",
+}
+
+// isAlreadySent 判断发送交易的错误是否可能是因为交易已被节点接受
+//
+// nonce too low 也可能是同 nonce 的另一笔交易已上链，调用方需按交易哈希确认
+func isAlreadySent(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range alreadySentErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTooManyResults 判断 eth_getLogs 是否因区块范围过大或结果过多被拒绝，缩小区块范围后重试即可
+func IsTooManyResults(err error) bool {
+	var rpcErr rpc.Error
+	if err == nil || !errors.As(err, &rpcErr) {
+		return false
+	}
+	msg := strings.ToLower(rpcErr.Error())
+	for _, s := range tooManyResultsErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTransient 判断错误是否为重试可能成功的临时错误：超时、连接中断、429、5xx、
+// 请求超限以及节点刚出新块时的 "header not found"
+//
+// 执行回滚、参数错误、nonce 错误等节点明确拒绝的请求，NotFound，以及原样重试不会成功的
+// 日志范围过大（见 IsTooManyResults）都是永久错误
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsTooManyResults(err) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		if rpcErr.ErrorCode() == errCodeLimitExceeded {
+			return true
+		}
+		msg := strings.ToLower(rpcErr.Error())
+		for _, s := range laggingNodeErrors {
+			if strings.Contains(msg, s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsRateLimited 判断错误是否为节点限流（HTTP 429 或 JSON-RPC -32005）
+func IsRateLimited(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errCodeLimitExceeded
+}
@@ -0,0 +1,782 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrNoEndpoints 没有可用的节点
+	ErrNoEndpoints = errors.New("没有可用的节点")
+	// ErrNoQuorum 达成一致的节点数不足
+	ErrNoQuorum = errors.New("节点结果未达成一致")
+)
+
+// latencyBucket 比较节点响应时间的粒度
+const latencyBucket = 50 * time.Millisecond
+
+// MultiOptions 多节点参数
+type MultiOptions struct {
+	// HealthCheckInterval 后台健康检查间隔，0 表示不做后台检查
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout 单个节点健康检查的超时时间
+	HealthCheckTimeout time.Duration
+	// MaxHeadLag 落后最高区块超过该数量视为不健康
+	MaxHeadLag uint64
+	// MaxErrorRate 最近请求的错误率超过该值视为不健康
+	MaxErrorRate float64
+	// ErrorWindow 统计错误率的最近请求数
+	ErrorWindow int
+	// RateLimitCooldown 节点返回 429 后暂停使用的时间
+	RateLimitCooldown time.Duration
+	// Quorum 关键读取（余额、nonce、收据、合约调用等）需要结果一致的节点数，0 或 1 表示不启用
+	Quorum int
+	// RateLimit DialMulti 为每个节点单独设置的请求预算
+	RateLimit RateLimit
+}
+
+// DefaultMultiOptions 默认多节点参数
+func DefaultMultiOptions() *MultiOptions {
+	return &MultiOptions{
+		HealthCheckInterval: 15 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		MaxHeadLag:          3,
+		MaxErrorRate:        0.5,
+		ErrorWindow:         20,
+		RateLimitCooldown:   30 * time.Second,
+	}
+}
+
+// Endpoint 多节点中的一个节点
+type Endpoint struct {
+	// Name 用于日志和错误信息，不应包含 API Key
+	Name    string
+	Backend Backend
+}
+
+// EndpointStatus 节点健康状态
+type EndpointStatus struct {
+	Name      string
+	Head      uint64
+	Latency   time.Duration
+	ErrorRate float64
+	Healthy   bool
+	LastError error
+}
+
+// endpoint 节点及其健康统计
+type endpoint struct {
+	Endpoint
+
+	mu            sync.Mutex
+	head          uint64
+	latency       time.Duration
+	checkErr      error
+	lastErr       error
+	outcomes      []bool // 最近请求是否失败，环形缓冲
+	next          int
+	cooldownUntil time.Time
+}
+
+// record 记录一次请求结果
+func (e *endpoint) record(err error, rateLimited bool, window int, cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.outcomes) < window {
+		e.outcomes = append(e.outcomes, err != nil)
+	} else {
+		e.outcomes[e.next] = err != nil
+		e.next = (e.next + 1) % window
+	}
+	if err != nil {
+		e.lastErr = err
+	}
+	if rateLimited {
+		e.cooldownUntil = time.Now().Add(cooldown)
+	}
+}
+
+// errorRate 最近请求的错误率
+func (e *endpoint) errorRate() float64 {
+	if len(e.outcomes) == 0 {
+		return 0
+	}
+	failed := 0
+	for _, f := range e.outcomes {
+		if f {
+			failed++
+		}
+	}
+	return float64(failed) / float64(len(e.outcomes))
+}
+
+// MultiBackend 多节点 Backend：按健康度路由请求，传输错误和 429 时切换节点，
+// 可选对关键读取做多数一致校验，发送交易时广播到所有节点
+type MultiBackend struct {
+	endpoints []*endpoint
+	opts      *MultiOptions
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ Backend = (*MultiBackend)(nil)
+
+// NewMultiBackend 使用已创建的 Backend 组成多节点，opts.HealthCheckInterval > 0 时启动后台健康检查
+func NewMultiBackend(endpoints []Endpoint, opts *MultiOptions) (*MultiBackend, error) {
+	if opts == nil {
+		opts = DefaultMultiOptions()
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if opts.Quorum > len(endpoints) {
+		return nil, fmt.Errorf("quorum %d 超过节点数 %d", opts.Quorum, len(endpoints))
+	}
+	if opts.ErrorWindow <= 0 {
+		opts.ErrorWindow = DefaultMultiOptions().ErrorWindow
+	}
+
+	m := &MultiBackend{opts: opts, stop: make(chan struct{})}
+	for _, ep := range endpoints {
+		m.endpoints = append(m.endpoints, &endpoint{Endpoint: ep})
+	}
+
+	if opts.HealthCheckInterval > 0 {
+		m.wg.Add(1)
+		go m.healthLoop()
+	}
+	return m, nil
+}
+
+// DialMulti 连接多个节点并完成首次健康检查，所有节点必须属于同一条链
+//
+// 连接失败的节点会被跳过，剩余节点数少于 quorum 时返回错误
+func DialMulti(ctx context.Context, urls []string, opts *MultiOptions) (*MultiBackend, error) {
+	if opts == nil {
+		opts = DefaultMultiOptions()
+	}
+
+	var (
+		endpoints []Endpoint
+		chainID   *big.Int
+		dialErrs  []string
+	)
+	for i, rawURL := range urls {
+		name := endpointName(rawURL, i)
+		if _, err := url.Parse(rawURL); err != nil {
+			// 解析错误中带有完整地址，不能原样返回
+			dialErrs = append(dialErrs, fmt.Sprintf("%s: 无效的节点地址", name))
+			continue
+		}
+		client, err := ethclient.DialContext(ctx, rawURL)
+		if err != nil {
+			dialErrs = append(dialErrs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		id, err := client.ChainID(ctx)
+		if err != nil {
+			client.Close()
+			dialErrs = append(dialErrs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if chainID != nil && id.Cmp(chainID) != 0 {
+			client.Close()
+			closeEndpoints(endpoints)
+			return nil, fmt.Errorf("节点 %s 的 Chain ID %s 与其他节点 %s 不一致", name, id, chainID)
+		}
+		chainID = id
+
+		// 每个节点单独限流，重试交给外层，失败时尽快切换节点
+		var backend Backend = client
+		if opts.RateLimit != (RateLimit{}) {
+			backend = NewRetryBackend(client, &RetryOptions{MaxAttempts: 1, RateLimit: opts.RateLimit})
+		}
+		endpoints = append(endpoints, Endpoint{Name: name, Backend: backend})
+	}
+
+	if len(endpoints) == 0 || len(endpoints) < opts.Quorum {
+		closeEndpoints(endpoints)
+		return nil, fmt.Errorf("连接以太坊节点失败: %w (%s)", ErrNoEndpoints, strings.Join(dialErrs, "; "))
+	}
+
+	m, err := NewMultiBackend(endpoints, opts)
+	if err != nil {
+		closeEndpoints(endpoints)
+		return nil, err
+	}
+	m.CheckHealth(ctx)
+	return m, nil
+}
+
+// Close 停止健康检查并关闭所有节点连接
+func (m *MultiBackend) Close() {
+	select {
+	case <-m.stop:
+		return
+	default:
+		close(m.stop)
+	}
+	m.wg.Wait()
+	for _, e := range m.endpoints {
+		if cl, ok := e.Backend.(closer); ok {
+			cl.Close()
+		}
+	}
+}
+
+// CheckHealth 立即检查所有节点的最新区块和响应时间
+func (m *MultiBackend) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range m.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if m.opts.HealthCheckTimeout > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, m.opts.HealthCheckTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			head, err := e.Backend.BlockNumber(checkCtx)
+			latency := time.Since(start)
+
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.checkErr = err
+			if err != nil {
+				e.lastErr = err
+				return
+			}
+			e.head, e.latency = head, latency
+		}(e)
+	}
+	wg.Wait()
+}
+
+// Status 返回各节点的健康状态，顺序与请求路由顺序一致
+func (m *MultiBackend) Status() []EndpointStatus {
+	ranked, healthy := m.rank()
+	status := make([]EndpointStatus, len(ranked))
+	for i, e := range ranked {
+		e.mu.Lock()
+		status[i] = EndpointStatus{
+			Name:      e.Name,
+			Head:      e.head,
+			Latency:   e.latency,
+			ErrorRate: e.errorRate(),
+			Healthy:   i < healthy,
+			LastError: e.lastErr,
+		}
+		e.mu.Unlock()
+	}
+	return status
+}
+
+func (m *MultiBackend) healthLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				select {
+				case <-m.stop:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+			m.CheckHealth(ctx)
+			cancel()
+		}
+	}
+}
+
+// rank 按健康度排序节点，返回排序结果和其中健康节点的数量
+//
+// 不健康的节点排在后面作为最后的兜底
+func (m *MultiBackend) rank() ([]*endpoint, int) {
+	type ranking struct {
+		e         *endpoint
+		healthy   bool
+		lag       uint64
+		errorRate float64
+		latency   time.Duration
+	}
+
+	var maxHead uint64
+	for _, e := range m.endpoints {
+		e.mu.Lock()
+		if e.checkErr == nil && e.head > maxHead {
+			maxHead = e.head
+		}
+		e.mu.Unlock()
+	}
+
+	now := time.Now()
+	rankings := make([]ranking, len(m.endpoints))
+	for i, e := range m.endpoints {
+		e.mu.Lock()
+		r := ranking{e: e, lag: maxHead - min(e.head, maxHead), errorRate: e.errorRate(), latency: e.latency}
+		r.healthy = e.checkErr == nil &&
+			!now.Before(e.cooldownUntil) &&
+			r.lag <= m.opts.MaxHeadLag &&
+			(m.opts.MaxErrorRate <= 0 || r.errorRate <= m.opts.MaxErrorRate)
+		e.mu.Unlock()
+		rankings[i] = r
+	}
+
+	sort.SliceStable(rankings, func(i, j int) bool {
+		a, b := rankings[i], rankings[j]
+		switch {
+		case a.healthy != b.healthy:
+			return a.healthy
+		case a.lag != b.lag:
+			return a.lag < b.lag
+		case a.errorRate != b.errorRate:
+			return a.errorRate < b.errorRate
+		default:
+			// 响应时间相差不大时保持配置顺序，避免抖动导致主节点频繁切换
+			return a.latency/latencyBucket < b.latency/latencyBucket
+		}
+	})
+
+	ranked := make([]*endpoint, len(rankings))
+	healthy := 0
+	for i, r := range rankings {
+		ranked[i] = r.e
+		if r.healthy {
+			healthy++
+		}
+	}
+	return ranked, healthy
+}
+
+// classifyError 判断错误是否应切换节点，以及是否为限流
+//
+// 临时错误、HTTP 错误状态和未知的传输错误会切换节点；节点正常返回的 JSON-RPC 错误
+// （执行回滚、nonce 过低等）和 NotFound 换节点也不会改变结果，直接返回
+func classifyError(err error) (failover, rateLimited bool) {
+	if IsTransient(err) {
+		return true, IsRateLimited(err)
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return true, false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) || errors.Is(err, ethereum.NotFound) {
+		return false, false
+	}
+	return true, false
+}
+
+// handle 记录请求结果，返回是否应尝试下一个节点
+func (m *MultiBackend) handle(ctx context.Context, e *endpoint, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		e.record(nil, false, m.opts.ErrorWindow, m.opts.RateLimitCooldown)
+		return false
+	}
+	failover, rateLimited := classifyError(err)
+	if !failover {
+		e.record(nil, false, m.opts.ErrorWindow, m.opts.RateLimitCooldown)
+		return false
+	}
+	e.record(err, rateLimited, m.opts.ErrorWindow, m.opts.RateLimitCooldown)
+	return true
+}
+
+// call 按健康度依次尝试节点，直到成功或遇到不需要切换的错误
+func call[T any](ctx context.Context, m *MultiBackend, fn func(Backend) (T, error)) (T, error) {
+	var (
+		zero    T
+		lastErr error
+	)
+	ranked, _ := m.rank()
+	for _, e := range ranked {
+		v, err := fn(e.Backend)
+		if !m.handle(ctx, e, err) {
+			return v, err
+		}
+		lastErr = fmt.Errorf("%s: %w", e.Name, err)
+	}
+	return zero, fmt.Errorf("所有节点请求失败: %w", lastErr)
+}
+
+// quorumCall 并发请求所有节点，返回至少 Quorum 个节点一致的结果；未启用 quorum 时等同 call
+//
+// key 用于比较结果是否一致，NotFound 也作为一种结果参与比较
+func quorumCall[T any](ctx context.Context, m *MultiBackend, fn func(Backend) (T, error), key func(T) string) (T, error) {
+	if m.opts.Quorum <= 1 {
+		return call(ctx, m, fn)
+	}
+
+	type answer struct {
+		value T
+		err   error
+		key   string
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	answers := make(chan answer, len(m.endpoints))
+	for _, e := range m.endpoints {
+		go func(e *endpoint) {
+			v, err := fn(e.Backend)
+			if m.handle(ctx, e, err) {
+				answers <- answer{err: fmt.Errorf("%s: %w", e.Name, err)}
+				return
+			}
+			switch {
+			case err == nil:
+				answers <- answer{value: v, key: key(v)}
+			case errors.Is(err, ethereum.NotFound):
+				answers <- answer{err: err, key: "not found"}
+			default:
+				answers <- answer{err: err, key: "error: " + err.Error()}
+			}
+		}(e)
+	}
+
+	var (
+		zero   T
+		counts = make(map[string]int)
+		errs   []string
+	)
+	for range m.endpoints {
+		a := <-answers
+		if a.key == "" {
+			errs = append(errs, a.err.Error())
+			continue
+		}
+		counts[a.key]++
+		if counts[a.key] >= m.opts.Quorum {
+			return a.value, a.err
+		}
+	}
+	return zero, fmt.Errorf("%w: 需要 %d 个节点一致，结果分布 %v，失败 %v", ErrNoQuorum, m.opts.Quorum, counts, errs)
+}
+
+// broadcast 并发发送到所有节点，任一节点接受即视为成功
+func (m *MultiBackend) broadcast(ctx context.Context, fn func(Backend) error) error {
+	errs := make([]error, len(m.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range m.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			err := fn(e.Backend)
+			m.handle(ctx, e, err)
+			errs[i] = err
+		}(i, e)
+	}
+	wg.Wait()
+
+	var nodeErr, lastErr error
+	for i, err := range errs {
+		if err == nil {
+			return nil
+		}
+		// 交易已经通过其他节点的 p2p 广播到达
+		if strings.Contains(err.Error(), "already known") {
+			return nil
+		}
+		if failover, _ := classifyError(err); !failover && nodeErr == nil {
+			nodeErr = err
+		}
+		lastErr = fmt.Errorf("%s: %w", m.endpoints[i].Name, err)
+	}
+	// 优先返回节点拒绝交易的原因（nonce、余额等），其次是传输错误
+	if nodeErr != nil {
+		return nodeErr
+	}
+	return fmt.Errorf("所有节点发送失败: %w", lastErr)
+}
+
+// subscribe 在第一个支持订阅的节点上建立订阅，节点切换后需要调用方重新订阅
+func (m *MultiBackend) subscribe(ctx context.Context, fn func(Backend) (ethereum.Subscription, error)) (ethereum.Subscription, error) {
+	var lastErr error
+	ranked, _ := m.rank()
+	for _, e := range ranked {
+		sub, err := fn(e.Backend)
+		if err == nil {
+			return sub, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = fmt.Errorf("%s: %w", e.Name, err)
+	}
+	return nil, fmt.Errorf("所有节点订阅失败: %w", lastErr)
+}
+
+// BatchCallContext 在最健康的节点上发送批量请求，整批失败时切换节点
+func (m *MultiBackend) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	_, err := call(ctx, m, func(backend Backend) (struct{}, error) {
+		bc, ok := batchCallerOf(backend)
+		if !ok {
+			return struct{}{}, ErrBatchUnsupported
+		}
+		return struct{}{}, bc.BatchCallContext(ctx, b)
+	})
+	return err
+}
+
+// EthSubscribe 在最健康的节点上建立任意 eth_subscribe 订阅
+func (m *MultiBackend) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (ethereum.Subscription, error) {
+	return m.subscribe(ctx, func(b Backend) (ethereum.Subscription, error) {
+		sub, ok := subscriberOf(b)
+		if !ok {
+			return nil, ErrSubscriptionUnsupported
+		}
+		return sub.EthSubscribe(ctx, channel, args...)
+	})
+}
+
+// ChainID 返回链 ID
+func (m *MultiBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return call(ctx, m, func(b Backend) (*big.Int, error) { return b.ChainID(ctx) })
+}
+
+// BlockNumber 返回健康节点中的最新区块号
+func (m *MultiBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, m, func(b Backend) (uint64, error) { return b.BlockNumber(ctx) })
+}
+
+// BlockByHash 按哈希查询区块
+func (m *MultiBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return call(ctx, m, func(b Backend) (*types.Block, error) { return b.BlockByHash(ctx, hash) })
+}
+
+// BlockByNumber 按高度查询区块
+func (m *MultiBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return call(ctx, m, func(b Backend) (*types.Block, error) { return b.BlockByNumber(ctx, number) })
+}
+
+// HeaderByHash 按哈希查询区块头
+func (m *MultiBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return call(ctx, m, func(b Backend) (*types.Header, error) { return b.HeaderByHash(ctx, hash) })
+}
+
+// HeaderByNumber 按高度查询区块头
+func (m *MultiBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return call(ctx, m, func(b Backend) (*types.Header, error) { return b.HeaderByNumber(ctx, number) })
+}
+
+// TransactionCount 返回区块中的交易数
+func (m *MultiBackend) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return call(ctx, m, func(b Backend) (uint, error) { return b.TransactionCount(ctx, blockHash) })
+}
+
+// TransactionInBlock 返回区块中指定位置的交易
+func (m *MultiBackend) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return call(ctx, m, func(b Backend) (*types.Transaction, error) { return b.TransactionInBlock(ctx, blockHash, index) })
+}
+
+// SubscribeNewHead 订阅新区块头
+func (m *MultiBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return m.subscribe(ctx, func(b Backend) (ethereum.Subscription, error) { return b.SubscribeNewHead(ctx, ch) })
+}
+
+// TransactionByHash 按哈希查询交易
+func (m *MultiBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+	r, err := call(ctx, m, func(b Backend) (result, error) {
+		tx, isPending, err := b.TransactionByHash(ctx, hash)
+		return result{tx, isPending}, err
+	})
+	return r.tx, r.isPending, err
+}
+
+// TransactionReceipt 查询交易收据，启用 quorum 时要求收据所在区块和状态一致
+func (m *MultiBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return quorumCall(ctx, m, func(b Backend) (*types.Receipt, error) {
+		return b.TransactionReceipt(ctx, txHash)
+	}, func(r *types.Receipt) string {
+		return fmt.Sprintf("%s/%d", r.BlockHash.Hex(), r.Status)
+	})
+}
+
+// BalanceAt 查询余额，启用 quorum 时要求余额一致
+func (m *MultiBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return quorumCall(ctx, m, func(b Backend) (*big.Int, error) {
+		return b.BalanceAt(ctx, account, blockNumber)
+	}, (*big.Int).String)
+}
+
+// StorageAt 查询存储槽，启用 quorum 时要求结果一致
+func (m *MultiBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.StorageAt(ctx, account, key, blockNumber)
+	}, hexutil.Encode)
+}
+
+// CodeAt 查询合约代码，启用 quorum 时要求结果一致
+func (m *MultiBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.CodeAt(ctx, account, blockNumber)
+	}, hexutil.Encode)
+}
+
+// NonceAt 查询已确认 nonce，启用 quorum 时要求结果一致
+func (m *MultiBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return quorumCall(ctx, m, func(b Backend) (uint64, error) {
+		return b.NonceAt(ctx, account, blockNumber)
+	}, func(n uint64) string { return fmt.Sprint(n) })
+}
+
+// BalanceAtHash 查询指定区块哈希处的余额，启用 quorum 时要求余额一致
+func (m *MultiBackend) BalanceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (*big.Int, error) {
+	return quorumCall(ctx, m, func(b Backend) (*big.Int, error) {
+		return b.BalanceAtHash(ctx, account, blockHash)
+	}, (*big.Int).String)
+}
+
+// StorageAtHash 查询指定区块哈希处的存储槽，启用 quorum 时要求结果一致
+func (m *MultiBackend) StorageAtHash(ctx context.Context, account common.Address, key common.Hash, blockHash common.Hash) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.StorageAtHash(ctx, account, key, blockHash)
+	}, hexutil.Encode)
+}
+
+// CodeAtHash 查询指定区块哈希处的合约代码，启用 quorum 时要求结果一致
+func (m *MultiBackend) CodeAtHash(ctx context.Context, account common.Address, blockHash common.Hash) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.CodeAtHash(ctx, account, blockHash)
+	}, hexutil.Encode)
+}
+
+// NonceAtHash 查询指定区块哈希处的 nonce，启用 quorum 时要求结果一致
+func (m *MultiBackend) NonceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (uint64, error) {
+	return quorumCall(ctx, m, func(b Backend) (uint64, error) {
+		return b.NonceAtHash(ctx, account, blockHash)
+	}, func(n uint64) string { return fmt.Sprint(n) })
+}
+
+// PendingBalanceAt 查询 pending 状态的余额
+func (m *MultiBackend) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	return call(ctx, m, func(b Backend) (*big.Int, error) { return b.PendingBalanceAt(ctx, account) })
+}
+
+// PendingStorageAt 查询 pending 状态的存储槽
+func (m *MultiBackend) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	return call(ctx, m, func(b Backend) ([]byte, error) { return b.PendingStorageAt(ctx, account, key) })
+}
+
+// PendingCodeAt 查询 pending 状态的合约代码
+func (m *MultiBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return call(ctx, m, func(b Backend) ([]byte, error) { return b.PendingCodeAt(ctx, account) })
+}
+
+// PendingNonceAt 查询 pending nonce
+func (m *MultiBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(ctx, m, func(b Backend) (uint64, error) { return b.PendingNonceAt(ctx, account) })
+}
+
+// PendingTransactionCount 查询交易池中的交易数
+func (m *MultiBackend) PendingTransactionCount(ctx context.Context) (uint, error) {
+	return call(ctx, m, func(b Backend) (uint, error) { return b.PendingTransactionCount(ctx) })
+}
+
+// CallContract 执行 eth_call，启用 quorum 时要求返回数据一致
+func (m *MultiBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.CallContract(ctx, msg, blockNumber)
+	}, hexutil.Encode)
+}
+
+// CallContractAtHash 在指定区块哈希处的状态上执行 eth_call，启用 quorum 时要求返回数据一致
+func (m *MultiBackend) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return quorumCall(ctx, m, func(b Backend) ([]byte, error) {
+		return b.CallContractAtHash(ctx, msg, blockHash)
+	}, hexutil.Encode)
+}
+
+// PendingCallContract 在 pending 状态上执行 eth_call
+func (m *MultiBackend) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return call(ctx, m, func(b Backend) ([]byte, error) { return b.PendingCallContract(ctx, msg) })
+}
+
+// FilterLogs 查询日志
+func (m *MultiBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return call(ctx, m, func(b Backend) ([]types.Log, error) { return b.FilterLogs(ctx, q) })
+}
+
+// SubscribeFilterLogs 订阅日志
+func (m *MultiBackend) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return m.subscribe(ctx, func(b Backend) (ethereum.Subscription, error) { return b.SubscribeFilterLogs(ctx, q, ch) })
+}
+
+// SendTransaction 将交易广播到所有节点
+func (m *MultiBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return m.broadcast(ctx, func(b Backend) error { return b.SendTransaction(ctx, tx) })
+}
+
+// SuggestGasPrice 返回建议 Gas 价格
+func (m *MultiBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return call(ctx, m, func(b Backend) (*big.Int, error) { return b.SuggestGasPrice(ctx) })
+}
+
+// SuggestGasTipCap 返回建议小费
+func (m *MultiBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(ctx, m, func(b Backend) (*big.Int, error) { return b.SuggestGasTipCap(ctx) })
+}
+
+// FeeHistory 返回 eth_feeHistory 结果
+func (m *MultiBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return call(ctx, m, func(b Backend) (*ethereum.FeeHistory, error) {
+		return b.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+// EstimateGas 估算 Gas
+func (m *MultiBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, m, func(b Backend) (uint64, error) { return b.EstimateGas(ctx, msg) })
+}
+
+// endpointName 去掉 URL 中的路径、查询参数和用户信息，避免 API Key 出现在错误和日志中
+//
+// 无法解析或没有主机名（如 IPC 路径）时按序号命名为 endpoint#<i>
+func endpointName(rawURL string, i int) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("endpoint#%d", i)
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+func closeEndpoints(endpoints []Endpoint) {
+	for _, ep := range endpoints {
+		if cl, ok := ep.Backend.(closer); ok {
+			cl.Close()
+		}
+	}
+}
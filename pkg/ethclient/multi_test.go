@@ -0,0 +1,177 @@
+package ethclient_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-eth-learning/pkg/ethclient"
+)
+
+// fakeBackend 只实现多节点测试用到的方法
+type fakeBackend struct {
+	ethclient.Backend
+
+	head    uint64
+	balance int64
+	err     error
+	calls   atomic.Int32
+	sent    atomic.Int32
+}
+
+func (f *fakeBackend) BlockNumber(context.Context) (uint64, error) {
+	return f.head, nil
+}
+
+func (f *fakeBackend) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	f.calls.Add(1)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return big.NewInt(f.balance), nil
+}
+
+func (f *fakeBackend) SendTransaction(context.Context, *types.Transaction) error {
+	f.sent.Add(1)
+	return f.err
+}
+
+// jsonRPCError 节点返回的 JSON-RPC 错误
+type jsonRPCError struct {
+	code int
+	msg  string
+}
+
+func (e *jsonRPCError) Error() string  { return e.msg }
+func (e *jsonRPCError) ErrorCode() int { return e.code }
+
+func newMulti(t *testing.T, quorum int, backends ...*fakeBackend) *ethclient.MultiBackend {
+	t.Helper()
+
+	endpoints := make([]ethclient.Endpoint, len(backends))
+	for i, b := range backends {
+		endpoints[i] = ethclient.Endpoint{Name: string(rune('a' + i)), Backend: b}
+	}
+	opts := ethclient.DefaultMultiOptions()
+	opts.HealthCheckInterval = 0
+	opts.Quorum = quorum
+
+	m, err := ethclient.NewMultiBackend(endpoints, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(m.Close)
+	m.CheckHealth(context.Background())
+	return m
+}
+
+func TestMultiBackend_Failover(t *testing.T) {
+	ctx := context.Background()
+	limited := &fakeBackend{head: 100, balance: 1, err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}}
+	healthy := &fakeBackend{head: 100, balance: 2}
+	m := newMulti(t, 0, limited, healthy)
+
+	balance, err := m.BalanceAt(ctx, common.Address{}, nil)
+	if err != nil || balance.Int64() != 2 {
+		t.Fatalf("BalanceAt = %v, %v, want 2", balance, err)
+	}
+
+	// 429 后节点进入冷却，后续请求直接路由到健康节点
+	if _, err := m.BalanceAt(ctx, common.Address{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := limited.calls.Load(); n != 1 {
+		t.Errorf("限流节点被调用 %d 次, want 1", n)
+	}
+	if status := m.Status(); status[0].Name != "b" || status[1].Healthy {
+		t.Errorf("节点状态 = %+v", status)
+	}
+}
+
+func TestMultiBackend_NodeErrorIsNotRetried(t *testing.T) {
+	reverted := &jsonRPCError{code: 3, msg: "execution reverted"}
+	first := &fakeBackend{head: 100, err: reverted}
+	second := &fakeBackend{head: 100, balance: 2}
+	m := newMulti(t, 0, first, second)
+
+	if _, err := m.BalanceAt(context.Background(), common.Address{}, nil); !errors.Is(err, reverted) {
+		t.Errorf("err = %v, want execution reverted", err)
+	}
+	if n := second.calls.Load(); n != 0 {
+		t.Errorf("节点错误不应切换节点，第二个节点被调用 %d 次", n)
+	}
+}
+
+func TestMultiBackend_HeadLag(t *testing.T) {
+	lagging := &fakeBackend{head: 90, balance: 1}
+	synced := &fakeBackend{head: 100, balance: 2}
+	m := newMulti(t, 0, lagging, synced)
+
+	balance, err := m.BalanceAt(context.Background(), common.Address{}, nil)
+	if err != nil || balance.Int64() != 2 {
+		t.Errorf("BalanceAt = %v, %v, want 2（来自同步节点）", balance, err)
+	}
+}
+
+func TestMultiBackend_Quorum(t *testing.T) {
+	ctx := context.Background()
+
+	m := newMulti(t, 2,
+		&fakeBackend{head: 100, balance: 5},
+		&fakeBackend{head: 100, balance: 7},
+		&fakeBackend{head: 100, balance: 5},
+	)
+	balance, err := m.BalanceAt(ctx, common.Address{}, nil)
+	if err != nil || balance.Int64() != 5 {
+		t.Errorf("BalanceAt = %v, %v, want 5", balance, err)
+	}
+
+	m = newMulti(t, 2,
+		&fakeBackend{head: 100, balance: 5},
+		&fakeBackend{head: 100, balance: 7},
+		&fakeBackend{head: 100, err: errors.New("connection refused")},
+	)
+	if _, err := m.BalanceAt(ctx, common.Address{}, nil); !errors.Is(err, ethclient.ErrNoQuorum) {
+		t.Errorf("err = %v, want ErrNoQuorum", err)
+	}
+}
+
+func TestMultiBackend_Broadcast(t *testing.T) {
+	down := &fakeBackend{head: 100, err: errors.New("connection refused")}
+	up := &fakeBackend{head: 100}
+	known := &fakeBackend{head: 100, err: &jsonRPCError{code: -32000, msg: "already known"}}
+	m := newMulti(t, 0, down, up, known)
+
+	tx := types.NewTx(&types.LegacyTx{})
+	if err := m.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("广播失败: %v", err)
+	}
+	for i, b := range []*fakeBackend{down, up, known} {
+		if b.sent.Load() != 1 {
+			t.Errorf("节点 %d 收到 %d 次发送, want 1", i, b.sent.Load())
+		}
+	}
+
+	rejected := &jsonRPCError{code: -32000, msg: "nonce too low"}
+	m = newMulti(t, 0, &fakeBackend{head: 100, err: errors.New("EOF")}, &fakeBackend{head: 100, err: rejected})
+	if err := m.SendTransaction(context.Background(), tx); !errors.Is(err, rejected) {
+		t.Errorf("err = %v, want nonce too low", err)
+	}
+}
+
+func TestDialMulti_HidesInvalidURL(t *testing.T) {
+	_, err := ethclient.DialMulti(context.Background(), []string{"https://[mainnet.example/v3/secret-key"}, nil)
+	if err == nil {
+		t.Fatal("无效的节点地址应该报错")
+	}
+	if msg := err.Error(); strings.Contains(msg, "secret-key") || !strings.Contains(msg, "endpoint#0") {
+		t.Errorf("错误信息 = %q，不应包含原始地址", msg)
+	}
+}
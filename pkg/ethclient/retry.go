@@ -0,0 +1,348 @@
+package ethclient
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// RateLimit 单个节点的请求预算
+type RateLimit struct {
+	// RequestsPerSecond 每秒请求数上限，0 表示不限制
+	RequestsPerSecond float64
+	// Burst 允许的突发请求数，0 时取 RequestsPerSecond 向上取整
+	Burst int
+	// MaxConcurrent 同时进行的请求数上限，0 表示不限制
+	MaxConcurrent int
+}
+
+// RetryOptions 重试与限流参数
+type RetryOptions struct {
+	// MaxAttempts 最多请求次数（包含首次），1 表示不重试
+	MaxAttempts int
+	// InitialBackoff 首次重试前的等待时间，之后每次翻倍
+	InitialBackoff time.Duration
+	// MaxBackoff 单次等待时间上限
+	MaxBackoff time.Duration
+	// RateLimit 请求预算，所有重试同样计入
+	RateLimit RateLimit
+}
+
+// DefaultRetryOptions 默认重试参数：最多 4 次请求，不限流
+func DefaultRetryOptions() *RetryOptions {
+	return &RetryOptions{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}
+}
+
+// RetryBackend 为 Backend 加上临时错误重试（指数退避加随机抖动）和请求预算
+type RetryBackend struct {
+	backend Backend
+	opts    *RetryOptions
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+var _ Backend = (*RetryBackend)(nil)
+
+// NewRetryBackend 包装 Backend，opts 为 nil 时使用默认参数
+func NewRetryBackend(backend Backend, opts *RetryOptions) *RetryBackend {
+	if opts == nil {
+		opts = DefaultRetryOptions()
+	}
+
+	r := &RetryBackend{backend: backend, opts: opts}
+	if limit := opts.RateLimit; limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(limit.RequestsPerSecond + 0.999)
+		}
+		r.limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	if opts.RateLimit.MaxConcurrent > 0 {
+		r.slots = make(chan struct{}, opts.RateLimit.MaxConcurrent)
+	}
+	return r
+}
+
+// Close 关闭底层 Backend
+func (r *RetryBackend) Close() {
+	if cl, ok := r.backend.(closer); ok {
+		cl.Close()
+	}
+}
+
+// acquire 等待请求预算，返回释放函数
+func (r *RetryBackend) acquire(ctx context.Context) (func(), error) {
+	if r.limiter != nil {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if r.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case r.slots <- struct{}{}:
+		return func() { <-r.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// backoff 第 attempt 次重试前的等待时间：指数增长，取一半固定加一半随机
+func (r *RetryBackend) backoff(attempt int) time.Duration {
+	d := r.opts.InitialBackoff << (attempt - 1)
+	if d <= 0 || (r.opts.MaxBackoff > 0 && d > r.opts.MaxBackoff) {
+		d = r.opts.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retry 执行请求，临时错误时退避重试，直到成功、遇到永久错误或次数用完
+func retry[T any](ctx context.Context, r *RetryBackend, fn func() (T, error)) (T, error) {
+	var zero T
+	for attempt := 1; ; attempt++ {
+		release, err := r.acquire(ctx)
+		if err != nil {
+			return zero, err
+		}
+		v, err := fn()
+		release()
+
+		if err == nil || !IsTransient(err) || attempt >= r.opts.MaxAttempts || ctx.Err() != nil {
+			return v, err
+		}
+
+		timer := time.NewTimer(r.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, err
+		case <-timer.C:
+		}
+	}
+}
+
+// BatchCallContext 发送批量请求，整批失败且为临时错误时重试，单条请求的错误不重试
+func (r *RetryBackend) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	bc, ok := batchCallerOf(r.backend)
+	if !ok {
+		return ErrBatchUnsupported
+	}
+	_, err := retry(ctx, r, func() (struct{}, error) { return struct{}{}, bc.BatchCallContext(ctx, b) })
+	return err
+}
+
+// EthSubscribe 建立任意 eth_subscribe 订阅，只重试建立订阅的请求
+func (r *RetryBackend) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (ethereum.Subscription, error) {
+	sub, ok := subscriberOf(r.backend)
+	if !ok {
+		return nil, ErrSubscriptionUnsupported
+	}
+	return retry(ctx, r, func() (ethereum.Subscription, error) { return sub.EthSubscribe(ctx, channel, args...) })
+}
+
+// ChainID 返回链 ID
+func (r *RetryBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.ChainID(ctx) })
+}
+
+// BlockNumber 返回最新区块号
+func (r *RetryBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return retry(ctx, r, func() (uint64, error) { return r.backend.BlockNumber(ctx) })
+}
+
+// BlockByHash 按哈希查询区块
+func (r *RetryBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return retry(ctx, r, func() (*types.Block, error) { return r.backend.BlockByHash(ctx, hash) })
+}
+
+// BlockByNumber 按高度查询区块
+func (r *RetryBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return retry(ctx, r, func() (*types.Block, error) { return r.backend.BlockByNumber(ctx, number) })
+}
+
+// HeaderByHash 按哈希查询区块头
+func (r *RetryBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return retry(ctx, r, func() (*types.Header, error) { return r.backend.HeaderByHash(ctx, hash) })
+}
+
+// HeaderByNumber 按高度查询区块头
+func (r *RetryBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return retry(ctx, r, func() (*types.Header, error) { return r.backend.HeaderByNumber(ctx, number) })
+}
+
+// TransactionCount 返回区块中的交易数
+func (r *RetryBackend) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	return retry(ctx, r, func() (uint, error) { return r.backend.TransactionCount(ctx, blockHash) })
+}
+
+// TransactionInBlock 返回区块中指定位置的交易
+func (r *RetryBackend) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	return retry(ctx, r, func() (*types.Transaction, error) { return r.backend.TransactionInBlock(ctx, blockHash, index) })
+}
+
+// SubscribeNewHead 订阅新区块头，只重试建立订阅的请求
+func (r *RetryBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return retry(ctx, r, func() (ethereum.Subscription, error) { return r.backend.SubscribeNewHead(ctx, ch) })
+}
+
+// TransactionByHash 按哈希查询交易
+func (r *RetryBackend) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	var isPending bool
+	tx, err := retry(ctx, r, func() (*types.Transaction, error) {
+		tx, pending, err := r.backend.TransactionByHash(ctx, hash)
+		isPending = pending
+		return tx, err
+	})
+	return tx, isPending, err
+}
+
+// TransactionReceipt 查询交易收据
+func (r *RetryBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return retry(ctx, r, func() (*types.Receipt, error) { return r.backend.TransactionReceipt(ctx, txHash) })
+}
+
+// BalanceAt 查询余额
+func (r *RetryBackend) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.BalanceAt(ctx, account, blockNumber) })
+}
+
+// StorageAt 查询存储槽
+func (r *RetryBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.StorageAt(ctx, account, key, blockNumber) })
+}
+
+// CodeAt 查询合约代码
+func (r *RetryBackend) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.CodeAt(ctx, account, blockNumber) })
+}
+
+// NonceAt 查询已确认 nonce
+func (r *RetryBackend) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return retry(ctx, r, func() (uint64, error) { return r.backend.NonceAt(ctx, account, blockNumber) })
+}
+
+// BalanceAtHash 查询指定区块哈希处的余额
+func (r *RetryBackend) BalanceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.BalanceAtHash(ctx, account, blockHash) })
+}
+
+// StorageAtHash 查询指定区块哈希处的存储槽
+func (r *RetryBackend) StorageAtHash(ctx context.Context, account common.Address, key common.Hash, blockHash common.Hash) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.StorageAtHash(ctx, account, key, blockHash) })
+}
+
+// CodeAtHash 查询指定区块哈希处的合约代码
+func (r *RetryBackend) CodeAtHash(ctx context.Context, account common.Address, blockHash common.Hash) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.CodeAtHash(ctx, account, blockHash) })
+}
+
+// NonceAtHash 查询指定区块哈希处的 nonce
+func (r *RetryBackend) NonceAtHash(ctx context.Context, account common.Address, blockHash common.Hash) (uint64, error) {
+	return retry(ctx, r, func() (uint64, error) { return r.backend.NonceAtHash(ctx, account, blockHash) })
+}
+
+// PendingBalanceAt 查询 pending 状态的余额
+func (r *RetryBackend) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.PendingBalanceAt(ctx, account) })
+}
+
+// PendingStorageAt 查询 pending 状态的存储槽
+func (r *RetryBackend) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.PendingStorageAt(ctx, account, key) })
+}
+
+// PendingCodeAt 查询 pending 状态的合约代码
+func (r *RetryBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.PendingCodeAt(ctx, account) })
+}
+
+// PendingNonceAt 查询 pending nonce
+func (r *RetryBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return retry(ctx, r, func() (uint64, error) { return r.backend.PendingNonceAt(ctx, account) })
+}
+
+// PendingTransactionCount 查询交易池中的交易数
+func (r *RetryBackend) PendingTransactionCount(ctx context.Context) (uint, error) {
+	return retry(ctx, r, func() (uint, error) { return r.backend.PendingTransactionCount(ctx) })
+}
+
+// CallContract 执行 eth_call
+func (r *RetryBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.CallContract(ctx, msg, blockNumber) })
+}
+
+// CallContractAtHash 在指定区块哈希处的状态上执行 eth_call
+func (r *RetryBackend) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.CallContractAtHash(ctx, msg, blockHash) })
+}
+
+// PendingCallContract 在 pending 状态上执行 eth_call
+func (r *RetryBackend) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return retry(ctx, r, func() ([]byte, error) { return r.backend.PendingCallContract(ctx, msg) })
+}
+
+// FilterLogs 查询日志
+func (r *RetryBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return retry(ctx, r, func() ([]types.Log, error) { return r.backend.FilterLogs(ctx, q) })
+}
+
+// SubscribeFilterLogs 订阅日志，只重试建立订阅的请求
+func (r *RetryBackend) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return retry(ctx, r, func() (ethereum.Subscription, error) { return r.backend.SubscribeFilterLogs(ctx, q, ch) })
+}
+
+// SendTransaction 发送交易
+//
+// 超时的请求可能已被节点接受，重试时节点会返回 "already known"、"nonce too low" 等错误，
+// 此时按交易哈希查询，能查到交易即视为发送成功
+func (r *RetryBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	attempts := 0
+	_, err := retry(ctx, r, func() (struct{}, error) {
+		attempts++
+		err := r.backend.SendTransaction(ctx, tx)
+		if err != nil && attempts > 1 && isAlreadySent(err) {
+			if _, _, lookupErr := r.backend.TransactionByHash(ctx, tx.Hash()); lookupErr == nil {
+				return struct{}{}, nil
+			}
+		}
+		return struct{}{}, err
+	})
+	return err
+}
+
+// SuggestGasPrice 返回建议 Gas 价格
+func (r *RetryBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.SuggestGasPrice(ctx) })
+}
+
+// SuggestGasTipCap 返回建议小费
+func (r *RetryBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return retry(ctx, r, func() (*big.Int, error) { return r.backend.SuggestGasTipCap(ctx) })
+}
+
+// FeeHistory 返回 eth_feeHistory 结果
+func (r *RetryBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return retry(ctx, r, func() (*ethereum.FeeHistory, error) {
+		return r.backend.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+	})
+}
+
+// EstimateGas 估算 Gas
+func (r *RetryBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return retry(ctx, r, func() (uint64, error) { return r.backend.EstimateGas(ctx, msg) })
+}
@@ -0,0 +1,196 @@
+package ethclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-eth-learning/pkg/ethclient"
+)
+
+// flakyBackend 前 failures 次请求返回 err
+type flakyBackend struct {
+	ethclient.Backend
+
+	err      error
+	failures int32
+	delay    time.Duration
+
+	calls    atomic.Int32
+	inflight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (f *flakyBackend) BlockNumber(context.Context) (uint64, error) {
+	n := f.inflight.Add(1)
+	defer f.inflight.Add(-1)
+	for {
+		peak := f.peak.Load()
+		if n <= peak || f.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+
+	if f.calls.Add(1) <= f.failures {
+		return 0, f.err
+	}
+	return 100, nil
+}
+
+func fastRetry() *ethclient.RetryOptions {
+	opts := ethclient.DefaultRetryOptions()
+	opts.InitialBackoff = time.Millisecond
+	opts.MaxBackoff = 5 * time.Millisecond
+	return opts
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{rpc.HTTPError{StatusCode: 429}, true},
+		{rpc.HTTPError{StatusCode: 503}, true},
+		{rpc.HTTPError{StatusCode: 401}, false},
+		{&jsonRPCError{code: -32005, msg: "limit exceeded"}, true},
+		{&jsonRPCError{code: -32000, msg: "header not found"}, true},
+		{&jsonRPCError{code: -32005, msg: "query returned more than 10000 results"}, false},
+		{&jsonRPCError{code: 3, msg: "execution reverted"}, false},
+		{&jsonRPCError{code: -32602, msg: "invalid params"}, false},
+		{fmt.Errorf("请求失败: %w", io.ErrUnexpectedEOF), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{ethereum.NotFound, false},
+	}
+
+	for _, tt := range tests {
+		if got := ethclient.IsTransient(tt.err); got != tt.transient {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.transient)
+		}
+	}
+}
+
+func TestRetryBackend_RetriesTransient(t *testing.T) {
+	flaky := &flakyBackend{err: rpc.HTTPError{StatusCode: 503}, failures: 2}
+	r := ethclient.NewRetryBackend(flaky, fastRetry())
+
+	head, err := r.BlockNumber(context.Background())
+	if err != nil || head != 100 {
+		t.Fatalf("BlockNumber = %d, %v", head, err)
+	}
+	if n := flaky.calls.Load(); n != 3 {
+		t.Errorf("请求次数 = %d, want 3", n)
+	}
+
+	// 超过最大次数后返回最后一次的错误
+	flaky = &flakyBackend{err: rpc.HTTPError{StatusCode: 503}, failures: 10}
+	r = ethclient.NewRetryBackend(flaky, fastRetry())
+	if _, err := r.BlockNumber(context.Background()); err == nil || flaky.calls.Load() != 4 {
+		t.Errorf("err = %v, 请求次数 = %d, want 4", err, flaky.calls.Load())
+	}
+}
+
+func TestRetryBackend_PermanentNotRetried(t *testing.T) {
+	invalid := &jsonRPCError{code: -32602, msg: "invalid params"}
+	flaky := &flakyBackend{err: invalid, failures: 1}
+	r := ethclient.NewRetryBackend(flaky, fastRetry())
+
+	if _, err := r.BlockNumber(context.Background()); !errors.Is(err, invalid) {
+		t.Errorf("err = %v, want invalid params", err)
+	}
+	if n := flaky.calls.Load(); n != 1 {
+		t.Errorf("请求次数 = %d, want 1", n)
+	}
+}
+
+func TestRetryBackend_RateLimit(t *testing.T) {
+	opts := fastRetry()
+	opts.RateLimit = ethclient.RateLimit{RequestsPerSecond: 50, Burst: 1, MaxConcurrent: 2}
+	flaky := &flakyBackend{delay: 5 * time.Millisecond}
+	r := ethclient.NewRetryBackend(flaky, opts)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.BlockNumber(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 每秒 50 个请求、突发 1 个：6 个请求至少需要 100ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 个请求耗时 %s，限流未生效", elapsed)
+	}
+	if peak := flaky.peak.Load(); peak > 2 {
+		t.Errorf("最大并发 = %d, want <= 2", peak)
+	}
+}
+
+// sendBackend 第一次发送超时，lost 为 false 时节点其实已经收到交易；之后的发送返回 retryErr
+type sendBackend struct {
+	ethclient.Backend
+
+	retryErr error
+	lost     bool
+	sends    int
+	received map[common.Hash]*types.Transaction
+}
+
+func (b *sendBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.sends++
+	if b.sends == 1 {
+		if !b.lost {
+			b.received[tx.Hash()] = tx
+		}
+		return context.DeadlineExceeded
+	}
+	return b.retryErr
+}
+
+func (b *sendBackend) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if tx, ok := b.received[hash]; ok {
+		return tx, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+func TestRetryBackend_SendTransactionAlreadySent(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Nonce: 7, Gas: 21000})
+	for _, msg := range []string{
+		"already known",
+		"known transaction: 0x1234",
+		"ALREADY_EXISTS: already known",
+		"nonce too low: next nonce 8, tx nonce 7",
+	} {
+		b := &sendBackend{retryErr: &jsonRPCError{code: -32000, msg: msg}, received: make(map[common.Hash]*types.Transaction)}
+		if err := ethclient.NewRetryBackend(b, fastRetry()).SendTransaction(context.Background(), tx); err != nil {
+			t.Errorf("%s: 超时后节点已收到交易，应视为成功: %v", msg, err)
+		}
+		if b.sends != 2 {
+			t.Errorf("%s: 发送次数 = %d, want 2", msg, b.sends)
+		}
+	}
+
+	// 超时的请求没有到达节点，nonce 被同 nonce 的另一笔交易占用：查不到本交易，返回错误
+	nonceErr := &jsonRPCError{code: -32000, msg: "nonce too low"}
+	b := &sendBackend{retryErr: nonceErr, lost: true, received: make(map[common.Hash]*types.Transaction)}
+	if err := ethclient.NewRetryBackend(b, fastRetry()).SendTransaction(context.Background(), tx); !errors.Is(err, nonceErr) || b.sends != 2 {
+		t.Errorf("err = %v, 发送次数 = %d, want nonce too low, 2", err, b.sends)
+	}
+}
@@ -0,0 +1,487 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrSubscriptionUnsupported 节点不支持订阅，也无法用轮询代替
+var ErrSubscriptionUnsupported = errors.New("节点不支持订阅")
+
+// errCodeMethodNotFound JSON-RPC 方法不存在
+const errCodeMethodNotFound = -32601
+
+// Subscriber 支持任意 eth_subscribe 订阅的 Backend
+//
+// RetryBackend 和 MultiBackend 实现了该接口，
+// go-ethereum 的 *ethclient.Client 通过 Client() 暴露底层 *rpc.Client
+type Subscriber interface {
+	EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (ethereum.Subscription, error)
+}
+
+// subscriberOf 取 Backend 的订阅能力
+func subscriberOf(b Backend) (Subscriber, bool) {
+	switch v := b.(type) {
+	case Subscriber:
+		return v, true
+	case interface{ Client() *rpc.Client }:
+		return rpcSubscriber{v.Client()}, true
+	}
+	return nil, false
+}
+
+// rpcSubscriber 把 *rpc.Client 适配为 Subscriber
+type rpcSubscriber struct {
+	client *rpc.Client
+}
+
+func (s rpcSubscriber) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (ethereum.Subscription, error) {
+	return s.client.EthSubscribe(ctx, channel, args...)
+}
+
+// isSubscriptionUnsupported 判断是否为 HTTP 连接或节点未开放订阅
+func isSubscriptionUnsupported(err error) bool {
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) || errors.Is(err, ErrSubscriptionUnsupported) {
+		return true
+	}
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == errCodeMethodNotFound
+}
+
+// SubscribeOptions 订阅参数
+type SubscribeOptions struct {
+	// PollInterval 节点不支持订阅（HTTP）时的轮询间隔
+	PollInterval time.Duration
+	// MinBackoff 订阅断开后第一次重连前的等待时间，之后每次翻倍
+	MinBackoff time.Duration
+	// MaxBackoff 连续重连失败时的等待时间上限
+	MaxBackoff time.Duration
+	// OnError 订阅断开、重连或补齐失败时的回调（可选），用于记录日志
+	OnError func(error)
+}
+
+// DefaultSubscribeOptions 默认订阅参数：3 秒轮询，重连等待 1 秒起、最长 30 秒
+func DefaultSubscribeOptions() *SubscribeOptions {
+	return &SubscribeOptions{
+		PollInterval: 3 * time.Second,
+		MinBackoff:   time.Second,
+		MaxBackoff:   30 * time.Second,
+	}
+}
+
+// SubscribeNewHead 订阅新区块头
+//
+// 连接断开后自动重连，并按区块号补齐断开期间的区块头；节点不支持订阅（HTTP）时改为轮询。
+// 发生重组时同一高度可能收到多个区块头，需要按父哈希处理的场景使用 pkg/reorg。
+// 返回的订阅只在无法恢复时通过 Err() 报告错误，调用 Unsubscribe 后不再写入 ch
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header, opts *SubscribeOptions) (ethereum.Subscription, error) {
+	var next uint64 // 下一个待补齐的区块号，0 表示尚未记录起点
+	catchUp := func(ctx context.Context, emit func(*types.Header) error) error {
+		head, err := c.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("获取最新区块失败: %w", err)
+		}
+		if next == 0 {
+			next = head.Number.Uint64() + 1
+			return nil
+		}
+		for ; next <= head.Number.Uint64(); next++ {
+			header := head
+			if next < head.Number.Uint64() {
+				if header, err = c.client.HeaderByNumber(ctx, new(big.Int).SetUint64(next)); err != nil {
+					return fmt.Errorf("获取区块 %d 失败: %w", next, err)
+				}
+			}
+			if err := emit(header); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	s := &stream[*types.Header, common.Hash]{
+		out: ch,
+		subscribe: func(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+			return c.client.SubscribeNewHead(ctx, ch)
+		},
+		catchUp: catchUp,
+		poll:    catchUp,
+		observe: func(h *types.Header) {
+			next = max(next, h.Number.Uint64()+1)
+		},
+		key: (*types.Header).Hash,
+	}
+	return s.start(ctx, opts)
+}
+
+// logKey 日志去重键，同一条日志被重组移除时会以 Removed 为 true 再推送一次
+type logKey struct {
+	block   common.Hash
+	index   uint
+	removed bool
+}
+
+// SubscribeFilterLogs 订阅匹配 q 的日志，q 的 FromBlock / ToBlock 被忽略
+//
+// 连接断开后自动重连，并用 eth_getLogs 补齐断开期间的日志；节点不支持订阅时改为按区块范围轮询。
+// 订阅推送的日志在重组时会以 Removed 为 true 重新推送，补齐和轮询得到的日志不会
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log, opts *SubscribeOptions) (ethereum.Subscription, error) {
+	q.FromBlock, q.ToBlock = nil, nil
+
+	var (
+		from    uint64 // 下一次补齐的起始区块（含），最后推送的区块可能只收到一部分日志，会重新查询
+		started bool
+	)
+	catchUp := func(ctx context.Context, emit func(types.Log) error) error {
+		head, err := c.client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("获取区块号失败: %w", err)
+		}
+		if !started {
+			from, started = head+1, true
+			return nil
+		}
+		if from > head {
+			return nil
+		}
+
+		query := q
+		query.FromBlock = new(big.Int).SetUint64(from)
+		query.ToBlock = new(big.Int).SetUint64(head)
+		logs, err := c.client.FilterLogs(ctx, query)
+		if err != nil {
+			return fmt.Errorf("查询区块 %d-%d 日志失败: %w", from, head, err)
+		}
+		for _, l := range logs {
+			if err := emit(l); err != nil {
+				return err
+			}
+		}
+		from = head + 1
+		return nil
+	}
+
+	s := &stream[types.Log, logKey]{
+		out: ch,
+		subscribe: func(ctx context.Context, ch chan<- types.Log) (ethereum.Subscription, error) {
+			return c.client.SubscribeFilterLogs(ctx, q, ch)
+		},
+		catchUp: catchUp,
+		poll:    catchUp,
+		observe: func(l types.Log) {
+			if !l.Removed && l.BlockNumber >= from {
+				from = l.BlockNumber
+			}
+		},
+		key: func(l types.Log) logKey {
+			return logKey{block: l.BlockHash, index: l.Index, removed: l.Removed}
+		},
+	}
+	return s.start(ctx, opts)
+}
+
+// SubscribePendingTransactions 订阅进入节点交易池的交易哈希（newPendingTransactions）
+//
+// 连接断开后自动重连，但断开期间的交易无法补齐；节点不支持订阅时改用
+// eth_newPendingTransactionFilter 轮询，两者都不支持时返回 ErrSubscriptionUnsupported
+func (c *Client) SubscribePendingTransactions(ctx context.Context, ch chan<- common.Hash, opts *SubscribeOptions) (ethereum.Subscription, error) {
+	var filterID string
+	poll := func(ctx context.Context, emit func(common.Hash) error) error {
+		bc, ok := batchCallerOf(c.client)
+		if !ok {
+			return ErrSubscriptionUnsupported
+		}
+		if filterID == "" {
+			if err := callContext(ctx, bc, &filterID, "eth_newPendingTransactionFilter"); err != nil {
+				if isSubscriptionUnsupported(err) {
+					return fmt.Errorf("%w: %w", ErrSubscriptionUnsupported, err)
+				}
+				return fmt.Errorf("创建交易池过滤器失败: %w", err)
+			}
+			return nil
+		}
+
+		var hashes []common.Hash
+		if err := callContext(ctx, bc, &hashes, "eth_getFilterChanges", filterID); err != nil {
+			// 过滤器长时间未读取会被节点删除，下一轮重新创建
+			if strings.Contains(strings.ToLower(err.Error()), "filter not found") {
+				filterID = ""
+			}
+			return fmt.Errorf("读取交易池过滤器失败: %w", err)
+		}
+		for _, h := range hashes {
+			if err := emit(h); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	s := &stream[common.Hash, common.Hash]{
+		out: ch,
+		subscribe: func(ctx context.Context, ch chan<- common.Hash) (ethereum.Subscription, error) {
+			sub, ok := subscriberOf(c.client)
+			if !ok {
+				return nil, ErrSubscriptionUnsupported
+			}
+			return sub.EthSubscribe(ctx, ch, "newPendingTransactions")
+		},
+		poll: poll,
+		key:  func(h common.Hash) common.Hash { return h },
+	}
+	return s.start(ctx, opts)
+}
+
+// callContext 用单条批量请求发送任意 JSON-RPC 调用
+func callContext(ctx context.Context, bc BatchCaller, result interface{}, method string, args ...interface{}) error {
+	elems := []rpc.BatchElem{{Method: method, Args: args, Result: result}}
+	if err := bc.BatchCallContext(ctx, elems); err != nil {
+		return err
+	}
+	return elems[0].Error
+}
+
+// stream 自动恢复的订阅：订阅断开后退避重连，重连后补齐断开期间的数据，节点不支持订阅时改为轮询
+//
+// 补齐和订阅推送的数据可能重叠，按 key 去掉最近已发送过的数据。
+// 除 start 中的首次连接外，所有回调都只在后台 goroutine 中调用，闭包中的状态不需要加锁
+type stream[T any, K comparable] struct {
+	out  chan<- T
+	opts SubscribeOptions
+
+	// subscribe 建立底层订阅
+	subscribe func(ctx context.Context, ch chan<- T) (ethereum.Subscription, error)
+	// catchUp 订阅建立后补齐上次位置到当前的数据，首次调用只记录起点；为 nil 时不补齐
+	catchUp func(ctx context.Context, emit func(T) error) error
+	// poll 轮询一次新数据，首次调用只记录起点
+	poll func(ctx context.Context, emit func(T) error) error
+	// observe 记录订阅推送的数据位置（可选）
+	observe func(T)
+	key     func(T) K
+
+	sent recentSet[K]
+}
+
+// streamBuffer 底层订阅通道的缓冲，补齐期间推送的数据暂存在这里
+const streamBuffer = 128
+
+// start 同步建立首次订阅（或记录轮询起点），之后在后台维持订阅
+func (s *stream[T, K]) start(ctx context.Context, opts *SubscribeOptions) (ethereum.Subscription, error) {
+	defaults := DefaultSubscribeOptions()
+	if opts == nil {
+		opts = defaults
+	}
+	s.opts = *opts
+	if s.opts.PollInterval <= 0 {
+		s.opts.PollInterval = defaults.PollInterval
+	}
+	if s.opts.MinBackoff <= 0 {
+		s.opts.MinBackoff = defaults.MinBackoff
+	}
+	if s.opts.MaxBackoff < s.opts.MinBackoff {
+		s.opts.MaxBackoff = max(defaults.MaxBackoff, s.opts.MinBackoff)
+	}
+	s.sent.init(4096)
+
+	ctx, cancel := context.WithCancel(ctx)
+	inner, ch, err := s.connect(ctx)
+	polling := isSubscriptionUnsupported(err)
+	if polling {
+		err = s.poll(ctx, s.emitter(ctx))
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	sub := &resubscription{cancel: cancel, err: make(chan error, 1), done: make(chan struct{})}
+	go func() {
+		defer close(sub.done)
+		if err := s.run(ctx, inner, ch, polling); err != nil && !sub.unsubscribed.Load() {
+			sub.err <- err
+		}
+	}()
+	return sub, nil
+}
+
+// connect 建立底层订阅并补齐数据
+func (s *stream[T, K]) connect(ctx context.Context) (ethereum.Subscription, chan T, error) {
+	ch := make(chan T, streamBuffer)
+	inner, err := s.subscribe(ctx, ch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.catchUp != nil {
+		if err := s.catchUp(ctx, s.emitter(ctx)); err != nil {
+			inner.Unsubscribe()
+			return nil, nil, err
+		}
+	}
+	return inner, ch, nil
+}
+
+// run 转发订阅数据，断开时重连；进入轮询模式后不再尝试订阅
+func (s *stream[T, K]) run(ctx context.Context, inner ethereum.Subscription, ch chan T, polling bool) error {
+	emit := s.emitter(ctx)
+	backoff := s.opts.MinBackoff
+	for {
+		if polling {
+			if err := sleep(ctx, s.opts.PollInterval); err != nil {
+				return err
+			}
+			if err := s.poll(ctx, emit); err != nil {
+				if ctx.Err() != nil || errors.Is(err, ErrSubscriptionUnsupported) {
+					return err
+				}
+				s.report(err)
+			}
+			continue
+		}
+
+		err := s.forward(ctx, inner, ch, emit)
+		inner.Unsubscribe()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.report(fmt.Errorf("订阅断开: %w", err))
+
+		for {
+			if err := sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff = min(backoff*2, s.opts.MaxBackoff)
+
+			inner, ch, err = s.connect(ctx)
+			if err == nil {
+				backoff = s.opts.MinBackoff
+				break
+			}
+			if isSubscriptionUnsupported(err) {
+				polling = true
+				break
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s.report(fmt.Errorf("重新订阅失败: %w", err))
+		}
+	}
+}
+
+// forward 把底层订阅的数据转发给调用方，直到订阅出错或 ctx 结束
+func (s *stream[T, K]) forward(ctx context.Context, inner ethereum.Subscription, ch chan T, emit func(T) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-inner.Err():
+			if err == nil {
+				err = errors.New("订阅被关闭")
+			}
+			return err
+		case v := <-ch:
+			if s.observe != nil {
+				s.observe(v)
+			}
+			if err := emit(v); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// emitter 返回发送函数：跳过最近发送过的数据，调用方读取缓慢时阻塞
+func (s *stream[T, K]) emitter(ctx context.Context) func(T) error {
+	return func(v T) error {
+		if !s.sent.add(s.key(v)) {
+			return nil
+		}
+		select {
+		case s.out <- v:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *stream[T, K]) report(err error) {
+	if s.opts.OnError != nil {
+		s.opts.OnError(err)
+	}
+}
+
+// sleep 等待 d，ctx 结束时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// resubscription 返回给调用方的订阅，Unsubscribe 等待后台 goroutine 退出后关闭 Err 通道
+type resubscription struct {
+	cancel       context.CancelFunc
+	err          chan error
+	done         chan struct{}
+	once         sync.Once
+	unsubscribed atomic.Bool
+}
+
+// Unsubscribe 取消订阅
+func (r *resubscription) Unsubscribe() {
+	r.once.Do(func() {
+		r.unsubscribed.Store(true)
+		r.cancel()
+		<-r.done
+		close(r.err)
+	})
+}
+
+// Err 返回无法恢复的订阅错误，Unsubscribe 后关闭
+func (r *resubscription) Err() <-chan error {
+	return r.err
+}
+
+// recentSet 记住最近 n 个键，超出时淘汰最早加入的
+type recentSet[K comparable] struct {
+	keys  map[K]struct{}
+	order []K
+	next  int
+}
+
+func (r *recentSet[K]) init(n int) {
+	r.keys = make(map[K]struct{}, n)
+	r.order = make([]K, 0, n)
+}
+
+// add 加入键，已存在时返回 false
+func (r *recentSet[K]) add(k K) bool {
+	if _, ok := r.keys[k]; ok {
+		return false
+	}
+	if len(r.order) < cap(r.order) {
+		r.order = append(r.order, k)
+	} else {
+		delete(r.keys, r.order[r.next])
+		r.order[r.next] = k
+		r.next = (r.next + 1) % len(r.order)
+	}
+	r.keys[k] = struct{}{}
+	return true
+}
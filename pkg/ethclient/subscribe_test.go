@@ -0,0 +1,259 @@
+package ethclient_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/ethclient"
+)
+
+// droppingBackend 可以模拟连接断开和只支持 HTTP 的节点
+type droppingBackend struct {
+	ethclient.Backend
+
+	mu   sync.Mutex
+	http bool
+	down bool
+	subs []*droppingSub
+}
+
+// droppingSub 可以从外部注入断开错误的订阅
+type droppingSub struct {
+	ethereum.Subscription
+	err chan error
+}
+
+func (s *droppingSub) Err() <-chan error { return s.err }
+
+func (b *droppingBackend) wrap(sub ethereum.Subscription, err error) (ethereum.Subscription, error) {
+	if err != nil {
+		return nil, err
+	}
+	fs := &droppingSub{Subscription: sub, err: make(chan error, 1)}
+	b.subs = append(b.subs, fs)
+	return fs, nil
+}
+
+func (b *droppingBackend) check() error {
+	if b.http {
+		return rpc.ErrNotificationsUnsupported
+	}
+	if b.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (b *droppingBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	return b.wrap(b.Backend.SubscribeNewHead(ctx, ch))
+}
+
+func (b *droppingBackend) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	return b.wrap(b.Backend.SubscribeFilterLogs(ctx, q, ch))
+}
+
+// drop 断开所有订阅，之后的订阅请求失败直到 restore
+func (b *droppingBackend) drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.down = true
+	for _, s := range b.subs {
+		s.Subscription.Unsubscribe()
+		s.err <- errors.New("websocket: close 1006")
+	}
+	b.subs = nil
+}
+
+func (b *droppingBackend) restore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.down = false
+}
+
+// receive 从 ch 读取 n 个值，超时则测试失败
+func receive[T any](t *testing.T, ch <-chan T, n int) []T {
+	t.Helper()
+	var got []T
+	timeout := time.After(5 * time.Second)
+	for len(got) < n {
+		select {
+		case v := <-ch:
+			got = append(got, v)
+		case <-timeout:
+			t.Fatalf("收到 %d 个, want %d", len(got), n)
+		}
+	}
+	// 不应再有重复的数据
+	select {
+	case v := <-ch:
+		t.Fatalf("多收到 %v", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return got
+}
+
+func subscribeOptions() *ethclient.SubscribeOptions {
+	opts := ethclient.DefaultSubscribeOptions()
+	opts.PollInterval = 10 * time.Millisecond
+	opts.MinBackoff = 10 * time.Millisecond
+	return opts
+}
+
+func newDroppingClient(t *testing.T, chain *testchain.Chain) (*ethclient.Client, *droppingBackend) {
+	t.Helper()
+	backend := &droppingBackend{Backend: chain.Eth()}
+	client, err := ethclient.NewFromBackend(context.Background(), backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, backend
+}
+
+func headNumbers(headers []*types.Header) []uint64 {
+	numbers := make([]uint64, len(headers))
+	for i, h := range headers {
+		numbers[i] = h.Number.Uint64()
+	}
+	return numbers
+}
+
+func TestSubscribeNewHead_Reconnect(t *testing.T) {
+	chain := testchain.New(t, nil)
+	client, backend := newDroppingClient(t, chain)
+
+	heads := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(context.Background(), heads, subscribeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	chain.Mine(2)
+	if got := headNumbers(receive(t, heads, 2)); got[0] != 1 || got[1] != 2 {
+		t.Fatalf("区块 = %v, want [1 2]", got)
+	}
+
+	// 断开期间出的区块在重连后补齐，重连前后都不重复
+	backend.drop()
+	chain.Mine(3)
+	backend.restore()
+	chain.Mine(1)
+
+	got := headNumbers(receive(t, heads, 4))
+	for i, n := range got {
+		if n != uint64(3+i) {
+			t.Fatalf("区块 = %v, want [3 4 5 6]", got)
+		}
+	}
+}
+
+func TestSubscribeNewHead_PollingFallback(t *testing.T) {
+	chain := testchain.New(t, nil)
+	client, backend := newDroppingClient(t, chain)
+	backend.http = true
+
+	heads := make(chan *types.Header, 16)
+	sub, err := client.SubscribeNewHead(context.Background(), heads, subscribeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain.Mine(2)
+	if got := headNumbers(receive(t, heads, 2)); got[0] != 1 || got[1] != 2 {
+		t.Errorf("区块 = %v, want [1 2]", got)
+	}
+
+	sub.Unsubscribe()
+	if _, ok := <-sub.Err(); ok {
+		t.Error("Unsubscribe 后 Err 通道应关闭")
+	}
+}
+
+func TestSubscribeFilterLogs_Reconnect(t *testing.T) {
+	chain := testchain.New(t, nil)
+	client, backend := newDroppingClient(t, chain)
+	ctx := context.Background()
+
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	token := chain.DeployMyToken(alice, 1000)
+	transfer := func() {
+		t.Helper()
+		tx, err := token.Transfer(ctx, alice.Signer, bob.Address, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("转账失败: %v", err)
+		}
+		chain.MustMine(tx)
+	}
+
+	logs := make(chan types.Log, 16)
+	sub, err := client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{token.Address}}, logs, subscribeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	transfer()
+	backend.drop()
+	transfer()
+	transfer()
+	backend.restore()
+	transfer()
+
+	got := receive(t, logs, 4)
+	for i := 1; i < len(got); i++ {
+		if got[i].BlockNumber != got[i-1].BlockNumber+1 {
+			t.Fatalf("日志区块不连续: %d 之后是 %d", got[i-1].BlockNumber, got[i].BlockNumber)
+		}
+	}
+}
+
+func TestSubscribePendingTransactions(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+
+	hashes := make(chan common.Hash, 16)
+	sub, err := chain.Client().SubscribePendingTransactions(ctx, hashes, subscribeOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	want := make(map[common.Hash]bool)
+	for i := 0; i < 2; i++ {
+		tx, err := chain.TxManager().Send(ctx, alice.Signer, &bob.Address, big.NewInt(params.GWei), nil)
+		if err != nil {
+			t.Fatalf("发送交易失败: %v", err)
+		}
+		want[tx.Hash()] = true
+	}
+
+	for _, h := range receive(t, hashes, 2) {
+		if !want[h] {
+			t.Errorf("收到未知交易 %s", h.Hex())
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxStatus 等待交易的最终结果
+type TxStatus int
+
+const (
+	// TxSuccess 交易已上链且执行成功
+	TxSuccess TxStatus = iota + 1
+	// TxReverted 交易已上链但执行失败
+	TxReverted
+	// TxDropped 交易从交易池消失且 nonce 未被使用
+	TxDropped
+	// TxReplaced 同 nonce 的另一笔交易已上链
+	TxReplaced
+	// TxTimeout 超时仍未得到结果
+	TxTimeout
+)
+
+// String 返回状态名称
+func (s TxStatus) String() string {
+	switch s {
+	case TxSuccess:
+		return "success"
+	case TxReverted:
+		return "reverted"
+	case TxDropped:
+		return "dropped"
+	case TxReplaced:
+		return "replaced"
+	case TxTimeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// WaitOptions 等待参数
+type WaitOptions struct {
+	// Confirmations 需要的确认数，包含交易所在区块（1 表示上链即可）
+	Confirmations uint64
+	// Timeout 总超时时间，0 表示只受 ctx 控制
+	Timeout time.Duration
+	// PollInterval 无法订阅 newHeads 时的轮询间隔
+	PollInterval time.Duration
+	// DroppedAfter 交易从节点消失多久后判定为被丢弃
+	DroppedAfter time.Duration
+}
+
+// DefaultWaitOptions 默认等待参数
+func DefaultWaitOptions() *WaitOptions {
+	return &WaitOptions{
+		Confirmations: 1,
+		Timeout:       5 * time.Minute,
+		PollInterval:  3 * time.Second,
+		DroppedAfter:  time.Minute,
+	}
+}
+
+// WaitResult 等待结果
+type WaitResult struct {
+	Status        TxStatus
+	Receipt       *types.Receipt // 仅 TxSuccess / TxReverted 时有值
+	Confirmations uint64
+	Reorgs        int // 等待期间交易所在区块被重组的次数
+}
+
+// waitState 跟踪交易的最新观测信息
+type waitState struct {
+	hash     common.Hash
+	from     common.Address
+	nonce    uint64
+	known    bool      // 已获取发送者和 nonce
+	lastSeen time.Time // 最近一次在节点上看到交易的时间
+	block    common.Hash
+	reorgs   int
+}
+
+// WaitMined 等待交易上链并达到指定确认数，期间处理重组、替换和丢弃
+//
+// opts 中为 0 的字段使用默认值，opts 本身不会被修改，可以在多次调用之间共享
+func (c *Client) WaitMined(ctx context.Context, txHash string, opts *WaitOptions) (*WaitResult, error) {
+	defaults := DefaultWaitOptions()
+	if opts == nil {
+		opts = defaults
+	}
+	o := *opts
+	opts = &o
+	if opts.Confirmations == 0 {
+		opts.Confirmations = defaults.Confirmations
+	}
+	if opts.DroppedAfter == 0 {
+		opts.DroppedAfter = defaults.DroppedAfter
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	heads, stop := c.headTicker(ctx, opts.PollInterval)
+	defer stop()
+
+	st := &waitState{hash: common.HexToHash(txHash), lastSeen: time.Now()}
+	for {
+		result, err := c.checkTx(ctx, st, opts)
+		if err != nil && ctx.Err() == nil && !expired(ctx) {
+			return nil, err
+		}
+		if result != nil {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &WaitResult{Status: TxTimeout, Reorgs: st.reorgs}, nil
+			}
+			return nil, ctx.Err()
+		case <-heads:
+		}
+	}
+}
+
+// expired 判断 ctx 是否已到截止时间
+//
+// 请求可能因连接的截止时间先于 ctx 的定时器报错，此时 ctx.Err() 仍为 nil，应按超时处理
+func expired(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && !time.Now().Before(deadline)
+}
+
+// checkTx 检查一次交易状态，返回 nil 表示需要继续等待
+func (c *Client) checkTx(ctx context.Context, st *waitState, opts *WaitOptions) (*WaitResult, error) {
+	receipt, err := c.client.TransactionReceipt(ctx, st.hash)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("获取交易收据失败: %w", err)
+	}
+
+	if receipt != nil {
+		st.lastSeen = time.Now()
+		return c.checkConfirmations(ctx, st, receipt, opts)
+	}
+
+	// 收据消失说明所在区块被重组
+	if st.block != (common.Hash{}) {
+		st.block = common.Hash{}
+		st.reorgs++
+	}
+
+	tx, _, err := c.client.TransactionByHash(ctx, st.hash)
+	switch {
+	case err == nil:
+		st.lastSeen = time.Now()
+		if !st.known {
+			from, err := types.Sender(types.LatestSignerForChainID(c.chainID), tx)
+			if err != nil {
+				return nil, fmt.Errorf("解析交易发送者失败: %w", err)
+			}
+			st.from, st.nonce, st.known = from, tx.Nonce(), true
+		}
+	case !errors.Is(err, ethereum.NotFound):
+		return nil, fmt.Errorf("查询交易失败: %w", err)
+	}
+
+	if !st.known {
+		if time.Since(st.lastSeen) >= opts.DroppedAfter {
+			return &WaitResult{Status: TxDropped, Reorgs: st.reorgs}, nil
+		}
+		return nil, nil
+	}
+
+	// nonce 已被使用但本交易没有收据：被同 nonce 的其他交易替换
+	confirmedNonce, err := c.client.NonceAt(ctx, st.from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取 nonce 失败: %w", err)
+	}
+	if confirmedNonce > st.nonce {
+		// 收据可能刚好在两次查询之间出现，再确认一次
+		if receipt, err := c.client.TransactionReceipt(ctx, st.hash); err == nil {
+			return c.checkConfirmations(ctx, st, receipt, opts)
+		}
+		return &WaitResult{Status: TxReplaced, Reorgs: st.reorgs}, nil
+	}
+
+	if time.Since(st.lastSeen) < opts.DroppedAfter {
+		return nil, nil
+	}
+	return &WaitResult{Status: TxDropped, Reorgs: st.reorgs}, nil
+}
+
+// checkConfirmations 确认收据所在区块仍在主链上，并计算确认数
+func (c *Client) checkConfirmations(ctx context.Context, st *waitState, receipt *types.Receipt, opts *WaitOptions) (*WaitResult, error) {
+	header, err := c.client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return nil, fmt.Errorf("获取区块头失败: %w", err)
+	}
+	if header == nil || header.Hash() != receipt.BlockHash {
+		// 节点返回了已被重组掉的收据，等待新的主链
+		if st.block != (common.Hash{}) {
+			st.reorgs++
+		}
+		st.block = common.Hash{}
+		return nil, nil
+	}
+	if st.block != (common.Hash{}) && st.block != receipt.BlockHash {
+		st.reorgs++
+	}
+	st.block = receipt.BlockHash
+
+	latest, err := c.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取区块号失败: %w", err)
+	}
+
+	var confirmations uint64
+	if latest >= receipt.BlockNumber.Uint64() {
+		confirmations = latest - receipt.BlockNumber.Uint64() + 1
+	}
+	if confirmations < opts.Confirmations {
+		return nil, nil
+	}
+
+	status := TxSuccess
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = TxReverted
+	}
+	return &WaitResult{
+		Status:        status,
+		Receipt:       receipt,
+		Confirmations: confirmations,
+		Reorgs:        st.reorgs,
+	}, nil
+}
+
+// headTicker 返回新区块通知通道：优先订阅 newHeads，不支持订阅（HTTP）时按间隔轮询
+func (c *Client) headTicker(ctx context.Context, interval time.Duration) (<-chan struct{}, func()) {
+	if interval <= 0 {
+		interval = DefaultWaitOptions().PollInterval
+	}
+
+	notify := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(ctx)
+
+	headers := make(chan *types.Header, 16)
+	sub, err := c.client.SubscribeNewHead(ctx, headers)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var subErr <-chan error
+		if err == nil {
+			defer sub.Unsubscribe()
+			subErr = sub.Err()
+			// 订阅正常时轮询只作为兜底
+			ticker.Reset(interval * 10)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-headers:
+			case <-ticker.C:
+			case <-subErr:
+				// 订阅断开，退回轮询
+				subErr = nil
+				ticker.Reset(interval)
+				continue
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return notify, cancel
+}
@@ -0,0 +1,206 @@
+package ethclient_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+)
+
+// fastWait 测试用的等待参数，轮询间隔很短
+func fastWait() *ethclient.WaitOptions {
+	return &ethclient.WaitOptions{PollInterval: 10 * time.Millisecond, DroppedAfter: 200 * time.Millisecond}
+}
+
+// waitAsync 在后台等待交易，返回结果通道
+func waitAsync(t *testing.T, chain *testchain.Chain, hash common.Hash, opts *ethclient.WaitOptions) <-chan *ethclient.WaitResult {
+	t.Helper()
+	done := make(chan *ethclient.WaitResult, 1)
+	go func() {
+		result, err := chain.Client().WaitMined(context.Background(), hash.Hex(), opts)
+		if err != nil {
+			t.Errorf("等待交易失败: %v", err)
+		}
+		done <- result
+	}()
+	// 让等待方先观察到交易当前的状态
+	time.Sleep(100 * time.Millisecond)
+	return done
+}
+
+func waitResult(t *testing.T, done <-chan *ethclient.WaitResult) *ethclient.WaitResult {
+	t.Helper()
+	select {
+	case result := <-done:
+		if result == nil {
+			t.FailNow()
+		}
+		return result
+	case <-time.After(10 * time.Second):
+		t.Fatal("WaitMined 没有返回")
+		return nil
+	}
+}
+
+// signLegacy 用第一个预置账户签名 legacy 交易
+func signLegacy(t *testing.T, chain *testchain.Chain, nonce uint64, to common.Address, gasPrice *big.Int, gas uint64, data []byte) *types.Transaction {
+	t.Helper()
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gas, To: &to, Data: data})
+	signed, err := chain.Accounts[0].Signer.SignTx(context.Background(), tx, chain.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestWaitMined_Reverted(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	token := chain.DeployMyToken(alice, 1000)
+
+	// 转出超过余额的代币，固定 gas 跳过估算，交易上链但执行失败
+	erc20, err := contract.ParseERC20ABI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := erc20.Pack("transfer", bob.Address, new(big.Int).Lsh(big.NewInt(1), 200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := chain.Eth().PendingNonceAt(ctx, alice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := signLegacy(t, chain, nonce, token.Address, big.NewInt(10*params.GWei), 100_000, data)
+	if err := chain.Eth().SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := chain.Client().WaitMined(ctx, tx.Hash().Hex(), fastWait())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ethclient.TxReverted || result.Receipt == nil || result.Receipt.Status != types.ReceiptStatusFailed {
+		t.Errorf("等待结果 = %+v", result)
+	}
+}
+
+func TestWaitMined_Dropped(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+
+	tx, err := chain.TxManager().Send(ctx, chain.Accounts[0].Signer, &chain.Accounts[1].Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := waitAsync(t, chain, tx.Hash(), fastWait())
+
+	// 交易从待打包区块中消失，nonce 没有被使用
+	chain.Rollback()
+	if result := waitResult(t, done); result.Status != ethclient.TxDropped {
+		t.Errorf("等待结果 = %+v", result)
+	}
+}
+
+func TestWaitMined_Replaced(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+	alice := chain.Accounts[0]
+
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &chain.Accounts[1].Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := waitAsync(t, chain, tx.Hash(), fastWait())
+
+	if _, err := chain.TxManager().Cancel(ctx, alice.Signer, tx.Hash()); err != nil {
+		t.Fatalf("取消交易失败: %v", err)
+	}
+	chain.Commit()
+	if result := waitResult(t, done); result.Status != ethclient.TxReplaced {
+		t.Errorf("等待结果 = %+v", result)
+	}
+}
+
+func TestWaitMined_ReorgedOut(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice := chain.Accounts[0]
+
+	chain.Mine(1)
+	parent, err := chain.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := chain.TxManager().Send(ctx, alice.Signer, &chain.Accounts[1].Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitOpts := fastWait()
+	waitOpts.Confirmations = 3
+	waitOpts.DroppedAfter = time.Minute // 交易重新发送前不应判定为丢弃
+	done := waitAsync(t, chain, tx.Hash(), waitOpts)
+
+	// 交易所在区块被更长的分叉替换，收据消失
+	chain.Fork(parent.Hash())
+	chain.AdjustTime(time.Second)
+	chain.Mine(2)
+	if _, err := chain.Eth().TransactionReceipt(ctx, tx.Hash()); err == nil {
+		t.Fatal("重组后不应有收据")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// 交易重新打包进新主链，确认数足够后成功
+	if err := chain.Eth().SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("重新发送交易失败: %v", err)
+	}
+	chain.Mine(2)
+
+	result := waitResult(t, done)
+	if result.Status != ethclient.TxSuccess || result.Reorgs != 1 || result.Confirmations < 3 {
+		t.Fatalf("等待结果 = %+v", result)
+	}
+	if want := parent.Number.Uint64() + 3; result.Receipt.BlockNumber.Uint64() != want {
+		t.Errorf("收据区块 = %d, want %d", result.Receipt.BlockNumber, want)
+	}
+}
+
+func TestWaitMined_Timeout(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+
+	tx, err := chain.TxManager().Send(ctx, chain.Accounts[0].Signer, &chain.Accounts[1].Address, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitOpts := &ethclient.WaitOptions{Timeout: 100 * time.Millisecond, PollInterval: 10 * time.Millisecond}
+	result, err := chain.Client().WaitMined(ctx, tx.Hash().Hex(), waitOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != ethclient.TxTimeout {
+		t.Errorf("等待结果 = %+v", result)
+	}
+
+	// 默认值只用于本次等待，不写回调用方的参数
+	if waitOpts.Confirmations != 0 || waitOpts.DroppedAfter != 0 {
+		t.Errorf("WaitMined 修改了参数: %+v", waitOpts)
+	}
+}
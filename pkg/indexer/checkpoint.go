@@ -0,0 +1,117 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Checkpoint 最后处理完成的区块
+type Checkpoint struct {
+	Block uint64
+	Hash  common.Hash // 区块哈希，重启时与主链对比以发现停机期间的重组；旧版本保存的检查点为空
+}
+
+// CheckpointStore 保存最后处理完成的区块，重启后从下一个区块继续
+type CheckpointStore interface {
+	// Load 读取检查点，ok 为 false 表示尚未保存过
+	Load(ctx context.Context) (cp Checkpoint, ok bool, err error)
+	// Save 在区块及之前的日志全部处理完成后调用
+	Save(ctx context.Context, cp Checkpoint) error
+}
+
+// MemoryCheckpoint 内存检查点，用于测试或不需要断点续传的场景
+type MemoryCheckpoint struct {
+	mu sync.Mutex
+	cp Checkpoint
+	ok bool
+}
+
+// Load 读取检查点
+func (m *MemoryCheckpoint) Load(context.Context) (Checkpoint, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cp, m.ok, nil
+}
+
+// Save 保存检查点
+func (m *MemoryCheckpoint) Save(_ context.Context, cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cp, m.ok = cp, true
+	return nil
+}
+
+// FileCheckpoint JSON 文件检查点，先写临时文件再重命名，进程中途退出不会留下半截文件
+type FileCheckpoint struct {
+	path string
+}
+
+// checkpointFile 检查点文件内容
+type checkpointFile struct {
+	Block     uint64       `json:"block"`
+	Hash      *common.Hash `json:"hash,omitempty"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// NewFileCheckpoint 创建文件检查点，文件不存在时视为尚未保存
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{path: path}
+}
+
+// Path 返回检查点文件路径
+func (f *FileCheckpoint) Path() string {
+	return f.path
+}
+
+// Load 读取检查点
+func (f *FileCheckpoint) Load(context.Context) (Checkpoint, bool, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, fmt.Errorf("读取检查点失败: %w", err)
+	}
+
+	var file checkpointFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Checkpoint{}, false, fmt.Errorf("解析检查点 %s 失败: %w", f.path, err)
+	}
+	cp := Checkpoint{Block: file.Block}
+	if file.Hash != nil {
+		cp.Hash = *file.Hash
+	}
+	return cp, true, nil
+}
+
+// Save 保存检查点
+func (f *FileCheckpoint) Save(_ context.Context, cp Checkpoint) error {
+	file := checkpointFile{Block: cp.Block, UpdatedAt: time.Now().UTC()}
+	if cp.Hash != (common.Hash{}) {
+		file.Hash = &cp.Hash
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("创建检查点目录失败: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("写入检查点失败: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("写入检查点失败: %w", err)
+	}
+	return nil
+}
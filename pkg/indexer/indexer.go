@@ -0,0 +1,287 @@
+// Package indexer 提供可断点续传的日志索引：分段并发回填历史区块，完成后无缝切换到跟踪新区块
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+)
+
+// Batch 一个区块区间 [From, To] 内的全部日志，按区块和日志序号排列
+type Batch struct {
+	From uint64
+	To   uint64
+	Hash common.Hash // To 区块的哈希，随检查点保存
+	Logs []types.Log
+}
+
+// Handler 处理一个区间的日志；返回错误时索引停止，检查点停在上一个区间
+//
+// 区间按区块顺序依次交给 Handler，不会并发调用；进程在 Handler 返回后、检查点保存前退出时，
+// 重启后会再次收到同一个区间，Handler 需要按 (TxHash, Index) 幂等处理
+type Handler func(ctx context.Context, batch Batch) error
+
+// Options 索引参数
+type Options struct {
+	FromBlock     uint64        // 没有检查点时的起始区块
+	ChunkSize     uint64        // 每次 eth_getLogs 查询的最大区块数，节点提示结果过多时自动减半
+	Concurrency   int           // 回填时并发查询的区间数
+	Confirmations uint64        // 只处理已有 n 个确认的区块，降低重组影响
+	PollInterval  time.Duration // 追上最新区块后的轮询间隔
+	OnError       func(error)   // Run 遇到临时错误重试前的回调（可选），用于记录日志
+}
+
+// DefaultOptions 默认参数：每段 2000 个区块、4 路并发、12 秒轮询
+func DefaultOptions() *Options {
+	return &Options{
+		ChunkSize:    2000,
+		Concurrency:  4,
+		PollInterval: 12 * time.Second,
+	}
+}
+
+// Indexer 日志索引器
+//
+// Sync 和 Run 不能并发调用
+type Indexer struct {
+	backend ethclient.Backend
+	query   ethereum.FilterQuery
+	store   CheckpointStore
+	handler Handler
+	opts    Options
+
+	next   uint64 // 下一个待处理的区块
+	loaded bool
+	chunk  atomic.Uint64 // 当前使用的区块段大小
+	shrunk atomic.Bool   // 本轮是否因结果过多缩小过区块段
+}
+
+// handlerError Handler 返回的错误，Run 不重试
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// New 创建索引器，query 的 FromBlock / ToBlock 由索引器填写，store 为 nil 时使用内存检查点
+func New(backend ethclient.Backend, query ethereum.FilterQuery, store CheckpointStore, handler Handler, opts *Options) *Indexer {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	if store == nil {
+		store = &MemoryCheckpoint{}
+	}
+
+	ix := &Indexer{
+		backend: backend,
+		query:   query,
+		store:   store,
+		handler: handler,
+		opts:    *opts,
+	}
+	if ix.opts.ChunkSize == 0 {
+		ix.opts.ChunkSize = DefaultOptions().ChunkSize
+	}
+	if ix.opts.Concurrency <= 0 {
+		ix.opts.Concurrency = 1
+	}
+	if ix.opts.PollInterval <= 0 {
+		ix.opts.PollInterval = DefaultOptions().PollInterval
+	}
+	ix.chunk.Store(ix.opts.ChunkSize)
+	return ix
+}
+
+// Next 返回下一个待处理的区块，首次调用时读取检查点
+func (ix *Indexer) Next(ctx context.Context) (uint64, error) {
+	if ix.loaded {
+		return ix.next, nil
+	}
+
+	cp, ok, err := ix.store.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ix.next = ix.opts.FromBlock
+	if ok {
+		ix.next = cp.Block + 1
+	}
+	ix.loaded = true
+	return ix.next, nil
+}
+
+// Checkpoint 返回最后处理完成的区块，ok 为 false 表示还没有处理过任何区块
+func (ix *Indexer) Checkpoint(ctx context.Context) (cp Checkpoint, ok bool, err error) {
+	return ix.store.Load(ctx)
+}
+
+// Sync 处理到 to 区块（含）为止后返回，已处理过的区块直接跳过
+func (ix *Indexer) Sync(ctx context.Context, to uint64) error {
+	next, err := ix.Next(ctx)
+	if err != nil {
+		return err
+	}
+
+	for next <= to {
+		ranges := ix.plan(next, to)
+		batches, fetchErr := ix.fetchAll(ctx, ranges)
+
+		// 失败区间之前已取回的区间照常提交，检查点尽量前进
+		for _, b := range batches {
+			if err := ix.handler(ctx, b); err != nil {
+				return &handlerError{fmt.Errorf("处理区块 %d-%d 日志失败: %w", b.From, b.To, err)}
+			}
+			if err := ix.store.Save(ctx, Checkpoint{Block: b.To, Hash: b.Hash}); err != nil {
+				return fmt.Errorf("保存检查点失败: %w", err)
+			}
+			ix.next = b.To + 1
+		}
+		if fetchErr != nil {
+			return fetchErr
+		}
+		next = ix.next
+
+		// 整轮没有缩小过区块段时逐步放大，回到配置的上限
+		if !ix.shrunk.Swap(false) {
+			if c := ix.chunk.Load(); c < ix.opts.ChunkSize {
+				ix.chunk.Store(min(c*2, ix.opts.ChunkSize))
+			}
+		}
+	}
+	return nil
+}
+
+// Run 回填到最新的已确认区块后持续跟踪新区块，直到 ctx 结束
+//
+// 回填和跟踪使用同一套分段逻辑：每轮都从检查点处理到当前已确认区块，落后很多时自动并发追赶。
+// 节点临时错误会在下一轮重试，Handler 和检查点的错误直接返回
+func (ix *Indexer) Run(ctx context.Context) error {
+	for {
+		err := ix.syncHead(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			var hErr *handlerError
+			if errors.As(err, &hErr) || !ethclient.IsTransient(err) && !ethclient.IsTooManyResults(err) {
+				return err
+			}
+			if ix.opts.OnError != nil {
+				ix.opts.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ix.opts.PollInterval):
+		}
+	}
+}
+
+// syncHead 处理到当前已确认的区块
+func (ix *Indexer) syncHead(ctx context.Context) error {
+	head, err := ix.backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("获取区块号失败: %w", err)
+	}
+	if head < ix.opts.Confirmations {
+		return nil
+	}
+	return ix.Sync(ctx, head-ix.opts.Confirmations)
+}
+
+// plan 按当前区块段大小切出最多 Concurrency 个区间
+func (ix *Indexer) plan(from, to uint64) [][2]uint64 {
+	chunk := ix.chunk.Load()
+	ranges := make([][2]uint64, 0, ix.opts.Concurrency)
+	for len(ranges) < ix.opts.Concurrency && from <= to {
+		end := to
+		if to-from >= chunk {
+			end = from + chunk - 1
+		}
+		ranges = append(ranges, [2]uint64{from, end})
+		from = end + 1
+	}
+	return ranges
+}
+
+// fetchAll 并发查询各区间，按区间顺序返回第一个失败区间之前的结果
+func (ix *Indexer) fetchAll(ctx context.Context, ranges [][2]uint64) ([]Batch, error) {
+	batches := make([]Batch, len(ranges))
+	errs := make([]error, len(ranges))
+
+	var wg sync.WaitGroup
+	for i, r := range ranges {
+		wg.Add(1)
+		go func(i int, from, to uint64) {
+			defer wg.Done()
+			// 先取区间末尾的区块哈希再查日志：期间发生重组时检查点记录的是旧区块，重启或跟踪时能发现
+			header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+			if err != nil {
+				errs[i] = fmt.Errorf("获取区块 %d 失败: %w", to, err)
+				return
+			}
+			logs, err := ix.fetch(ctx, from, to)
+			batches[i] = Batch{From: from, To: to, Hash: header.Hash(), Logs: logs}
+			errs[i] = err
+		}(i, r[0], r[1])
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return batches[:i], err
+		}
+	}
+	return batches, nil
+}
+
+// fetch 查询 [from, to] 的日志，节点提示结果过多时对半拆分递归查询
+func (ix *Indexer) fetch(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	q := ix.query
+	q.FromBlock = new(big.Int).SetUint64(from)
+	q.ToBlock = new(big.Int).SetUint64(to)
+
+	logs, err := ix.backend.FilterLogs(ctx, q)
+	if err == nil {
+		return logs, nil
+	}
+	if !ethclient.IsTooManyResults(err) || from == to {
+		return nil, fmt.Errorf("查询区块 %d-%d 日志失败: %w", from, to, err)
+	}
+
+	mid := from + (to-from)/2
+	ix.shrink(mid - from + 1)
+	left, err := ix.fetch(ctx, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ix.fetch(ctx, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// shrink 把后续区间的区块段缩小到 size
+func (ix *Indexer) shrink(size uint64) {
+	ix.shrunk.Store(true)
+	for {
+		cur := ix.chunk.Load()
+		if size >= cur || ix.chunk.CompareAndSwap(cur, size) {
+			return
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/indexer"
+)
+
+// tooManyResults 模拟服务商对大范围 eth_getLogs 返回的错误
+type tooManyResults struct{}
+
+func (tooManyResults) Error() string  { return "query returned more than 10000 results" }
+func (tooManyResults) ErrorCode() int { return -32005 }
+
+// limitedBackend 拒绝超过 maxRange 个区块的日志查询
+type limitedBackend struct {
+	ethclient.Backend
+
+	maxRange uint64
+	calls    atomic.Int32
+	rejected atomic.Int32
+}
+
+func (b *limitedBackend) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	b.calls.Add(1)
+	if q.ToBlock.Uint64()-q.FromBlock.Uint64()+1 > b.maxRange {
+		b.rejected.Add(1)
+		return nil, tooManyResults{}
+	}
+	return b.Backend.FilterLogs(ctx, q)
+}
+
+// collector 记录收到的区间和日志
+type collector struct {
+	mu   sync.Mutex
+	next uint64
+	logs []types.Log
+	err  error
+}
+
+func (c *collector) handle(_ context.Context, b indexer.Batch) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b.From != c.next {
+		c.err = errors.New("区间不连续")
+	}
+	c.next = b.To + 1
+	c.logs = append(c.logs, b.Logs...)
+	return nil
+}
+
+func (c *collector) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.logs)
+}
+
+// transfers 发送 n 笔代币转账，每笔单独出块
+func transfers(t *testing.T, chain *testchain.Chain, token *contract.ERC20Contract, n int) {
+	t.Helper()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	for i := 0; i < n; i++ {
+		tx, err := token.Transfer(context.Background(), alice.Signer, bob.Address, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("转账失败: %v", err)
+		}
+		chain.MustMine(tx)
+	}
+}
+
+func TestIndexer_BackfillAndResume(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+
+	token := chain.DeployMyToken(chain.Accounts[0], 1000)
+	transfers(t, chain, token, 12)
+	chain.Mine(5)
+
+	backend := &limitedBackend{Backend: chain.Eth(), maxRange: 3}
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{token.Address},
+		Topics:    [][]common.Hash{{token.ABI.Events["Transfer"].ID}},
+	}
+	store := indexer.NewFileCheckpoint(filepath.Join(t.TempDir(), "checkpoint.json"))
+	opts := indexer.DefaultOptions()
+	opts.ChunkSize = 8
+	opts.Concurrency = 3
+
+	head, err := chain.Eth().BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collector{}
+	if err := indexer.New(backend, query, store, c.handle, opts).Sync(ctx, head); err != nil {
+		t.Fatalf("回填失败: %v", err)
+	}
+	// 部署时铸币 1 条 + 转账 12 条
+	if c.err != nil || c.count() != 13 || c.next != head+1 {
+		t.Fatalf("收到 %d 条日志, next = %d, err = %v", c.count(), c.next, c.err)
+	}
+	for i := 1; i < len(c.logs); i++ {
+		if c.logs[i].BlockNumber < c.logs[i-1].BlockNumber {
+			t.Fatalf("日志顺序错误: %d 在 %d 之后", c.logs[i].BlockNumber, c.logs[i-1].BlockNumber)
+		}
+	}
+	if backend.rejected.Load() == 0 {
+		t.Error("应触发区块范围拆分")
+	}
+	headHeader, err := chain.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, ok, _ := store.Load(ctx); !ok || saved.Block != head || saved.Hash != headHeader.Hash() {
+		t.Errorf("检查点 = %+v, want %d %s", saved, head, headHeader.Hash().Hex())
+	}
+
+	// 重启后从检查点继续，只收到新日志
+	transfers(t, chain, token, 2)
+	head += 2
+
+	c = &collector{next: head - 1}
+	if err := indexer.New(backend, query, store, c.handle, opts).Sync(ctx, head); err != nil {
+		t.Fatalf("续传失败: %v", err)
+	}
+	if c.err != nil || c.count() != 2 {
+		t.Errorf("续传收到 %d 条日志, err = %v, want 2", c.count(), c.err)
+	}
+}
+
+func TestIndexer_RunTailsNewBlocks(t *testing.T) {
+	chain := testchain.New(t, nil)
+
+	token := chain.DeployMyToken(chain.Accounts[0], 1000)
+	transfers(t, chain, token, 3)
+
+	opts := indexer.DefaultOptions()
+	opts.PollInterval = 10 * time.Millisecond
+	query := ethereum.FilterQuery{Addresses: []common.Address{token.Address}}
+
+	c := &collector{}
+	ix := indexer.New(chain.Eth(), query, nil, c.handle, opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- ix.Run(ctx) }()
+
+	waitFor := func(n int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for c.count() < n {
+			if time.Now().After(deadline) {
+				t.Fatalf("收到 %d 条日志, want %d", c.count(), n)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// 回填完成后切换到跟踪，新区块的日志继续送达
+	waitFor(4)
+	transfers(t, chain, token, 2)
+	waitFor(6)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run 返回 %v, want context.Canceled", err)
+	}
+	if c.err != nil || c.count() != 6 {
+		t.Errorf("共收到 %d 条日志, err = %v", c.count(), c.err)
+	}
+}
@@ -0,0 +1,347 @@
+// Package reorg 提供按区块哈希跟踪主链的区块头跟踪器：记住最近交付的区块，
+// 发现父哈希不连续时回退到共同祖先，先通知被回滚的区块和日志，再交付新的主链区块
+package reorg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+)
+
+// ErrReorgTooDeep 重组越过了跟踪器记住的全部区块，无法找到共同祖先
+var ErrReorgTooDeep = errors.New("重组深度超过跟踪范围")
+
+// EventType 事件类型
+type EventType int
+
+const (
+	BlockAdded    EventType = iota // 区块进入主链
+	BlockReverted                  // 之前交付的区块被重组移出主链
+)
+
+// String 返回事件类型名称
+func (t EventType) String() string {
+	switch t {
+	case BlockAdded:
+		return "added"
+	case BlockReverted:
+		return "reverted"
+	default:
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+}
+
+// Event 一个区块的变化
+//
+// 发生重组时先按区块号从高到低交付 BlockReverted，再从共同祖先之后按区块号从低到高交付 BlockAdded
+type Event struct {
+	Type   EventType
+	Header *types.Header
+	Logs   []types.Log // 设置了 Options.Query 时为区块内匹配的日志，BlockReverted 中的日志 Removed 为 true
+}
+
+// Handler 处理一个区块事件；返回错误时跟踪停止，该事件在下次 Poll 时重新交付
+type Handler func(ctx context.Context, ev Event) error
+
+// Options 跟踪参数
+type Options struct {
+	FromBlock     uint64                // 第一个交付的区块，0 表示从启动时已确认的最新区块之后开始
+	FromHash      common.Hash           // FromBlock-1 的区块哈希（可选，通常来自检查点），不在主链上时先回滚到共同祖先，见 Resume
+	Confirmations uint64                // 区块有 n 个确认后才交付，浅于 n 的重组不会产生回滚
+	Depth         int                   // 记住的最近区块数，重组超过该深度时返回 ErrReorgTooDeep
+	PollInterval  time.Duration         // 轮询间隔
+	Query         *ethereum.FilterQuery // 不为 nil 时按区块哈希查询匹配的日志，FromBlock / ToBlock / BlockHash 由跟踪器填写
+	OnError       func(error)           // Run 遇到临时错误重试前的回调（可选），用于记录日志
+}
+
+// DefaultOptions 默认参数：记住最近 128 个区块、12 秒轮询
+func DefaultOptions() *Options {
+	return &Options{
+		Depth:        128,
+		PollInterval: 12 * time.Second,
+	}
+}
+
+// trackedBlock 跟踪器记住的区块
+type trackedBlock struct {
+	header    *types.Header
+	logs      []types.Log
+	delivered bool // 起点之前的锚定区块只用于校验父哈希，不交付也不回滚
+}
+
+// Tracker 区块头跟踪器
+//
+// Poll 和 Run 不能并发调用
+type Tracker struct {
+	backend ethclient.Backend
+	handler Handler
+	opts    Options
+
+	started bool
+	blocks  []*trackedBlock // 最近的主链区块，按区块号递增，第一个可能是未交付的锚定区块
+}
+
+// handlerError Handler 返回的错误，Run 不重试
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+func (e *handlerError) Unwrap() error { return e.err }
+
+// NewTracker 创建区块头跟踪器
+func NewTracker(backend ethclient.Backend, handler Handler, opts *Options) *Tracker {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	t := &Tracker{
+		backend: backend,
+		handler: handler,
+		opts:    *opts,
+	}
+	if t.opts.Depth <= 0 {
+		t.opts.Depth = DefaultOptions().Depth
+	}
+	if t.opts.PollInterval <= 0 {
+		t.opts.PollInterval = DefaultOptions().PollInterval
+	}
+	return t
+}
+
+// Head 返回最后交付的区块，尚未交付时返回 nil
+func (t *Tracker) Head() *types.Header {
+	if tip := t.tip(); tip != nil && tip.delivered {
+		return tip.header
+	}
+	return nil
+}
+
+// Poll 检查一次链头：处理自上次以来的重组，并交付到当前已确认区块为止的新区块
+func (t *Tracker) Poll(ctx context.Context) error {
+	head, err := t.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("获取最新区块失败: %w", err)
+	}
+	if head.Number.Uint64() < t.opts.Confirmations {
+		return nil
+	}
+	target := head.Number.Uint64() - t.opts.Confirmations
+
+	if !t.started {
+		if ok, err := t.start(ctx, target); !ok || err != nil {
+			return err
+		}
+	}
+
+	// 先确认记住的最新区块仍在主链上
+	if err := t.rewind(ctx); err != nil {
+		return err
+	}
+
+	for n := t.nextBlock(); n <= target; n = t.nextBlock() {
+		header, err := t.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if errors.Is(err, ethereum.NotFound) {
+			// 链头在查询期间被更短的分叉替换
+			return t.rewind(ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("获取区块 %d 失败: %w", n, err)
+		}
+
+		if header.ParentHash != t.tip().header.Hash() {
+			// 查询期间发生重组，回退后从共同祖先继续
+			if err := t.rewind(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		logs, err := t.logs(ctx, header)
+		if err != nil {
+			return err
+		}
+		if err := t.handler(ctx, Event{Type: BlockAdded, Header: header, Logs: logs}); err != nil {
+			return &handlerError{fmt.Errorf("处理区块 %d 失败: %w", n, err)}
+		}
+		t.push(&trackedBlock{header: header, logs: logs, delivered: true})
+	}
+	return nil
+}
+
+// Run 持续轮询链头直到 ctx 结束
+//
+// 节点临时错误会在下一轮重试，Handler 的错误和 ErrReorgTooDeep 直接返回
+func (t *Tracker) Run(ctx context.Context) error {
+	for {
+		err := t.Poll(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			var hErr *handlerError
+			if errors.As(err, &hErr) || !ethclient.IsTransient(err) {
+				return err
+			}
+			if t.opts.OnError != nil {
+				t.opts.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.opts.PollInterval):
+		}
+	}
+}
+
+// start 确定起点并记住起点的父区块作为锚定，起点尚未确认时返回 false
+func (t *Tracker) start(ctx context.Context, target uint64) (bool, error) {
+	from := t.opts.FromBlock
+	if from == 0 {
+		from = target + 1
+	}
+	if from > target+1 {
+		return false, nil
+	}
+
+	var anchor *types.Header
+	var err error
+	if t.opts.FromHash != (common.Hash{}) {
+		// 上次处理到的区块可能已被重组替换，回滚后从共同祖先之后继续
+		anchor, err = t.resume(ctx, t.opts.FromHash)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		anchor, err = t.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(from-1))
+		if err != nil {
+			return false, fmt.Errorf("获取区块 %d 失败: %w", from-1, err)
+		}
+	}
+	t.push(&trackedBlock{header: anchor})
+	t.started = true
+	return true, nil
+}
+
+// Resume 从检查点恢复时确认上次处理到的区块 hash 仍在主链上
+//
+// 停机期间发生重组时，沿父哈希对 hash 及其不在主链上的祖先依次交付 BlockReverted
+// （设置了 opts.Query 时附带 Removed 为 true 的日志），返回仍在主链上的共同祖先。
+// 节点查不到被替换的区块或重组超过 opts.Depth 时返回错误，此时无法确定需要回滚哪些事件。
+// 与 Handler 一样，交付中途失败时重新调用会再次交付已回滚的区块
+func Resume(ctx context.Context, backend ethclient.Backend, hash common.Hash, handler Handler, opts *Options) (*types.Header, error) {
+	return NewTracker(backend, handler, opts).resume(ctx, hash)
+}
+
+// resume 见 Resume
+func (t *Tracker) resume(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := t.backend.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("获取检查点区块 %s 失败，无法确认是否发生过重组: %w", hash.Hex(), err)
+	}
+
+	for depth := 0; ; depth++ {
+		canonical, err := t.backend.HeaderByNumber(ctx, header.Number)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("获取区块 %d 失败: %w", header.Number, err)
+		}
+		if err == nil && canonical.Hash() == header.Hash() {
+			return header, nil
+		}
+		if depth >= t.opts.Depth {
+			return nil, fmt.Errorf("%w（最多 %d 个区块）", ErrReorgTooDeep, t.opts.Depth)
+		}
+
+		logs, err := t.logs(ctx, header)
+		if err != nil {
+			return nil, err
+		}
+		for i := range logs {
+			logs[i].Removed = true
+		}
+		if err := t.handler(ctx, Event{Type: BlockReverted, Header: header, Logs: logs}); err != nil {
+			return nil, &handlerError{fmt.Errorf("回滚区块 %d 失败: %w", header.Number, err)}
+		}
+
+		parent, err := t.backend.HeaderByHash(ctx, header.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("获取区块 %d 的父区块失败: %w", header.Number, err)
+		}
+		header = parent
+	}
+}
+
+// rewind 从最新区块往回对比主链哈希，逐个回滚不在主链上的区块，直到共同祖先
+func (t *Tracker) rewind(ctx context.Context) error {
+	for len(t.blocks) > 0 {
+		tip := t.tip()
+		number := tip.header.Number
+		canonical, err := t.backend.HeaderByNumber(ctx, number)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("获取区块 %d 失败: %w", number, err)
+		}
+		if err == nil && canonical.Hash() == tip.header.Hash() {
+			return nil
+		}
+
+		if tip.delivered {
+			removed := make([]types.Log, len(tip.logs))
+			for i, l := range tip.logs {
+				l.Removed = true
+				removed[i] = l
+			}
+			if err := t.handler(ctx, Event{Type: BlockReverted, Header: tip.header, Logs: removed}); err != nil {
+				return &handlerError{fmt.Errorf("回滚区块 %d 失败: %w", number, err)}
+			}
+		}
+		t.blocks = t.blocks[:len(t.blocks)-1]
+	}
+	return fmt.Errorf("%w（最多 %d 个区块）", ErrReorgTooDeep, t.opts.Depth)
+}
+
+// logs 按区块哈希查询匹配的日志，避免查到同高度分叉区块的日志
+func (t *Tracker) logs(ctx context.Context, header *types.Header) ([]types.Log, error) {
+	if t.opts.Query == nil {
+		return nil, nil
+	}
+
+	hash := header.Hash()
+	q := *t.opts.Query
+	q.FromBlock, q.ToBlock, q.BlockHash = nil, nil, &hash
+	logs, err := t.backend.FilterLogs(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("查询区块 %d 日志失败: %w", header.Number, err)
+	}
+	return logs, nil
+}
+
+// push 记住一个区块，超过 Depth 时丢弃最早的
+func (t *Tracker) push(b *trackedBlock) {
+	t.blocks = append(t.blocks, b)
+	if len(t.blocks) > t.opts.Depth {
+		t.blocks = t.blocks[len(t.blocks)-t.opts.Depth:]
+	}
+}
+
+// tip 返回记住的最新区块
+func (t *Tracker) tip() *trackedBlock {
+	if len(t.blocks) == 0 {
+		return nil
+	}
+	return t.blocks[len(t.blocks)-1]
+}
+
+// nextBlock 返回下一个待交付的区块号
+func (t *Tracker) nextBlock() uint64 {
+	return t.tip().header.Number.Uint64() + 1
+}
@@ -0,0 +1,202 @@
+package reorg_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/reorg"
+)
+
+// recorder 按顺序记录事件，格式为 "added 3 logs=1"
+type recorder struct {
+	events []string
+	logs   map[common.Hash]bool // 当前有效的日志所在交易
+}
+
+func (r *recorder) handle(_ context.Context, ev reorg.Event) error {
+	r.events = append(r.events, fmt.Sprintf("%s %d logs=%d", ev.Type, ev.Header.Number, len(ev.Logs)))
+	for _, l := range ev.Logs {
+		if l.Removed != (ev.Type == reorg.BlockReverted) {
+			return fmt.Errorf("区块 %d 日志 Removed = %v", l.BlockNumber, l.Removed)
+		}
+		r.logs[l.TxHash] = !l.Removed
+	}
+	return nil
+}
+
+func (r *recorder) take() []string {
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// transfer 发送一笔代币转账并单独出块
+func transfer(t *testing.T, chain *testchain.Chain, token *contract.ERC20Contract, amount int64) {
+	t.Helper()
+	tx, err := token.Transfer(context.Background(), chain.Accounts[0].Signer, chain.Accounts[1].Address, big.NewInt(amount))
+	if err != nil {
+		t.Fatalf("转账失败: %v", err)
+	}
+	chain.MustMine(tx)
+}
+
+func assertEvents(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("事件 = %q, want %q", got, want)
+	}
+}
+
+func TestTracker_Reorg(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+
+	token := chain.DeployMyToken(chain.Accounts[0], 1000) // 区块 1
+	for i := 0; i < 3; i++ {
+		transfer(t, chain, token, 1) // 区块 2-4
+	}
+
+	opts := reorg.DefaultOptions()
+	opts.FromBlock = 2
+	opts.Query = &ethereum.FilterQuery{Addresses: []common.Address{token.Address}}
+	r := &recorder{logs: make(map[common.Hash]bool)}
+	tracker := reorg.NewTracker(chain.Eth(), r.handle, opts)
+
+	if err := tracker.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, r.take(), "added 2 logs=1", "added 3 logs=1", "added 4 logs=1")
+
+	// 从区块 2 分叉出更长的链，区块 3、4 被替换
+	fork, err := chain.Eth().HeaderByNumber(ctx, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Fork(fork.Hash())
+	if err := chain.TxManager().Nonces().Resync(ctx, chain.Accounts[0].Address); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		// 分叉链长于主链前交易不在主链上，不等待收据
+		if _, err := token.Transfer(ctx, chain.Accounts[0].Signer, chain.Accounts[1].Address, big.NewInt(2)); err != nil {
+			t.Fatalf("分叉链转账失败: %v", err)
+		}
+	}
+
+	if err := tracker.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, r.take(),
+		"reverted 4 logs=1", "reverted 3 logs=1",
+		"added 3 logs=1", "added 4 logs=1", "added 5 logs=1",
+	)
+
+	valid := 0
+	for _, ok := range r.logs {
+		if ok {
+			valid++
+		}
+	}
+	if valid != 4 || len(r.logs) != 6 {
+		t.Errorf("有效日志 %d 条、共 %d 条, want 4 / 6", valid, len(r.logs))
+	}
+	head, _ := chain.Eth().HeaderByNumber(ctx, nil)
+	if got := tracker.Head(); got == nil || got.Hash() != head.Hash() {
+		t.Errorf("Head = %v, want %s", got, head.Hash())
+	}
+}
+
+func TestTracker_Confirmations(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	chain.Mine(5)
+
+	opts := reorg.DefaultOptions()
+	opts.FromBlock = 1
+	opts.Confirmations = 2
+	r := &recorder{logs: make(map[common.Hash]bool)}
+	tracker := reorg.NewTracker(chain.Eth(), r.handle, opts)
+
+	if err := tracker.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, r.take(), "added 1 logs=0", "added 2 logs=0", "added 3 logs=0")
+
+	// 重组只替换未确认的区块 5，不会产生回滚
+	parent, err := chain.Eth().HeaderByNumber(ctx, big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := chain.Eth().HeaderByNumber(ctx, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Fork(parent.Hash())
+	chain.AdjustTime(time.Second)
+	chain.Mine(2)
+	if h, _ := chain.Eth().HeaderByNumber(ctx, big.NewInt(5)); h.Hash() == replaced.Hash() {
+		t.Fatal("区块 5 未被替换")
+	}
+
+	if err := tracker.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, r.take(), "added 4 logs=0")
+}
+
+func TestTracker_ResumeAfterDowntimeReorg(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+
+	token := chain.DeployMyToken(chain.Accounts[0], 1000) // 区块 1
+	for i := 0; i < 3; i++ {
+		transfer(t, chain, token, 1) // 区块 2-4
+	}
+	// 检查点记录了区块 4，之后进程退出
+	checkpoint, err := chain.Eth().HeaderByNumber(ctx, big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 停机期间从区块 2 分叉，区块 3、4 被替换
+	fork, err := chain.Eth().HeaderByNumber(ctx, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Fork(fork.Hash())
+	chain.AdjustTime(time.Second)
+	chain.Mine(3)
+
+	opts := reorg.DefaultOptions()
+	opts.FromBlock = 5
+	opts.FromHash = checkpoint.Hash()
+	opts.Query = &ethereum.FilterQuery{Addresses: []common.Address{token.Address}}
+	r := &recorder{logs: make(map[common.Hash]bool)}
+	tracker := reorg.NewTracker(chain.Eth(), r.handle, opts)
+
+	if err := tracker.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	assertEvents(t, r.take(),
+		"reverted 4 logs=1", "reverted 3 logs=1",
+		"added 3 logs=0", "added 4 logs=0", "added 5 logs=0",
+	)
+
+	// 检查点仍在主链上时不产生回滚
+	head, err := chain.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ancestor, err := reorg.Resume(ctx, chain.Eth(), head.Hash(), r.handle, opts)
+	if err != nil || ancestor.Hash() != head.Hash() || len(r.take()) != 0 {
+		t.Errorf("Resume = %v, %v", ancestor, err)
+	}
+}
@@ -0,0 +1,66 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"go-eth-learning/pkg/wallet"
+)
+
+// KeystoreSigner 使用 keystore 目录中的账户签名，每次签名时解密
+type KeystoreSigner struct {
+	ks       *wallet.KeyStore
+	address  common.Address
+	password string
+}
+
+// NewKeystoreSigner 创建 keystore 签名器
+func NewKeystoreSigner(ks *wallet.KeyStore, address common.Address, password string) (*KeystoreSigner, error) {
+	// 先解密一次，尽早发现地址或密码错误
+	if _, err := ks.Wallet(address, password); err != nil {
+		return nil, err
+	}
+
+	return &KeystoreSigner{
+		ks:       ks,
+		address:  address,
+		password: password,
+	}, nil
+}
+
+// Address 返回签名账户地址
+func (s *KeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 签名交易
+func (s *KeystoreSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTx(s.address, s.password, tx, chainID)
+}
+
+// SignMessage 按 EIP-191 签名消息
+func (s *KeystoreSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return s.signHash(accounts.TextHash(message))
+}
+
+// SignTypedData 按 EIP-712 签名结构化数据
+func (s *KeystoreSigner) SignTypedData(_ context.Context, data apitypes.TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.signHash(hash)
+}
+
+func (s *KeystoreSigner) signHash(hash []byte) ([]byte, error) {
+	signature, err := s.ks.SignHash(s.address, s.password, hash)
+	if err != nil {
+		return nil, err
+	}
+	return toEthereumV(signature), nil
+}
@@ -0,0 +1,81 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"go-eth-learning/pkg/wallet"
+)
+
+// PrivateKeySigner 使用内存私钥签名
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner 从私钥创建签名器
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// FromHex 从十六进制私钥（0x 可选）创建签名器
+func FromHex(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+// FromWallet 从钱包创建签名器
+func FromWallet(w *wallet.Wallet) *PrivateKeySigner {
+	return NewPrivateKeySigner(w.PrivateKey)
+}
+
+// Address 返回签名账户地址
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 签名交易
+func (s *PrivateKeySigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), s.key)
+	if err != nil {
+		return nil, fmt.Errorf("签名交易失败: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignMessage 按 EIP-191 签名消息
+func (s *PrivateKeySigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return s.signHash(accounts.TextHash(message))
+}
+
+// SignTypedData 按 EIP-712 签名结构化数据
+func (s *PrivateKeySigner) SignTypedData(_ context.Context, data apitypes.TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.signHash(hash)
+}
+
+func (s *PrivateKeySigner) signHash(hash []byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("签名失败: %w", err)
+	}
+	return toEthereumV(signature), nil
+}
@@ -0,0 +1,214 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// RemoteSigner 通过 HTTP JSON-RPC 调用外部签名服务（兼容 Clef 的 account_* 接口）
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTransactionResult account_signTransaction 返回结构
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewRemoteSigner 连接外部签名服务，并确认其管理了指定账户
+func NewRemoteSigner(ctx context.Context, endpoint string, address common.Address) (*RemoteSigner, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("连接外部签名服务失败: %w", err)
+	}
+
+	var addrs []common.Address
+	if err := client.CallContext(ctx, &addrs, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("获取外部签名账户失败: %w", err)
+	}
+	for _, addr := range addrs {
+		if addr == address {
+			return &RemoteSigner{client: client, address: address}, nil
+		}
+	}
+
+	client.Close()
+	return nil, fmt.Errorf("外部签名服务未管理账户: %s", address.Hex())
+}
+
+// Close 关闭连接
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// Address 返回签名账户地址
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 调用 account_signTransaction 签名交易
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:  common.NewMixedcaseAddress(s.address),
+		Gas:   hexutil.Uint64(tx.Gas()),
+		Value: hexutil.Big(*tx.Value()),
+		Nonce: hexutil.Uint64(tx.Nonce()),
+		Data:  &data,
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+
+	switch tx.Type() {
+	case types.LegacyTxType, types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("不支持的交易类型: %d", tx.Type())
+	}
+	if chainID != nil && chainID.Sign() != 0 {
+		args.ChainID = (*hexutil.Big)(chainID)
+	}
+	if tx.Type() != types.LegacyTxType {
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	}
+
+	var res signTransactionResult
+	if err := s.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("外部签名交易失败: %w", err)
+	}
+	if res.Tx == nil {
+		return nil, fmt.Errorf("外部签名服务未返回交易")
+	}
+	if err := s.checkSignedTx(tx, res.Tx, chainID); err != nil {
+		return nil, err
+	}
+	return res.Tx, nil
+}
+
+// checkSignedTx 确认外部签名服务返回的交易就是请求签名的交易，且由本账户签名
+//
+// 签名服务（或中间人）可能返回改动过的交易，直接广播会把资金转到别处
+func (s *RemoteSigner) checkSignedTx(req, signed *types.Transaction, chainID *big.Int) error {
+	switch {
+	case signed.Type() != req.Type():
+		return fmt.Errorf("外部签名服务返回的交易类型不一致: %d，请求为 %d", signed.Type(), req.Type())
+	case signed.Nonce() != req.Nonce():
+		return fmt.Errorf("外部签名服务返回的交易 nonce 不一致: %d，请求为 %d", signed.Nonce(), req.Nonce())
+	case !sameAddress(signed.To(), req.To()):
+		return fmt.Errorf("外部签名服务返回的交易接收地址不一致: %s，请求为 %s", addressString(signed.To()), addressString(req.To()))
+	case signed.Value().Cmp(req.Value()) != 0:
+		return fmt.Errorf("外部签名服务返回的交易金额不一致: %s，请求为 %s", signed.Value(), req.Value())
+	case !bytes.Equal(signed.Data(), req.Data()):
+		return fmt.Errorf("外部签名服务返回的交易数据不一致")
+	case signed.Gas() != req.Gas():
+		return fmt.Errorf("外部签名服务返回的交易 gas 上限不一致: %d，请求为 %d", signed.Gas(), req.Gas())
+	case !sameAccessList(signed.AccessList(), req.AccessList()):
+		return fmt.Errorf("外部签名服务返回的交易访问列表不一致")
+	}
+
+	// 类型已经一致，按类型比较实际生效的费用字段
+	if req.Type() == types.DynamicFeeTxType {
+		if signed.GasFeeCap().Cmp(req.GasFeeCap()) != 0 {
+			return fmt.Errorf("外部签名服务返回的交易 maxFeePerGas 不一致: %s，请求为 %s", signed.GasFeeCap(), req.GasFeeCap())
+		}
+		if signed.GasTipCap().Cmp(req.GasTipCap()) != 0 {
+			return fmt.Errorf("外部签名服务返回的交易 maxPriorityFeePerGas 不一致: %s，请求为 %s", signed.GasTipCap(), req.GasTipCap())
+		}
+	} else if signed.GasPrice().Cmp(req.GasPrice()) != 0 {
+		return fmt.Errorf("外部签名服务返回的交易 gasPrice 不一致: %s，请求为 %s", signed.GasPrice(), req.GasPrice())
+	}
+
+	if chainID == nil || chainID.Sign() == 0 {
+		chainID = signed.ChainId()
+	} else if signed.ChainId().Cmp(chainID) != 0 {
+		return fmt.Errorf("外部签名服务返回的交易链 ID 不一致: %s，请求为 %s", signed.ChainId(), chainID)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("解析外部签名交易的发送者失败: %w", err)
+	}
+	if sender != s.address {
+		return fmt.Errorf("外部签名服务返回的交易发送者为 %s，应为 %s", sender.Hex(), s.address.Hex())
+	}
+	return nil
+}
+
+func sameAddress(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameAccessList 比较访问列表，nil 与空列表视为相同
+func sameAccessList(a, b types.AccessList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address || len(a[i].StorageKeys) != len(b[i].StorageKeys) {
+			return false
+		}
+		for j := range a[i].StorageKeys {
+			if a[i].StorageKeys[j] != b[i].StorageKeys[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addressString 格式化交易接收地址，合约创建交易没有接收地址
+func addressString(addr *common.Address) string {
+	if addr == nil {
+		return "（合约创建）"
+	}
+	return addr.Hex()
+}
+
+// SignMessage 调用 account_signData (text/plain) 签名消息
+func (s *RemoteSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	addr := common.NewMixedcaseAddress(s.address)
+	if err := s.client.CallContext(ctx, &signature, "account_signData",
+		accounts.MimetypeTextPlain, &addr, hexutil.Encode(message)); err != nil {
+		return nil, fmt.Errorf("外部签名消息失败: %w", err)
+	}
+	return normalizeSignature(signature)
+}
+
+// SignTypedData 调用 account_signTypedData 签名结构化数据
+func (s *RemoteSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	addr := common.NewMixedcaseAddress(s.address)
+	if err := s.client.CallContext(ctx, &signature, "account_signTypedData", &addr, data); err != nil {
+		return nil, fmt.Errorf("外部签名结构化数据失败: %w", err)
+	}
+	return normalizeSignature(signature)
+}
+
+// normalizeSignature 校验签名长度并统一 V 为 27/28
+func normalizeSignature(signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("外部签名服务返回的签名长度错误: %d", len(signature))
+	}
+	return toEthereumV(signature), nil
+}
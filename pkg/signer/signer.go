@@ -0,0 +1,80 @@
+// Package signer 提供交易和消息签名抽象
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer 签名器接口
+//
+// 消息和 EIP-712 签名返回 65 字节 [R || S || V]，V 为 27/28（与 personal_sign 一致）
+type Signer interface {
+	// Address 返回签名账户地址
+	Address() common.Address
+
+	// SignTx 签名交易
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+	// SignMessage 按 EIP-191 (personal_sign) 签名消息
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+
+	// SignTypedData 按 EIP-712 签名结构化数据
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+}
+
+// TypedDataHash 计算 EIP-712 结构化数据的签名哈希
+func TypedDataHash(data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("计算 EIP-712 哈希失败: %w", err)
+	}
+	return hash, nil
+}
+
+// RecoverMessage 从 EIP-191 消息签名恢复签名者地址
+func RecoverMessage(message, signature []byte) (common.Address, error) {
+	return recoverHash(accounts.TextHash(message), signature)
+}
+
+// RecoverTypedData 从 EIP-712 签名恢复签名者地址
+func RecoverTypedData(data apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return recoverHash(hash, signature)
+}
+
+// recoverHash 从哈希签名恢复地址，兼容 V 为 0/1 或 27/28
+func recoverHash(hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("签名长度错误: %d", len(signature))
+	}
+
+	sig := common.CopyBytes(signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("恢复公钥失败: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// toEthereumV 将 V 从 0/1 转为 27/28
+func toEthereumV(signature []byte) []byte {
+	if signature[crypto.RecoveryIDOffset] < 27 {
+		signature[crypto.RecoveryIDOffset] += 27
+	}
+	return signature
+}
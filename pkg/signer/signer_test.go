@@ -0,0 +1,260 @@
+package signer_test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/wallet"
+)
+
+var chainID = big.NewInt(11155111)
+
+func testTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Mail": {
+				{Name: "to", Type: "address"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "test",
+			ChainId: (*math.HexOrDecimal256)(chainID),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":       "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			"contents": "hello",
+		},
+	}
+}
+
+// checkSigner 验证签名器的交易、消息和 EIP-712 签名都能恢复出正确地址
+func checkSigner(t *testing.T, s signer.Signer) {
+	t.Helper()
+	ctx := context.Background()
+
+	to := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	txs := []*types.Transaction{
+		types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1e9), nil),
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     1,
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(2e9),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(1),
+		}),
+	}
+	for _, tx := range txs {
+		signedTx, err := s.SignTx(ctx, tx, chainID)
+		if err != nil {
+			t.Fatalf("签名交易失败: %v", err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+		if err != nil {
+			t.Fatalf("恢复发送者失败: %v", err)
+		}
+		if sender != s.Address() {
+			t.Errorf("交易类型 %d 发送者 = %s, want %s", tx.Type(), sender.Hex(), s.Address().Hex())
+		}
+	}
+
+	message := []byte("hello ethereum")
+	sig, err := s.SignMessage(ctx, message)
+	if err != nil {
+		t.Fatalf("签名消息失败: %v", err)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Errorf("V = %d, want 27/28", sig[64])
+	}
+	if addr, err := signer.RecoverMessage(message, sig); err != nil || addr != s.Address() {
+		t.Errorf("消息签名恢复地址 = %s, err = %v", addr.Hex(), err)
+	}
+
+	typedSig, err := s.SignTypedData(ctx, testTypedData())
+	if err != nil {
+		t.Fatalf("签名结构化数据失败: %v", err)
+	}
+	if addr, err := signer.RecoverTypedData(testTypedData(), typedSig); err != nil || addr != s.Address() {
+		t.Errorf("EIP-712 签名恢复地址 = %s, err = %v", addr.Hex(), err)
+	}
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	s, err := signer.FromHex("0x" + w.GetPrivateKeyHex())
+	if err != nil {
+		t.Fatalf("创建签名器失败: %v", err)
+	}
+	if s.Address() != w.Address {
+		t.Fatal("签名器地址不匹配")
+	}
+	checkSigner(t, s)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	ks, _ := wallet.NewKeyStore(t.TempDir(), wallet.LightKeystoreOptions())
+	addr, _ := ks.NewAccount("secret")
+
+	if _, err := signer.NewKeystoreSigner(ks, addr, "wrong"); err == nil {
+		t.Error("错误密码应该返回错误")
+	}
+
+	s, err := signer.NewKeystoreSigner(ks, addr, "secret")
+	if err != nil {
+		t.Fatalf("创建签名器失败: %v", err)
+	}
+	checkSigner(t, s)
+}
+
+// fakeClef 模拟 Clef 的 account_* 接口，tamper 不为 nil 时在签名前改动交易参数
+type fakeClef struct {
+	key    *signer.PrivateKeySigner
+	tamper func(args *apitypes.SendTxArgs)
+	signAs *signer.PrivateKeySigner // 不为 nil 时用其他私钥签名
+}
+
+func (c *fakeClef) List() []common.Address {
+	return []common.Address{c.key.Address()}
+}
+
+func (c *fakeClef) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	if c.tamper != nil {
+		c.tamper(&args)
+	}
+	key := c.key
+	if c.signAs != nil {
+		key = c.signAs
+	}
+	tx := args.ToTransaction()
+	signedTx, err := key.SignTx(context.Background(), tx, (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := signedTx.MarshalBinary()
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signedTx}, nil
+}
+
+func (c *fakeClef) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	return c.key.SignMessage(context.Background(), data)
+}
+
+func (c *fakeClef) SignTypedData(addr common.MixedcaseAddress, data apitypes.TypedData) (hexutil.Bytes, error) {
+	return c.key.SignTypedData(context.Background(), data)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	clef := &fakeClef{key: signer.NewPrivateKeySigner(key)}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatalf("注册服务失败: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	if _, err := signer.NewRemoteSigner(ctx, httpServer.URL, common.Address{1}); err == nil {
+		t.Error("未管理的账户应该返回错误")
+	}
+
+	s, err := signer.NewRemoteSigner(ctx, httpServer.URL, clef.key.Address())
+	if err != nil {
+		t.Fatalf("连接外部签名服务失败: %v", err)
+	}
+	defer s.Close()
+
+	checkSigner(t, s)
+}
+
+func TestRemoteSigner_RejectsMismatchedTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	clef := &fakeClef{key: signer.NewPrivateKeySigner(key)}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatalf("注册服务失败: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	s, err := signer.NewRemoteSigner(ctx, httpServer.URL, clef.key.Address())
+	if err != nil {
+		t.Fatalf("连接外部签名服务失败: %v", err)
+	}
+	defer s.Close()
+
+	to := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(2e9),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{1, 2, 3},
+	})
+
+	tests := []struct {
+		name   string
+		tamper func(args *apitypes.SendTxArgs)
+		signAs *signer.PrivateKeySigner
+	}{
+		{name: "nonce", tamper: func(args *apitypes.SendTxArgs) { args.Nonce++ }},
+		{name: "to", tamper: func(args *apitypes.SendTxArgs) {
+			attacker := common.NewMixedcaseAddress(common.HexToAddress("0xbad"))
+			args.To = &attacker
+		}},
+		{name: "value", tamper: func(args *apitypes.SendTxArgs) { args.Value = hexutil.Big(*big.NewInt(1e18)) }},
+		{name: "data", tamper: func(args *apitypes.SendTxArgs) {
+			data := hexutil.Bytes{4, 5, 6}
+			args.Data, args.Input = &data, nil
+		}},
+		{name: "chainID", tamper: func(args *apitypes.SendTxArgs) { args.ChainID = (*hexutil.Big)(big.NewInt(1)) }},
+		{name: "gas", tamper: func(args *apitypes.SendTxArgs) { args.Gas = 1_000_000 }},
+		{name: "type", tamper: func(args *apitypes.SendTxArgs) {
+			args.GasPrice, args.MaxFeePerGas, args.MaxPriorityFeePerGas = args.MaxFeePerGas, nil, nil
+			args.AccessList = nil
+		}},
+		{name: "maxFeePerGas", tamper: func(args *apitypes.SendTxArgs) { args.MaxFeePerGas = (*hexutil.Big)(big.NewInt(1e12)) }},
+		{name: "maxPriorityFeePerGas", tamper: func(args *apitypes.SendTxArgs) {
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(big.NewInt(2e9))
+		}},
+		{name: "accessList", tamper: func(args *apitypes.SendTxArgs) {
+			args.AccessList = &types.AccessList{{Address: to, StorageKeys: []common.Hash{{}}}}
+		}},
+		{name: "sender", signAs: signer.NewPrivateKeySigner(other)},
+	}
+	for _, tt := range tests {
+		clef.tamper, clef.signAs = tt.tamper, tt.signAs
+		if _, err := s.SignTx(ctx, tx, chainID); err == nil {
+			t.Errorf("%s: 返回的交易被改动时应报错", tt.name)
+		}
+	}
+
+	clef.tamper, clef.signAs = nil, nil
+	if _, err := s.SignTx(ctx, tx, chainID); err != nil {
+		t.Errorf("未改动的交易签名失败: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JSONLOptions JSONL 文件参数
+type JSONLOptions struct {
+	MaxBytes int64 // 文件超过该大小后轮转，0 表示不轮转
+	MaxFiles int   // 保留的历史文件数（path.1 … path.N），0 表示全部保留
+}
+
+// DefaultJSONLOptions 默认参数：单个文件 100MB，保留 10 个历史文件
+func DefaultJSONLOptions() *JSONLOptions {
+	return &JSONLOptions{
+		MaxBytes: 100 << 20,
+		MaxFiles: 10,
+	}
+}
+
+// JSONL 追加写入 JSONL 文件，每行一个区块或事件
+//
+// 每个批次写完后 fsync，文件按大小轮转：path 重命名为 path.1，原有的 path.1 变为 path.2，依次类推；
+// 一个批次总是写在同一个文件中
+type JSONL struct {
+	path string
+	opts JSONLOptions
+	f    *os.File
+	size int64
+}
+
+// OpenJSONL 打开或创建 JSONL 文件，opts 为 nil 时使用默认参数
+func OpenJSONL(path string, opts *JSONLOptions) (*JSONL, error) {
+	if opts == nil {
+		opts = DefaultJSONLOptions()
+	}
+	j := &JSONL{path: path, opts: *opts}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *JSONL) open() error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("打开 JSONL 文件失败: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("打开 JSONL 文件失败: %w", err)
+	}
+	j.f, j.size = f, info.Size()
+	return nil
+}
+
+// Write 追加批次并同步到磁盘
+func (j *JSONL) Write(_ context.Context, batch Batch) error {
+	if batch.Empty() {
+		return nil
+	}
+	if j.opts.MaxBytes > 0 && j.size >= j.opts.MaxBytes {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+
+	// 写入失败时截断到批次开始的位置，避免重新投递时留下半行
+	n, err := j.write(batch)
+	if err != nil {
+		if truncErr := j.f.Truncate(j.size); truncErr != nil {
+			return errors.Join(err, fmt.Errorf("截断 JSONL 文件失败，文件末尾可能有不完整的行: %w", truncErr))
+		}
+		return err
+	}
+	if err := j.f.Sync(); err != nil {
+		return fmt.Errorf("同步 JSONL 文件失败: %w", err)
+	}
+	j.size += n
+	return nil
+}
+
+func (j *JSONL) write(batch Batch) (int64, error) {
+	cw := &countingWriter{w: j.f}
+	w := bufio.NewWriter(cw)
+	for _, b := range batch.Blocks {
+		if err := writeRecord(w, "block", b); err != nil {
+			return 0, err
+		}
+	}
+	for _, ev := range batch.Events {
+		if err := writeRecord(w, "event", ev); err != nil {
+			return 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, fmt.Errorf("写入 JSONL 失败: %w", err)
+	}
+	return cw.n, nil
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w *os.File
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// writeRecord 写入一行 {"type": kind, ...v 的字段}
+func writeRecord(w *bufio.Writer, kind string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("编码 %s 失败: %w", kind, err)
+	}
+	fmt.Fprintf(w, `{"type":%q,`, kind)
+	w.Write(data[1:])
+	return w.WriteByte('\n')
+}
+
+// rotate 关闭当前文件并依次重命名历史文件
+//
+// 失败时重新打开当前文件，之后的写入和 Close 仍然可用，下次写入时再尝试轮转
+func (j *JSONL) rotate() error {
+	err := j.f.Close()
+	if err != nil {
+		err = fmt.Errorf("关闭 JSONL 文件失败: %w", err)
+	} else {
+		err = j.shift()
+	}
+	if err != nil {
+		if openErr := j.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return err
+	}
+	return j.open()
+}
+
+// shift 把 path.i 重命名为 path.i+1，再把 path 重命名为 path.1
+func (j *JSONL) shift() error {
+	last := j.opts.MaxFiles
+	if last <= 0 {
+		// 全部保留时找到第一个不存在的序号
+		for last = 1; ; last++ {
+			if _, err := os.Stat(j.rotated(last)); os.IsNotExist(err) {
+				break
+			}
+		}
+	}
+	for i := last - 1; i >= 1; i-- {
+		if err := os.Rename(j.rotated(i), j.rotated(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("轮转 JSONL 文件失败: %w", err)
+		}
+	}
+	if err := os.Rename(j.path, j.rotated(1)); err != nil {
+		return fmt.Errorf("轮转 JSONL 文件失败: %w", err)
+	}
+	return nil
+}
+
+func (j *JSONL) rotated(n int) string {
+	return fmt.Sprintf("%s.%d", j.path, n)
+}
+
+// Close 关闭文件
+func (j *JSONL) Close() error {
+	return j.f.Close()
+}
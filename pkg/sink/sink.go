@@ -0,0 +1,143 @@
+// Package sink 把解码后的事件和区块投递给下游：JSONL 文件、SQLite、Webhook 和标准输出
+//
+// Sink.Write 返回 nil 表示批次已经持久化或被对方确认，调用方在此之后才保存检查点；
+// 进程在两者之间退出时重启后会再次投递同一批次，因此投递语义是至少一次，下游按
+// (BlockHash, LogIndex) 去重
+package sink
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/indexer"
+)
+
+// Event 解码后的一条日志，没有匹配的 ABI 时 Name 为空，输出原始 topic 和 data
+type Event struct {
+	Block     uint64             `json:"block"`
+	BlockHash string             `json:"blockHash"`
+	TxHash    string             `json:"txHash"`
+	LogIndex  uint               `json:"logIndex"`
+	Contract  string             `json:"contract"`
+	Name      string             `json:"event,omitempty"`
+	Signature string             `json:"signature,omitempty"`
+	Args      contract.EventArgs `json:"args,omitempty"`
+	Topics    []string           `json:"topics,omitempty"`
+	Data      string             `json:"data,omitempty"`
+	Removed   bool               `json:"removed,omitempty"` // 所在区块已被重组移出主链
+}
+
+// NewEvent 转换解码结果
+func NewEvent(ev *contract.DecodedEvent) Event {
+	l := ev.Log
+	out := Event{
+		Block:     l.BlockNumber,
+		BlockHash: l.BlockHash.Hex(),
+		TxHash:    l.TxHash.Hex(),
+		LogIndex:  l.Index,
+		Contract:  l.Address.Hex(),
+		Removed:   l.Removed,
+	}
+	if ev.Known() {
+		out.Name, out.Signature, out.Args = ev.Name, ev.Signature, ev.Args
+		return out
+	}
+	for _, topic := range l.Topics {
+		out.Topics = append(out.Topics, topic.Hex())
+	}
+	out.Data = hexutil.Encode(l.Data)
+	return out
+}
+
+// DecodeLogs 用 decoder 解码一组日志
+func DecodeLogs(decoder *contract.EventDecoder, logs []types.Log) []Event {
+	events := make([]Event, len(logs))
+	for i, l := range logs {
+		events[i] = NewEvent(decoder.Decode(l))
+	}
+	return events
+}
+
+// Block 区块摘要
+type Block struct {
+	Number     uint64 `json:"number"`
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parentHash"`
+	Time       uint64 `json:"time"`
+	TxCount    int    `json:"txCount"`
+	Removed    bool   `json:"removed,omitempty"` // 已被重组移出主链
+}
+
+// NewBlock 由区块头创建区块摘要，只有区块头时 txCount 传 0
+func NewBlock(header *types.Header, txCount int, removed bool) Block {
+	return Block{
+		Number:     header.Number.Uint64(),
+		Hash:       header.Hash().Hex(),
+		ParentHash: header.ParentHash.Hex(),
+		Time:       header.Time,
+		TxCount:    txCount,
+		Removed:    removed,
+	}
+}
+
+// Batch 一次投递的数据，对应区块区间 [From, To]
+type Batch struct {
+	From   uint64  `json:"from"`
+	To     uint64  `json:"to"`
+	Events []Event `json:"events,omitempty"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// Empty 批次中没有事件和区块
+func (b Batch) Empty() bool {
+	return len(b.Events) == 0 && len(b.Blocks) == 0
+}
+
+// Sink 事件和区块的投递目标
+//
+// Write 不会并发调用；返回 nil 之前必须已持久化或送达，失败时调用方不保存检查点并稍后重新投递
+type Sink interface {
+	Write(ctx context.Context, batch Batch) error
+	Close() error
+}
+
+// multi 依次写入多个 Sink
+type multi []Sink
+
+// Multi 把批次依次写入每个 Sink，任一失败即返回错误；重新投递时已成功的 Sink 会再次收到同一批次
+func Multi(sinks ...Sink) Sink {
+	if len(sinks) == 1 {
+		return sinks[0]
+	}
+	return multi(sinks)
+}
+
+func (m multi) Write(ctx context.Context, batch Batch) error {
+	for _, s := range m {
+		if err := s.Write(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multi) Close() error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler 创建索引器的 Handler：解码日志后写入 s，写入成功后索引器才保存检查点
+func Handler(decoder *contract.EventDecoder, s Sink) indexer.Handler {
+	return func(ctx context.Context, b indexer.Batch) error {
+		return s.Write(ctx, Batch{From: b.From, To: b.To, Events: DecodeLogs(decoder, b.Logs)})
+	}
+}
@@ -0,0 +1,249 @@
+package sink_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/contract"
+	"go-eth-learning/pkg/sink"
+)
+
+// transferBatch 构造包含一条 ERC20 Transfer 和一条未知日志的批次
+func transferBatch(t *testing.T, block uint64, removed bool) sink.Batch {
+	t.Helper()
+	decoder, err := contract.StandardEventDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	erc20, err := contract.ParseERC20ABI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	data, err := erc20.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockHash := common.BigToHash(new(big.Int).SetUint64(block))
+	logs := []types.Log{
+		{
+			Topics:      []common.Hash{erc20.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), {}},
+			Data:        data,
+			BlockNumber: block,
+			BlockHash:   blockHash,
+			Removed:     removed,
+		},
+		{Topics: []common.Hash{{1}}, Data: []byte{2}, BlockNumber: block, BlockHash: blockHash, Index: 1, Removed: removed},
+	}
+	return sink.Batch{From: block, To: block, Events: sink.DecodeLogs(decoder, logs)}
+}
+
+func TestJSONL_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	opts := sink.DefaultJSONLOptions()
+	opts.MaxBytes = 1 // 每个批次后都轮转
+	opts.MaxFiles = 2
+	s, err := sink.OpenJSONL(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for block := uint64(1); block <= 4; block++ {
+		if err := s.Write(ctx, transferBatch(t, block, block == 4)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只保留当前文件和 2 个历史文件，最早的批次已被删除
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("path.3 不应存在: %v", err)
+	}
+	for file, block := range map[string]uint64{path: 4, path + ".1": 3, path + ".2": 2} {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		var lines int
+		for scanner.Scan() {
+			var rec struct {
+				Type    string `json:"type"`
+				Block   uint64 `json:"block"`
+				Event   string `json:"event"`
+				Removed bool   `json:"removed"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			if rec.Type != "event" || rec.Block != block || rec.Removed != (block == 4) {
+				t.Errorf("%s: %s", file, scanner.Bytes())
+			}
+			lines++
+		}
+		f.Close()
+		if lines != 2 {
+			t.Errorf("%s 有 %d 行, want 2", file, lines)
+		}
+	}
+}
+
+func TestJSONL_RotateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	opts := sink.DefaultJSONLOptions()
+	opts.MaxBytes = 1
+	opts.MaxFiles = 1
+	s, err := sink.OpenJSONL(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := s.Write(ctx, transferBatch(t, 1, false)); err != nil {
+		t.Fatal(err)
+	}
+
+	// path.1 是非空目录，重命名失败
+	if err := os.MkdirAll(filepath.Join(path+".1", "x"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(ctx, transferBatch(t, 2, false)); err == nil {
+		t.Fatal("轮转失败时应返回错误")
+	}
+
+	// 轮转失败后文件仍然打开，问题排除后下次写入继续轮转
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(ctx, transferBatch(t, 3, false)); err != nil {
+		t.Fatalf("轮转失败后写入失败: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{path: `"block":3`, path + ".1": `"block":1`} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(string(data), want); lines != 2 || strings.Count(string(data), "\n") != 2 {
+			t.Errorf("%s:\n%s", file, data)
+		}
+	}
+}
+
+func TestSQLite_Upsert(t *testing.T) {
+	s, err := sink.OpenSQLite(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx := context.Background()
+
+	// 重复投递同一批次不产生重复行，回滚只更新 removed
+	for _, removed := range []bool{false, false, true} {
+		if err := s.Write(ctx, transferBatch(t, 7, removed)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db := s.DB()
+	var count, removed int
+	var value string
+	if err := db.QueryRowContext(ctx, `SELECT count(*), max(removed), max(value) FROM event_Transfer`).Scan(&count, &removed, &value); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || removed != 1 || value != "100" {
+		t.Errorf("event_Transfer: count=%d removed=%d value=%s", count, removed, value)
+	}
+	if err := db.QueryRowContext(ctx, `SELECT count(*) FROM raw_logs`).Scan(&count); err != nil || count != 1 {
+		t.Errorf("raw_logs: count=%d err=%v", count, err)
+	}
+}
+
+func TestWebhook_RetryAndSign(t *testing.T) {
+	const secret = "s3cret"
+	var requests, calls atomic.Int32
+	var delivery []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if !sink.Verify(secret, body, r.Header.Get(sink.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		delivery = append(delivery, r.Header.Get(sink.DeliveryHeader))
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var batch sink.Batch
+		if err := json.Unmarshal(body, &batch); err != nil || len(batch.Events) != 2 || batch.Events[0].Name != "Transfer" {
+			t.Errorf("收到 %s", body)
+		}
+	}))
+	defer srv.Close()
+
+	opts := sink.DefaultWebhookOptions()
+	opts.Secret = secret
+	opts.MinBackoff = time.Millisecond
+	wh := sink.NewWebhook(srv.URL, opts)
+	if err := wh.Write(context.Background(), transferBatch(t, 1, false)); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivery) != 3 || delivery[0] != delivery[2] {
+		t.Errorf("重试时投递 ID 应保持不变: %v", delivery)
+	}
+
+	// 签名错误是接收方拒绝，不重试
+	requests.Store(0)
+	opts.Secret = "wrong"
+	if err := sink.NewWebhook(srv.URL, opts).Write(context.Background(), transferBatch(t, 1, false)); err == nil {
+		t.Error("签名错误应返回错误")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("4xx 不应重试，收到 %d 次请求", n)
+	}
+}
+
+func TestWebhook_ErrorHidesURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	opts := sink.DefaultWebhookOptions()
+	opts.MaxRetries = 0
+
+	// 接收方拒绝和网络错误时，错误信息都只包含协议和主机
+	for _, check := range []func() error{
+		func() error {
+			return sink.NewWebhook(srv.URL+"/hooks/token-123?key=abc", opts).Write(context.Background(), transferBatch(t, 1, false))
+		},
+		func() error {
+			srv.Close()
+			return sink.NewWebhook(srv.URL+"/hooks/token-123?key=abc", opts).Write(context.Background(), transferBatch(t, 1, false))
+		},
+	} {
+		err := check()
+		if err == nil {
+			t.Fatal("投递应该失败")
+		}
+		if msg := err.Error(); strings.Contains(msg, "token-123") || strings.Contains(msg, "key=abc") || !strings.Contains(msg, srv.URL) {
+			t.Errorf("错误信息 = %q", msg)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite 驱动
+
+	"go-eth-learning/pkg/contract"
+)
+
+// eventColumns 每张事件表都有的列，同名的事件参数列加 arg_ 前缀
+var eventColumns = []string{"block_hash", "log_index", "block", "tx_hash", "contract", "signature", "removed"}
+
+// SQLite 写入 SQLite 数据库：每种事件一张表（event_<事件名>），参数各占一列，
+// 未知事件写入 raw_logs，区块写入 blocks
+//
+// 每个批次在一个事务中写入，按 (block_hash, log_index) 覆盖，重复投递和重组回滚只会更新已有的行
+type SQLite struct {
+	db      *sql.DB
+	columns map[string]map[string]bool // 已创建的事件表及其列
+}
+
+// OpenSQLite 打开或创建 SQLite 数据库文件
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 SQLite 数据库失败: %w", err)
+	}
+	s, err := NewSQLite(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewSQLite 使用已打开的数据库，创建 blocks 和 raw_logs 表
+func NewSQLite(db *sql.DB) (*SQLite, error) {
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		`CREATE TABLE IF NOT EXISTS blocks (
+			hash TEXT PRIMARY KEY, number INTEGER NOT NULL, parent_hash TEXT NOT NULL,
+			time INTEGER NOT NULL, tx_count INTEGER NOT NULL, removed INTEGER NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS raw_logs (
+			block_hash TEXT NOT NULL, log_index INTEGER NOT NULL, block INTEGER NOT NULL,
+			tx_hash TEXT NOT NULL, contract TEXT NOT NULL, topics TEXT NOT NULL, data TEXT NOT NULL,
+			removed INTEGER NOT NULL, PRIMARY KEY (block_hash, log_index))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("创建数据表失败: %w", err)
+		}
+	}
+	return &SQLite{db: db, columns: make(map[string]map[string]bool)}, nil
+}
+
+// Write 在一个事务中写入批次
+func (s *SQLite) Write(ctx context.Context, batch Batch) (err error) {
+	if batch.Empty() {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			// 回滚撤销了本事务中的建表和加列，下次写入时重新读取表结构
+			s.columns = make(map[string]map[string]bool)
+		}
+	}()
+
+	for _, b := range batch.Blocks {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO blocks (hash, number, parent_hash, time, tx_count, removed) VALUES (?, ?, ?, ?, ?, ?)`,
+			b.Hash, b.Number, b.ParentHash, b.Time, b.TxCount, b.Removed,
+		); err != nil {
+			return fmt.Errorf("写入区块 %d 失败: %w", b.Number, err)
+		}
+	}
+	for _, ev := range batch.Events {
+		if err := s.writeEvent(ctx, tx, ev); err != nil {
+			return fmt.Errorf("写入区块 %d 的日志 %d 失败: %w", ev.Block, ev.LogIndex, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLite) writeEvent(ctx context.Context, tx *sql.Tx, ev Event) error {
+	if ev.Name == "" {
+		topics, err := json.Marshal(ev.Topics)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO raw_logs (block_hash, log_index, block, tx_hash, contract, topics, data, removed) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			ev.BlockHash, ev.LogIndex, ev.Block, ev.TxHash, ev.Contract, string(topics), ev.Data, ev.Removed,
+		)
+		return err
+	}
+
+	table := "event_" + ev.Name
+	columns := append([]string(nil), eventColumns...)
+	values := []interface{}{ev.BlockHash, ev.LogIndex, ev.Block, ev.TxHash, ev.Contract, ev.Signature, ev.Removed}
+	for _, arg := range ev.Args {
+		columns = append(columns, argColumn(arg.Name))
+		values = append(values, contract.FormatString(arg.Value))
+	}
+	if err := s.ensureTable(ctx, tx, table, columns[len(eventColumns):]); err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdent(c)
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT OR REPLACE INTO %s (%s) VALUES (?%s)`,
+		quoteIdent(table), strings.Join(quoted, ", "), strings.Repeat(", ?", len(columns)-1)), values...)
+	return err
+}
+
+// ensureTable 创建事件表，并为同名但参数不同的事件（如 ERC20 与 ERC721 的 Transfer）补充缺少的列
+func (s *SQLite) ensureTable(ctx context.Context, tx *sql.Tx, table string, args []string) error {
+	known, ok := s.columns[table]
+	if !ok {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			block_hash TEXT NOT NULL, log_index INTEGER NOT NULL, block INTEGER NOT NULL,
+			tx_hash TEXT NOT NULL, contract TEXT NOT NULL, signature TEXT NOT NULL,
+			removed INTEGER NOT NULL, PRIMARY KEY (block_hash, log_index))`, quoteIdent(table)),
+		); err != nil {
+			return fmt.Errorf("创建数据表 %s 失败: %w", table, err)
+		}
+
+		known = make(map[string]bool)
+		rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+		if err != nil {
+			return fmt.Errorf("读取数据表 %s 失败: %w", table, err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				rows.Close()
+				return err
+			}
+			known[name] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		s.columns[table] = known
+	}
+
+	for _, c := range args {
+		if known[c] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s TEXT`, quoteIdent(table), quoteIdent(c))); err != nil {
+			return fmt.Errorf("添加列 %s.%s 失败: %w", table, c, err)
+		}
+		known[c] = true
+	}
+	return nil
+}
+
+// argColumn 参数对应的列名，避免与固定列重名
+func argColumn(name string) string {
+	for _, c := range eventColumns {
+		if c == name {
+			return "arg_" + name
+		}
+	}
+	return name
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// DB 返回底层数据库，用于查询已写入的数据
+func (s *SQLite) DB() *sql.DB {
+	return s.db
+}
+
+// Close 关闭数据库
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,79 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Stdout 输出到终端：文本格式每条记录一行，JSON 格式每行一个对象
+type Stdout struct {
+	w    io.Writer
+	json bool
+}
+
+// NewStdout 创建终端输出，asJSON 为 true 时每行输出一个 JSON 对象
+func NewStdout(w io.Writer, asJSON bool) *Stdout {
+	return &Stdout{w: w, json: asJSON}
+}
+
+// Write 输出批次中的区块和事件
+func (s *Stdout) Write(_ context.Context, batch Batch) error {
+	if s.json {
+		enc := json.NewEncoder(s.w)
+		for _, b := range batch.Blocks {
+			if err := enc.Encode(b); err != nil {
+				return err
+			}
+		}
+		for _, ev := range batch.Events {
+			if err := enc.Encode(ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, b := range batch.Blocks {
+		row := []string{
+			fmt.Sprintf("📦 #%d", b.Number),
+			time.Unix(int64(b.Time), 0).Format("15:04:05"),
+			fmt.Sprintf("交易: %d", b.TxCount),
+			b.Hash,
+		}
+		if b.Removed {
+			row = []string{fmt.Sprintf("↩️  #%d", b.Number), "已回滚", b.Hash}
+		}
+		if _, err := fmt.Fprintln(s.w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	for _, ev := range batch.Events {
+		if _, err := fmt.Fprintln(s.w, strings.Join(eventRow(ev), "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventRow 事件的文本格式：区块、交易、合约、事件名、参数；未知事件输出原始 topic 和 data
+func eventRow(ev Event) []string {
+	block := fmt.Sprint(ev.Block)
+	if ev.Removed {
+		block = "↩️ " + block
+	}
+	name, detail := ev.Name, ev.Args.String()
+	if name == "" {
+		name = "?"
+		detail = strings.Join(append(append([]string(nil), ev.Topics...), ev.Data), " ")
+	}
+	return []string{block, ev.TxHash, ev.Contract, name, detail}
+}
+
+// Close 不关闭底层 Writer
+func (s *Stdout) Close() error {
+	return nil
+}
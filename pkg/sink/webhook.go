@@ -0,0 +1,174 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader 请求体的 HMAC-SHA256 签名，格式为 sha256=<十六进制>
+	SignatureHeader = "X-Signature-256"
+	// DeliveryHeader 批次内容的 SHA-256，重新投递同一批次时不变，接收方可据此去重
+	DeliveryHeader = "X-Delivery-ID"
+)
+
+// WebhookOptions Webhook 参数
+type WebhookOptions struct {
+	Secret     string            // HMAC 密钥，为空时不签名
+	Headers    map[string]string // 附加的请求头，如 Authorization
+	Timeout    time.Duration     // 单次请求超时
+	MaxRetries int               // 失败后的最大重试次数，用尽后 Write 返回错误
+	MinBackoff time.Duration     // 第一次重试前的等待时间，之后逐次翻倍
+	MaxBackoff time.Duration     // 重试等待时间上限
+	Client     *http.Client      // 为空时使用 http.DefaultClient
+	OnError    func(error)       // 每次失败重试前的回调（可选），用于记录日志
+}
+
+// DefaultWebhookOptions 默认参数：10 秒超时，最多重试 5 次，等待 1s 起逐次翻倍至 30s
+func DefaultWebhookOptions() *WebhookOptions {
+	return &WebhookOptions{
+		Timeout:    10 * time.Second,
+		MaxRetries: 5,
+		MinBackoff: time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Webhook 把每个批次以 JSON POST 到 HTTP 地址，返回 2xx 视为送达
+//
+// 网络错误、5xx 和 429 按指数退避重试，其他 4xx 视为接收方拒绝，不再重试
+type Webhook struct {
+	url  string
+	name string // 只含协议和主机的地址，用于错误信息，路径和查询参数中常带有令牌
+	opts WebhookOptions
+}
+
+// NewWebhook 创建 Webhook，opts 为 nil 时使用默认参数
+func NewWebhook(rawURL string, opts *WebhookOptions) *Webhook {
+	if opts == nil {
+		opts = DefaultWebhookOptions()
+	}
+	w := &Webhook{url: rawURL, name: redactURL(rawURL), opts: *opts}
+	if w.opts.Client == nil {
+		w.opts.Client = http.DefaultClient
+	}
+	return w
+}
+
+// webhookError 一次投递失败，retry 表示是否可以重试
+type webhookError struct {
+	err   error
+	retry bool
+}
+
+func (e *webhookError) Error() string { return e.err.Error() }
+func (e *webhookError) Unwrap() error { return e.err }
+
+// Write 投递批次，失败时按参数重试
+func (w *Webhook) Write(ctx context.Context, batch Batch) error {
+	if batch.Empty() {
+		return nil
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("编码批次失败: %w", err)
+	}
+
+	backoff := w.opts.MinBackoff
+	for attempt := 0; ; attempt++ {
+		err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !err.retry || attempt >= w.opts.MaxRetries {
+			return fmt.Errorf("投递区块 %d-%d 到 %s 失败: %w", batch.From, batch.To, w.name, err)
+		}
+		if w.opts.OnError != nil {
+			w.opts.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, w.opts.MaxBackoff)
+	}
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) *webhookError {
+	if w.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.opts.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		// 解析错误中带有完整地址
+		return &webhookError{err: fmt.Errorf("无效的 Webhook 地址 %s", w.name)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set(DeliveryHeader, hex.EncodeToString(sum[:]))
+	if w.opts.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.opts.Secret, body))
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = w.name
+		}
+		return &webhookError{err: err, retry: true}
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout
+	return &webhookError{err: err, retry: retry}
+}
+
+// Sign 计算请求体的签名，即 SignatureHeader 的值
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 供接收方校验 SignatureHeader
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Close 无需释放资源
+func (w *Webhook) Close() error {
+	return nil
+}
+
+// redactURL 只保留协议和主机，无法解析时返回固定占位
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host
+}
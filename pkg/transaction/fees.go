@@ -0,0 +1,129 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeeOptions EIP-1559 费用估算参数
+type FeeOptions struct {
+	// HistoryBlocks eth_feeHistory 回看的区块数
+	HistoryBlocks uint64
+	// RewardPercentile 小费取历史区块中的第几百分位
+	RewardPercentile float64
+	// BaseFeeMultiplier maxFeePerGas = baseFee * BaseFeeMultiplier + 小费，
+	// 2 倍可以承受连续 6 个满块的 baseFee 上涨
+	BaseFeeMultiplier int64
+}
+
+// DefaultFeeOptions 默认费用估算参数
+func DefaultFeeOptions() FeeOptions {
+	return FeeOptions{
+		HistoryBlocks:     10,
+		RewardPercentile:  50,
+		BaseFeeMultiplier: 2,
+	}
+}
+
+// Fees 交易费用建议
+//
+// Legacy 为 true 时（链不支持 EIP-1559）只有 GasPrice 有效
+type Fees struct {
+	Legacy bool
+
+	BaseFee              *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+
+	GasPrice *big.Int
+}
+
+// SuggestFees 根据最新区块和 eth_feeHistory 估算费用，链不支持 EIP-1559 时回退到 gasPrice
+func (m *Manager) SuggestFees(ctx context.Context) (*Fees, error) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取区块头失败: %w", err)
+	}
+
+	// 伦敦升级前的链或不支持 EIP-1559 的链
+	if header.BaseFee == nil {
+		gasPrice, err := m.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取 Gas 价格失败: %w", err)
+		}
+		return &Fees{Legacy: true, GasPrice: gasPrice}, nil
+	}
+
+	history, err := m.client.FeeHistory(ctx, m.feeOpts.HistoryBlocks, nil, []float64{m.feeOpts.RewardPercentile})
+	if err != nil {
+		return nil, fmt.Errorf("获取费用历史失败: %w", err)
+	}
+
+	// 最后一项为下一个区块的 baseFee
+	baseFee := header.BaseFee
+	if n := len(history.BaseFee); n > 0 && history.BaseFee[n-1] != nil {
+		baseFee = history.BaseFee[n-1]
+	}
+
+	tip := medianReward(history.Reward)
+	if tip == nil {
+		if tip, err = m.client.SuggestGasTipCap(ctx); err != nil {
+			return nil, fmt.Errorf("获取建议小费失败: %w", err)
+		}
+	}
+
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(m.feeOpts.BaseFeeMultiplier))
+	maxFee.Add(maxFee, tip)
+
+	return &Fees{
+		BaseFee:              baseFee,
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: tip,
+	}, nil
+}
+
+// medianReward 取各区块百分位小费的中位数，忽略空块
+func medianReward(rewards [][]*big.Int) *big.Int {
+	var values []*big.Int
+	for _, r := range rewards {
+		if len(r) > 0 && r[0] != nil && r[0].Sign() > 0 {
+			values = append(values, r[0])
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	return new(big.Int).Set(values[len(values)/2])
+}
+
+// newTx 根据费用类型创建 DynamicFeeTx 或 LegacyTx
+func (m *Manager) newTx(nonce uint64, to *common.Address, value *big.Int, gasLimit uint64, data []byte, fees *Fees) *types.Transaction {
+	if fees.Legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       to,
+			Value:    value,
+			Gas:      gasLimit,
+			GasPrice: fees.GasPrice,
+			Data:     data,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   m.chainID,
+		Nonce:     nonce,
+		To:        to,
+		Value:     value,
+		Gas:       gasLimit,
+		GasTipCap: fees.MaxPriorityFeePerGas,
+		GasFeeCap: fees.MaxFeePerGas,
+		Data:      data,
+	})
+}
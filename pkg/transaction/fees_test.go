@@ -0,0 +1,141 @@
+package transaction_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/transaction"
+)
+
+// feeBackend 返回固定区块头和费用历史
+type feeBackend struct {
+	ethclient.Backend
+
+	baseFee  *big.Int // 最新区块的 baseFee，nil 表示链不支持 EIP-1559
+	history  *ethereum.FeeHistory
+	gasPrice *big.Int
+	tipCap   *big.Int
+
+	blocks      uint64 // 收到的 eth_feeHistory 参数
+	percentiles []float64
+}
+
+func (b *feeBackend) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), BaseFee: b.baseFee}, nil
+}
+
+func (b *feeBackend) FeeHistory(_ context.Context, blockCount uint64, _ *big.Int, percentiles []float64) (*ethereum.FeeHistory, error) {
+	b.blocks, b.percentiles = blockCount, percentiles
+	return b.history, nil
+}
+
+func (b *feeBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return b.gasPrice, nil
+}
+
+func (b *feeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return b.tipCap, nil
+}
+
+// rewards 每个区块一个百分位的小费，nil 表示空块（节点返回空行）
+func rewards(values ...*big.Int) [][]*big.Int {
+	rows := make([][]*big.Int, len(values))
+	for i, v := range values {
+		if v != nil {
+			rows[i] = []*big.Int{v}
+		}
+	}
+	return rows
+}
+
+func TestManager_SuggestFees(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseFee    *big.Int
+		history    *ethereum.FeeHistory
+		multiplier int64
+		want       transaction.Fees
+	}{
+		{
+			// 奇数个有效值取正中间，0 小费视为空块
+			name:    "median",
+			baseFee: gwei(10),
+			history: &ethereum.FeeHistory{
+				BaseFee: []*big.Int{gwei(10), gwei(11), gwei(12)},
+				Reward:  rewards(gwei(7), gwei(1), big.NewInt(0), gwei(4)),
+			},
+			multiplier: 2,
+			want:       transaction.Fees{BaseFee: gwei(12), MaxPriorityFeePerGas: gwei(4), MaxFeePerGas: gwei(2*12 + 4)},
+		},
+		{
+			// 偶数个有效值取较大的一个
+			name:    "even count",
+			baseFee: gwei(10),
+			history: &ethereum.FeeHistory{
+				BaseFee: []*big.Int{gwei(20)},
+				Reward:  rewards(gwei(5), gwei(1), nil, gwei(3), gwei(2)),
+			},
+			multiplier: 3,
+			want:       transaction.Fees{BaseFee: gwei(20), MaxPriorityFeePerGas: gwei(3), MaxFeePerGas: gwei(3*20 + 3)},
+		},
+		{
+			// 全是空块时使用节点建议的小费，费用历史没有 baseFee 时使用区块头的
+			name:       "empty rewards",
+			baseFee:    gwei(10),
+			history:    &ethereum.FeeHistory{Reward: rewards(nil, big.NewInt(0), nil)},
+			multiplier: 2,
+			want:       transaction.Fees{BaseFee: gwei(10), MaxPriorityFeePerGas: gwei(2), MaxFeePerGas: gwei(2*10 + 2)},
+		},
+		{
+			name:    "legacy",
+			history: &ethereum.FeeHistory{},
+			want:    transaction.Fees{Legacy: true, GasPrice: gwei(30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &feeBackend{baseFee: tt.baseFee, history: tt.history, gasPrice: gwei(30), tipCap: gwei(2)}
+			mgr := transaction.NewManager(backend, big.NewInt(1))
+			opts := transaction.DefaultFeeOptions()
+			opts.HistoryBlocks = 5
+			opts.RewardPercentile = 60
+			opts.BaseFeeMultiplier = tt.multiplier
+			mgr.SetFeeOptions(opts)
+
+			fees, err := mgr.SuggestFees(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fees.Legacy != tt.want.Legacy ||
+				!equalBig(fees.BaseFee, tt.want.BaseFee) ||
+				!equalBig(fees.MaxFeePerGas, tt.want.MaxFeePerGas) ||
+				!equalBig(fees.MaxPriorityFeePerGas, tt.want.MaxPriorityFeePerGas) ||
+				!equalBig(fees.GasPrice, tt.want.GasPrice) {
+				t.Errorf("SuggestFees = %+v, want %+v", fees, tt.want)
+			}
+
+			if tt.want.Legacy {
+				if backend.percentiles != nil {
+					t.Error("不支持 EIP-1559 的链不应查询费用历史")
+				}
+				return
+			}
+			if backend.blocks != 5 || len(backend.percentiles) != 1 || backend.percentiles[0] != 60 {
+				t.Errorf("eth_feeHistory 参数 = %d %v, want 5 [60]", backend.blocks, backend.percentiles)
+			}
+		})
+	}
+}
+
+func equalBig(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
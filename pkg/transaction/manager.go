@@ -3,71 +3,183 @@ package transaction
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
+
+	"go-eth-learning/pkg/ethclient"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/utils"
 )
 
 // Manager 交易管理器
 type Manager struct {
-	client  *ethclient.Client
+	client  ethclient.Backend
 	chainID *big.Int
+	feeOpts FeeOptions
+	nonces  *NonceManager
 }
 
 // NewManager 创建交易管理器
-func NewManager(client *ethclient.Client, chainID *big.Int) *Manager {
+func NewManager(client ethclient.Backend, chainID *big.Int) *Manager {
 	return &Manager{
 		client:  client,
 		chainID: chainID,
+		feeOpts: DefaultFeeOptions(),
+		nonces:  NewNonceManager(client),
 	}
 }
 
-// BuildTransferTx 构建转账交易
+// SetNonceManager 替换 nonce 管理器（例如使用持久化的管理器）
+func (m *Manager) SetNonceManager(nonces *NonceManager) {
+	m.nonces = nonces
+}
+
+// Nonces 返回当前使用的 nonce 管理器
+func (m *Manager) Nonces() *NonceManager {
+	return m.nonces
+}
+
+// SetFeeOptions 设置 EIP-1559 费用估算参数
+func (m *Manager) SetFeeOptions(opts FeeOptions) {
+	m.feeOpts = opts
+}
+
+// BuildTransferTx 构建转账交易，地址按链的校验和规则（EIP-55 / EIP-1191）校验
+//
+// 交易的 nonce 由 NonceManager 分配，构建后不发送时需调用 Nonces().Release 归还
 func (m *Manager) BuildTransferTx(
 	ctx context.Context,
 	from string,
 	to string,
 	amount *big.Int,
 ) (*types.Transaction, error) {
-	fromAddr := common.HexToAddress(from)
-	toAddr := common.HexToAddress(to)
+	fromAddr, err := utils.ParseAddressForChain(from, m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("发送地址: %w", err)
+	}
+	toAddr, err := utils.ParseAddressForChain(to, m.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("接收地址: %w", err)
+	}
 
-	// 获取 nonce
-	nonce, err := m.client.PendingNonceAt(ctx, fromAddr)
+	return m.buildTransfer(ctx, fromAddr, toAddr, amount)
+}
+
+// buildTransfer 构建已解析地址的转账交易
+func (m *Manager) buildTransfer(ctx context.Context, fromAddr, toAddr common.Address, amount *big.Int) (*types.Transaction, error) {
+	// 估算费用（不支持 EIP-1559 的链回退到 gasPrice）
+	fees, err := m.SuggestFees(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("获取 nonce 失败: %w", err)
+		return nil, err
 	}
 
-	// 获取 Gas 价格
-	gasPrice, err := m.client.SuggestGasPrice(ctx)
+	// 分配 nonce
+	nonce, err := m.nonces.Next(ctx, fromAddr)
 	if err != nil {
-		return nil, fmt.Errorf("获取 Gas 价格失败: %w", err)
+		return nil, err
 	}
 
 	// Gas 限制（ETH 转账固定 21000）
 	gasLimit := uint64(21000)
 
 	// 创建交易
-	tx := types.NewTransaction(nonce, toAddr, amount, gasLimit, gasPrice, nil)
+	tx := m.newTx(nonce, &toAddr, amount, gasLimit, nil, fees)
 
 	return tx, nil
 }
 
+// BuildTx 构建任意交易（合约调用、部署等），gas 限制由节点估算并预留 20% 余量
+//
+// to 为 nil 表示创建合约；nonce 分配规则同 BuildTransferTx
+func (m *Manager) BuildTx(
+	ctx context.Context,
+	from common.Address,
+	to *common.Address,
+	value *big.Int,
+	data []byte,
+) (*types.Transaction, error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	// 估算 gas，合约执行失败会在这里暴露
+	gasLimit, err := m.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("估算 Gas 失败: %w", err)
+	}
+	gasLimit = gasLimit * 12 / 10
+
+	fees, err := m.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := m.nonces.Next(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.newTx(nonce, to, value, gasLimit, data, fees), nil
+}
+
+// Send 构建、签名并发送交易
+func (m *Manager) Send(
+	ctx context.Context,
+	s signer.Signer,
+	to *common.Address,
+	value *big.Int,
+	data []byte,
+) (*types.Transaction, error) {
+	tx, err := m.BuildTx(ctx, s.Address(), to, value, data)
+	if err != nil {
+		return nil, err
+	}
+	return m.SignAndSend(ctx, tx, s)
+}
+
 // SignAndSend 签名并发送交易
 func (m *Manager) SignAndSend(
 	ctx context.Context,
 	tx *types.Transaction,
-	privateKey *ecdsa.PrivateKey,
+	s signer.Signer,
 ) (*types.Transaction, error) {
+	from := s.Address()
+	// 调用方自行指定的 nonce 不由 NonceManager 管理，发送失败时不能归还，否则会被重新分配
+	reserved := m.nonces.reserved(from, tx.Nonce())
+
+	signedTx, err := m.signAndBroadcast(ctx, tx, s)
+	if err != nil {
+		if reserved && errors.Is(err, errSign) {
+			_ = m.nonces.Release(from, tx.Nonce())
+		} else if reserved {
+			_ = m.nonces.HandleSendError(ctx, from, tx.Nonce(), err)
+		}
+		return nil, err
+	}
+	m.nonces.Confirm(from, tx.Nonce())
+
+	return signedTx, nil
+}
+
+// errSign 标记签名阶段的错误
+var errSign = errors.New("签名交易失败")
+
+// signAndBroadcast 签名并发送交易，不更新 nonce 状态（替换交易复用已占用的 nonce）
+func (m *Manager) signAndBroadcast(ctx context.Context, tx *types.Transaction, s signer.Signer) (*types.Transaction, error) {
 	// 签名交易
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(m.chainID), privateKey)
+	signedTx, err := s.SignTx(ctx, tx, m.chainID)
 	if err != nil {
-		return nil, fmt.Errorf("签名交易失败: %w", err)
+		return nil, fmt.Errorf("%w: %w", errSign, err)
 	}
 
 	// 发送交易
@@ -81,32 +193,23 @@ func (m *Manager) SignAndSend(
 // Transfer ETH 转账便捷方法
 func (m *Manager) Transfer(
 	ctx context.Context,
-	privateKeyHex string,
+	s signer.Signer,
 	to string,
 	amount *big.Int,
 ) (string, error) {
-	// 解析私钥
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	toAddr, err := utils.ParseAddressForChain(to, m.chainID)
 	if err != nil {
-		return "", fmt.Errorf("解析私钥失败: %w", err)
-	}
-
-	// 获取发送者地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", fmt.Errorf("无法转换公钥")
+		return "", fmt.Errorf("接收地址: %w", err)
 	}
-	from := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
 
 	// 构建交易
-	tx, err := m.BuildTransferTx(ctx, from, to, amount)
+	tx, err := m.buildTransfer(ctx, s.Address(), toAddr, amount)
 	if err != nil {
 		return "", err
 	}
 
 	// 签名并发送
-	signedTx, err := m.SignAndSend(ctx, tx, privateKey)
+	signedTx, err := m.SignAndSend(ctx, tx, s)
 	if err != nil {
 		return "", err
 	}
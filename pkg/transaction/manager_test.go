@@ -0,0 +1,85 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/signer"
+	"go-eth-learning/pkg/transaction"
+	"go-eth-learning/pkg/utils"
+)
+
+func TestManager_TransferChecksum(t *testing.T) {
+	chain := testchain.New(t, nil)
+	ctx := context.Background()
+	alice := chain.Accounts[0]
+	mgr := chain.TxManager()
+
+	// 校验和错误的接收地址在构建交易前被拒绝
+	if _, err := mgr.Transfer(ctx, alice.Signer, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", big.NewInt(1)); !errors.Is(err, utils.ErrBadChecksum) {
+		t.Fatalf("err = %v, want ErrBadChecksum", err)
+	}
+
+	// 全小写地址不带校验和，正常发送
+	to := strings.ToLower(chain.Accounts[1].Address.Hex())
+	if _, err := mgr.Transfer(ctx, alice.Signer, to, big.NewInt(params.Ether)); err != nil {
+		t.Fatalf("转账失败: %v", err)
+	}
+}
+
+// lagBackend 节点的 pending nonce 始终为 0（如节点落后或交易经其他节点广播）
+type lagBackend struct {
+	feeBackend
+
+	sendErr error
+}
+
+func (b *lagBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (b *lagBackend) SendTransaction(context.Context, *types.Transaction) error {
+	return b.sendErr
+}
+
+func TestManager_SignAndSendCallerNonce(t *testing.T) {
+	ctx := context.Background()
+	backend := &lagBackend{feeBackend: feeBackend{history: &ethereum.FeeHistory{}, gasPrice: gwei(1)}}
+	mgr := transaction.NewManager(backend, big.NewInt(1))
+	key, _ := crypto.GenerateKey()
+	s := signer.NewPrivateKeySigner(key)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	first, err := mgr.BuildTransferTx(ctx, s.Address().Hex(), to.Hex(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.SignAndSend(ctx, first, s); err != nil {
+		t.Fatal(err)
+	}
+
+	// 调用方自行构建、复用 nonce 0 的交易发送失败，不能把已发送交易的 nonce 归还
+	backend.sendErr = errors.New("insufficient funds for gas * price + value")
+	own := types.NewTx(&types.LegacyTx{Nonce: first.Nonce(), GasPrice: gwei(2), Gas: 21000, To: &to})
+	if _, err := mgr.SignAndSend(ctx, own, s); err == nil {
+		t.Fatal("发送应该失败")
+	}
+
+	next, err := mgr.BuildTransferTx(ctx, s.Address().Hex(), to.Hex(), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Nonce() != first.Nonce()+1 {
+		t.Errorf("nonce = %d, want %d", next.Nonce(), first.Nonce()+1)
+	}
+}
@@ -0,0 +1,328 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceSource 提供链上 pending nonce
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager 按账户在本地分配 nonce，保证并发发送时不会冲突
+//
+// 每次分配时都会对比节点的 pending nonce：节点更大时（其他程序发送了交易）向前对齐；
+// 节点更小且该 nonce 没有正在发送（或刚发送）的交易时，说明交易被丢弃留下了空洞，会优先复用该 nonce 填补。
+type NonceManager struct {
+	mu         sync.Mutex // 保护 accounts 和持久化文件，查询节点期间不持有
+	source     NonceSource
+	accounts   map[common.Address]*nonceState
+	locks      map[common.Address]*sync.Mutex // 账户锁，查询节点时只阻塞同一账户
+	path       string                         // 持久化文件路径，为空时不持久化
+	gapTimeout time.Duration                  // 已发送的交易多久未进入节点 pending 视为被丢弃
+}
+
+// 默认空洞判定时间
+const defaultGapTimeout = 2 * time.Minute
+
+// nonceState 单个账户的 nonce 状态
+type nonceState struct {
+	Next     uint64   `json:"next"`     // 下一个新 nonce
+	Released []uint64 `json:"released"` // 待复用的 nonce（发送失败或被丢弃）
+
+	Reserved map[uint64]time.Time `json:"reserved"` // 已分配的 nonce -> 发送时间（零值表示尚未发送）
+}
+
+// NewNonceManager 创建只保存在内存中的 nonce 管理器
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source:     source,
+		accounts:   make(map[common.Address]*nonceState),
+		locks:      make(map[common.Address]*sync.Mutex),
+		gapTimeout: defaultGapTimeout,
+	}
+}
+
+// SetGapTimeout 设置空洞判定时间
+func (nm *NonceManager) SetGapTimeout(timeout time.Duration) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	nm.gapTimeout = timeout
+}
+
+// NewPersistentNonceManager 创建持久化到文件的 nonce 管理器，文件存在时加载已有状态
+func NewPersistentNonceManager(source NonceSource, path string) (*NonceManager, error) {
+	nm := NewNonceManager(source)
+	nm.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nm, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取 nonce 状态失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &nm.accounts); err != nil {
+		return nil, fmt.Errorf("解析 nonce 状态失败: %w", err)
+	}
+	// 重启前正在发送的交易结果未知，从加载时刻开始计算空洞超时
+	now := time.Now()
+	for _, st := range nm.accounts {
+		if st.Reserved == nil {
+			st.Reserved = make(map[uint64]time.Time)
+		}
+		for nonce, sentAt := range st.Reserved {
+			if sentAt.IsZero() {
+				st.Reserved[nonce] = now
+			}
+		}
+	}
+	return nm, nil
+}
+
+// Next 为账户分配下一个 nonce，调用方必须随后调用 Confirm、Release 或 HandleSendError
+func (nm *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	unlock := nm.lockAccount(account)
+	defer unlock()
+
+	pending, err := nm.source.PendingNonceAt(ctx, account)
+	if err != nil {
+		return 0, fmt.Errorf("获取 nonce 失败: %w", err)
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	st := nm.state(account)
+	if pending > st.Next {
+		st.Next = pending
+	}
+	st.dropReleasedBelow(pending)
+	for nonce := range st.Reserved {
+		if nonce < pending {
+			delete(st.Reserved, nonce)
+		}
+	}
+
+	// 节点的 pending nonce 落后于本地且无人占用：交易已被丢弃，优先填补
+	if pending < st.Next && nm.isGap(st, pending) {
+		st.release(pending)
+	}
+
+	var nonce uint64
+	if len(st.Released) > 0 {
+		nonce = st.Released[0]
+		st.Released = st.Released[1:]
+	} else {
+		nonce = st.Next
+		st.Next++
+	}
+	st.Reserved[nonce] = time.Time{}
+
+	if err := nm.save(); err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+// Confirm 标记 nonce 对应的交易已被节点接收（在节点 pending nonce 超过它之前不会被当作空洞）
+func (nm *NonceManager) Confirm(account common.Address, nonce uint64) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	st := nm.state(account)
+	if _, ok := st.Reserved[nonce]; ok {
+		st.Reserved[nonce] = time.Now()
+	}
+}
+
+// reserved 判断 nonce 是否由 Next 分配且尚未发送
+func (nm *NonceManager) reserved(account common.Address, nonce uint64) bool {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	st, ok := nm.accounts[account]
+	if !ok {
+		return false
+	}
+	sentAt, ok := st.Reserved[nonce]
+	return ok && sentAt.IsZero()
+}
+
+// Release 归还未发送成功的 nonce，后续分配时优先复用
+func (nm *NonceManager) Release(account common.Address, nonce uint64) error {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	st := nm.state(account)
+	delete(st.Reserved, nonce)
+	st.release(nonce)
+	return nm.save()
+}
+
+// Resync 以节点的 pending nonce 为准重新对齐
+//
+// 节点已使用的 nonce 和已发送但节点没有的 nonce（如重组后）被丢弃；其他调用方已分配、尚未发送的
+// nonce 保留，Next 不会回退到它们之下，避免并发发送时重复分配；pending 与 Next 之间无人占用的 nonce 留待复用
+func (nm *NonceManager) Resync(ctx context.Context, account common.Address) error {
+	unlock := nm.lockAccount(account)
+	defer unlock()
+
+	pending, err := nm.source.PendingNonceAt(ctx, account)
+	if err != nil {
+		return fmt.Errorf("获取 nonce 失败: %w", err)
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	nm.state(account).resync(pending)
+	return nm.save()
+}
+
+// HandleSendError 根据发送错误更新状态：nonce 冲突时放弃该 nonce 并重新同步，其他错误归还 nonce
+func (nm *NonceManager) HandleSendError(ctx context.Context, account common.Address, nonce uint64, sendErr error) error {
+	if IsNonceError(sendErr) {
+		nm.mu.Lock()
+		delete(nm.state(account).Reserved, nonce)
+		nm.mu.Unlock()
+		return nm.Resync(ctx, account)
+	}
+	return nm.Release(account, nonce)
+}
+
+// IsNonceError 判断节点返回的错误是否由 nonce 冲突引起
+func IsNonceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{
+		"nonce too low",
+		"nonce too high",
+		"replacement transaction underpriced",
+		"invalid nonce",
+	} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// lockAccount 获取账户锁，返回解锁函数
+func (nm *NonceManager) lockAccount(account common.Address) func() {
+	nm.mu.Lock()
+	l, ok := nm.locks[account]
+	if !ok {
+		l = new(sync.Mutex)
+		nm.locks[account] = l
+	}
+	nm.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// state 返回账户状态，调用方需持有锁
+func (nm *NonceManager) state(account common.Address) *nonceState {
+	st, ok := nm.accounts[account]
+	if !ok {
+		st = &nonceState{Reserved: make(map[uint64]time.Time)}
+		nm.accounts[account] = st
+	}
+	return st
+}
+
+// isGap 判断 nonce 是否为空洞：未分配，或已发送但超时仍未进入节点 pending
+func (nm *NonceManager) isGap(st *nonceState, nonce uint64) bool {
+	sentAt, ok := st.Reserved[nonce]
+	if !ok {
+		return true
+	}
+	if sentAt.IsZero() {
+		return false
+	}
+	if time.Since(sentAt) < nm.gapTimeout {
+		return false
+	}
+	delete(st.Reserved, nonce)
+	return true
+}
+
+// save 原子写入持久化文件，调用方需持有锁
+func (nm *NonceManager) save() error {
+	if nm.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(nm.accounts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化 nonce 状态失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(nm.path), 0700); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmp := nm.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("写入 nonce 状态失败: %w", err)
+	}
+	if err := os.Rename(tmp, nm.path); err != nil {
+		return fmt.Errorf("写入 nonce 状态失败: %w", err)
+	}
+	return nil
+}
+
+// release 将 nonce 加入待复用列表（保持升序、去重）
+func (st *nonceState) release(nonce uint64) {
+	if nonce >= st.Next {
+		return
+	}
+	i := sort.Search(len(st.Released), func(i int) bool { return st.Released[i] >= nonce })
+	if i < len(st.Released) && st.Released[i] == nonce {
+		return
+	}
+	st.Released = append(st.Released, 0)
+	copy(st.Released[i+1:], st.Released[i:])
+	st.Released[i] = nonce
+}
+
+// resync 按节点的 pending nonce 重建状态：Next 取 pending 与最大的未发送 nonce + 1 中的较大者
+func (st *nonceState) resync(pending uint64) {
+	next := pending
+	for nonce, sentAt := range st.Reserved {
+		switch {
+		case nonce < pending || !sentAt.IsZero():
+			delete(st.Reserved, nonce)
+		case nonce >= next:
+			next = nonce + 1
+		}
+	}
+
+	st.Next = next
+	st.Released = nil
+	for nonce := pending; nonce < next; nonce++ {
+		if _, ok := st.Reserved[nonce]; !ok {
+			st.Released = append(st.Released, nonce)
+		}
+	}
+}
+
+// dropReleasedBelow 移除节点已经使用过的 nonce
+func (st *nonceState) dropReleasedBelow(pending uint64) {
+	i := sort.Search(len(st.Released), func(i int) bool { return st.Released[i] >= pending })
+	st.Released = st.Released[i:]
+}
@@ -0,0 +1,236 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/pkg/transaction"
+)
+
+// fakeNonceSource 模拟节点的 pending nonce
+type fakeNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+}
+
+func (f *fakeNonceSource) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pending, nil
+}
+
+func (f *fakeNonceSource) set(n uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = n
+}
+
+var account = common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb0")
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	source := &fakeNonceSource{pending: 5}
+	nm := transaction.NewNonceManager(source)
+	ctx := context.Background()
+
+	const workers = 50
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nm.Next(ctx, account)
+			if err != nil {
+				t.Errorf("分配 nonce 失败: %v", err)
+				return
+			}
+			nm.Confirm(account, nonce)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[nonce] {
+				t.Errorf("nonce %d 重复分配", nonce)
+			}
+			seen[nonce] = true
+		}()
+	}
+	wg.Wait()
+
+	for n := uint64(5); n < 5+workers; n++ {
+		if !seen[n] {
+			t.Errorf("nonce %d 未分配", n)
+		}
+	}
+}
+
+func TestNonceManager_ReleaseAndResync(t *testing.T) {
+	source := &fakeNonceSource{pending: 0}
+	nm := transaction.NewNonceManager(source)
+	ctx := context.Background()
+
+	n0, _ := nm.Next(ctx, account)
+	n1, _ := nm.Next(ctx, account)
+	if n0 != 0 || n1 != 1 {
+		t.Fatalf("nonce = %d, %d, want 0, 1", n0, n1)
+	}
+
+	// 发送失败的 nonce 应该被复用
+	if err := nm.HandleSendError(ctx, account, n0, errors.New("insufficient funds")); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := nm.Next(ctx, account); n != 0 {
+		t.Errorf("复用的 nonce = %d, want 0", n)
+	}
+
+	// nonce too low 时以节点为准
+	source.set(10)
+	if err := nm.HandleSendError(ctx, account, 1, errors.New("nonce too low")); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := nm.Next(ctx, account); n != 10 {
+		t.Errorf("重新同步后 nonce = %d, want 10", n)
+	}
+
+	// 其他调用方仍在发送 11、12 时，13 遇到 nonce 冲突：重新同步不能再次分配 11、12
+	n11, _ := nm.Next(ctx, account)
+	n12, _ := nm.Next(ctx, account)
+	n13, _ := nm.Next(ctx, account)
+	if err := nm.HandleSendError(ctx, account, n13, errors.New("nonce too high")); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[uint64]bool)
+	for i := 0; i < 2; i++ {
+		n, _ := nm.Next(ctx, account)
+		got[n] = true
+	}
+	if got[n11] || got[n12] || !got[13] || !got[14] {
+		t.Errorf("重新同步后分配了 %v，在途的 %d、%d 不应重复分配", got, n11, n12)
+	}
+}
+
+// blockingSource 查询指定账户时阻塞，直到 release 被关闭
+type blockingSource struct {
+	blocked common.Address
+	release chan struct{}
+}
+
+func (b *blockingSource) PendingNonceAt(_ context.Context, a common.Address) (uint64, error) {
+	if a == b.blocked {
+		<-b.release
+	}
+	return 0, nil
+}
+
+func TestNonceManager_SlowAccountDoesNotBlockOthers(t *testing.T) {
+	other := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	source := &blockingSource{blocked: other, release: make(chan struct{})}
+	nm := transaction.NewNonceManager(source)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		nm.Next(ctx, other)
+	}()
+	defer func() {
+		close(source.release)
+		<-done
+	}()
+
+	// 另一个账户的查询卡住时，本账户仍能分配 nonce
+	result := make(chan uint64, 1)
+	go func() {
+		n, _ := nm.Next(ctx, account)
+		result <- n
+	}()
+	select {
+	case n := <-result:
+		if n != 0 {
+			t.Errorf("nonce = %d, want 0", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("分配 nonce 被其他账户的节点查询阻塞")
+	}
+}
+
+func TestNonceManager_FillGap(t *testing.T) {
+	source := &fakeNonceSource{pending: 0}
+	nm := transaction.NewNonceManager(source)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		n, _ := nm.Next(ctx, account)
+		nm.Confirm(account, n)
+	}
+
+	// 节点只收到了 nonce 0，nonce 1 被丢弃
+	nm.SetGapTimeout(0)
+	source.set(1)
+	if n, _ := nm.Next(ctx, account); n != 1 {
+		t.Errorf("填补空洞的 nonce = %d, want 1", n)
+	}
+	if n, _ := nm.Next(ctx, account); n != 3 {
+		t.Errorf("下一个 nonce = %d, want 3", n)
+	}
+}
+
+func TestNonceManager_RecentlySentIsNotGap(t *testing.T) {
+	source := &fakeNonceSource{pending: 0}
+	nm := transaction.NewNonceManager(source)
+	nm.SetGapTimeout(time.Hour)
+	ctx := context.Background()
+
+	n, _ := nm.Next(ctx, account)
+	nm.Confirm(account, n)
+
+	// 节点尚未把刚发送的交易计入 pending，不应被当作空洞
+	if n, _ := nm.Next(ctx, account); n != 1 {
+		t.Errorf("nonce = %d, want 1", n)
+	}
+}
+
+func TestNonceManager_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nonces.json")
+	source := &fakeNonceSource{pending: 0}
+	ctx := context.Background()
+
+	nm, err := transaction.NewPersistentNonceManager(source, path)
+	if err != nil {
+		t.Fatalf("创建管理器失败: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		n, _ := nm.Next(ctx, account)
+		nm.Confirm(account, n)
+	}
+
+	// 重启后节点还没看到这些交易，不应从 0 开始重新分配
+	restarted, err := transaction.NewPersistentNonceManager(source, path)
+	if err != nil {
+		t.Fatalf("重新加载失败: %v", err)
+	}
+	if n, _ := restarted.Next(ctx, account); n != 3 {
+		t.Errorf("nonce = %d, want 3", n)
+	}
+}
+
+func TestIsNonceError(t *testing.T) {
+	if !transaction.IsNonceError(errors.New("nonce too low: next nonce 5, tx nonce 4")) {
+		t.Error("应该识别 nonce too low")
+	}
+	if transaction.IsNonceError(errors.New("insufficient funds for gas * price + value")) {
+		t.Error("余额不足不是 nonce 错误")
+	}
+	// 相同交易已在交易池中，nonce 已被这笔交易使用，不需要重新同步
+	if transaction.IsNonceError(errors.New("already known")) {
+		t.Error("already known 不是 nonce 错误")
+	}
+}
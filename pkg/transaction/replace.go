@@ -0,0 +1,171 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"go-eth-learning/pkg/signer"
+)
+
+// MinReplacementBump 节点接受替换交易的最低费用涨幅（geth txpool 默认 --txpool.pricebump=10）
+const MinReplacementBump = 10
+
+var (
+	// ErrTxNotPending 交易已上链或不存在，无法替换
+	ErrTxNotPending = errors.New("交易不在交易池中")
+	// ErrNotTxSender 签名器不是原交易的发送者
+	ErrNotTxSender = errors.New("签名账户不是原交易发送者")
+)
+
+// SpeedUp 以相同 nonce 和更高费用重发交易，bumpPercent 低于节点阈值时按阈值计算
+func (m *Manager) SpeedUp(ctx context.Context, s signer.Signer, txHash common.Hash, bumpPercent int) (*types.Transaction, error) {
+	orig, err := m.pendingTxFrom(ctx, s, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := m.replacementFees(ctx, orig, bumpPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := m.replacementTx(orig, orig.To(), orig.Value(), orig.Gas(), orig.Data(), orig.AccessList(), fees)
+	if err != nil {
+		return nil, err
+	}
+	return m.signAndBroadcast(ctx, tx, s)
+}
+
+// Cancel 用同 nonce 的 0 值自转账替换交易，交易类型与原交易相同
+func (m *Manager) Cancel(ctx context.Context, s signer.Signer, txHash common.Hash) (*types.Transaction, error) {
+	orig, err := m.pendingTxFrom(ctx, s, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := m.replacementFees(ctx, orig, MinReplacementBump)
+	if err != nil {
+		return nil, err
+	}
+
+	// 自转账不访问其他账户和存储，不需要 access list，21000 gas 足够
+	self := s.Address()
+	tx, err := m.replacementTx(orig, &self, big.NewInt(0), 21000, nil, nil, fees)
+	if err != nil {
+		return nil, err
+	}
+	return m.signAndBroadcast(ctx, tx, s)
+}
+
+// pendingTxFrom 查询仍在交易池中的交易并校验发送者
+func (m *Manager) pendingTxFrom(ctx context.Context, s signer.Signer, txHash common.Hash) (*types.Transaction, error) {
+	tx, isPending, err := m.client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("查询交易失败: %w", err)
+	}
+	if !isPending {
+		return nil, fmt.Errorf("%w: %s", ErrTxNotPending, txHash.Hex())
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(m.chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("解析交易发送者失败: %w", err)
+	}
+	if from != s.Address() {
+		return nil, fmt.Errorf("%w: %s", ErrNotTxSender, from.Hex())
+	}
+	return tx, nil
+}
+
+// replacementTx 按原交易的类型构造替换交易，只有费用字段使用 fees
+func (m *Manager) replacementTx(orig *types.Transaction, to *common.Address, value *big.Int, gas uint64, data []byte, accessList types.AccessList, fees *Fees) (*types.Transaction, error) {
+	switch orig.Type() {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    orig.Nonce(),
+			To:       to,
+			Value:    value,
+			Gas:      gas,
+			GasPrice: fees.GasPrice,
+			Data:     data,
+		}), nil
+	case types.AccessListTxType:
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    m.chainID,
+			Nonce:      orig.Nonce(),
+			To:         to,
+			Value:      value,
+			Gas:        gas,
+			GasPrice:   fees.GasPrice,
+			Data:       data,
+			AccessList: accessList,
+		}), nil
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    m.chainID,
+			Nonce:      orig.Nonce(),
+			To:         to,
+			Value:      value,
+			Gas:        gas,
+			GasTipCap:  fees.MaxPriorityFeePerGas,
+			GasFeeCap:  fees.MaxFeePerGas,
+			Data:       data,
+			AccessList: accessList,
+		}), nil
+	}
+	return nil, fmt.Errorf("不支持替换类型为 %d 的交易", orig.Type())
+}
+
+// replacementFees 计算替换交易费用：原费用上涨 bumpPercent，且不低于当前建议费用
+func (m *Manager) replacementFees(ctx context.Context, orig *types.Transaction, bumpPercent int) (*Fees, error) {
+	if bumpPercent < MinReplacementBump {
+		bumpPercent = MinReplacementBump
+	}
+
+	current, err := m.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 原交易只有 gasPrice（legacy 和 access list 交易）时替换交易同样只上调 gasPrice，
+	// 取值同时满足 tip 和 feeCap 的涨幅要求
+	if orig.Type() == types.LegacyTxType || orig.Type() == types.AccessListTxType {
+		gasPrice := maxBig(bump(orig.GasPrice(), bumpPercent), current.GasPrice, current.MaxFeePerGas)
+		return &Fees{Legacy: true, GasPrice: gasPrice}, nil
+	}
+
+	tip := maxBig(bump(orig.GasTipCap(), bumpPercent), current.MaxPriorityFeePerGas)
+	feeCap := maxBig(bump(orig.GasFeeCap(), bumpPercent), current.MaxFeePerGas)
+	if feeCap.Cmp(tip) < 0 {
+		feeCap = new(big.Int).Set(tip)
+	}
+
+	return &Fees{
+		BaseFee:              current.BaseFee,
+		MaxFeePerGas:         feeCap,
+		MaxPriorityFeePerGas: tip,
+	}, nil
+}
+
+// bump 按百分比上调并加 1 wei，避免整数截断导致刚好低于阈值
+func bump(value *big.Int, percent int) *big.Int {
+	result := new(big.Int).Mul(value, big.NewInt(int64(100+percent)))
+	result.Div(result, big.NewInt(100))
+	return result.Add(result, big.NewInt(1))
+}
+
+// maxBig 返回非 nil 值中的最大值
+func maxBig(values ...*big.Int) *big.Int {
+	var result *big.Int
+	for _, v := range values {
+		if v != nil && (result == nil || v.Cmp(result) > 0) {
+			result = v
+		}
+	}
+	return new(big.Int).Set(result)
+}
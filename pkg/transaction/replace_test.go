@@ -0,0 +1,132 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"go-eth-learning/internal/testchain"
+	"go-eth-learning/pkg/transaction"
+)
+
+func TestManager_SpeedUpAndCancel(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	mgr := chain.TxManager()
+
+	orig, err := mgr.Send(ctx, alice.Signer, &bob.Address, big.NewInt(params.Ether), nil)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	fast, err := mgr.SpeedUp(ctx, alice.Signer, orig.Hash(), 20)
+	if err != nil {
+		t.Fatalf("加速交易失败: %v", err)
+	}
+	if fast.Nonce() != orig.Nonce() || fast.GasTipCap().Cmp(orig.GasTipCap()) <= 0 {
+		t.Errorf("加速交易 nonce = %d, tip = %s", fast.Nonce(), fast.GasTipCap())
+	}
+
+	cancel, err := mgr.Cancel(ctx, alice.Signer, fast.Hash())
+	if err != nil {
+		t.Fatalf("取消交易失败: %v", err)
+	}
+	if *cancel.To() != alice.Address || cancel.Value().Sign() != 0 {
+		t.Errorf("取消交易 to = %s, value = %s", cancel.To().Hex(), cancel.Value())
+	}
+
+	block := chain.Commit()
+	if len(block.Transactions()) != 1 || block.Transactions()[0].Hash() != cancel.Hash() {
+		t.Fatalf("区块应只包含取消交易")
+	}
+
+	// 交易上链后不能再替换
+	if _, err := mgr.SpeedUp(ctx, alice.Signer, cancel.Hash(), 20); !errors.Is(err, transaction.ErrTxNotPending) {
+		t.Errorf("上链后加速错误 = %v, want ErrTxNotPending", err)
+	}
+}
+
+// sendRaw 签名并发送指定类型的交易，费用远高于模拟链的建议值，替换费用只由涨幅决定
+func sendRaw(t *testing.T, chain *testchain.Chain, data types.TxData) *types.Transaction {
+	t.Helper()
+	ctx := context.Background()
+	signed, err := chain.Accounts[0].Signer.SignTx(ctx, types.NewTx(data), chain.ChainID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.Eth().SendTransaction(ctx, signed); err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	return signed
+}
+
+func gwei(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.GWei))
+}
+
+// bumped 计算 n gwei 上调 percent% 再加 1 wei
+func bumped(n, percent int64) *big.Int {
+	v := new(big.Int).Mul(gwei(n), big.NewInt(100+percent))
+	v.Div(v, big.NewInt(100))
+	return v.Add(v, big.NewInt(1))
+}
+
+func TestManager_ReplacementKeepsTypeAndAccessList(t *testing.T) {
+	opts := testchain.DefaultOptions()
+	opts.ManualCommit = true
+	chain := testchain.New(t, opts)
+	ctx := context.Background()
+	alice, bob := chain.Accounts[0], chain.Accounts[1]
+	mgr := chain.TxManager()
+	accessList := types.AccessList{{Address: bob.Address, StorageKeys: []common.Hash{{1}}}}
+
+	// access list 交易：保持类型 1 和 access list，只上调 gasPrice；涨幅低于 10% 时按 10% 计算
+	orig := sendRaw(t, chain, &types.AccessListTx{
+		ChainID: chain.ChainID(), Nonce: 0, To: &bob.Address, Value: big.NewInt(1),
+		Gas: 30000, GasPrice: gwei(100), AccessList: accessList,
+	})
+	fast, err := mgr.SpeedUp(ctx, alice.Signer, orig.Hash(), 5)
+	if err != nil {
+		t.Fatalf("加速交易失败: %v", err)
+	}
+	if fast.Type() != types.AccessListTxType || len(fast.AccessList()) != 1 || fast.AccessList()[0].Address != bob.Address {
+		t.Errorf("加速交易类型 = %d, access list = %v", fast.Type(), fast.AccessList())
+	}
+	if fast.GasPrice().Cmp(bumped(100, 10)) != 0 || fast.Gas() != orig.Gas() || fast.Value().Cmp(orig.Value()) != 0 {
+		t.Errorf("加速交易 gasPrice = %s, want %s", fast.GasPrice(), bumped(100, 10))
+	}
+
+	// 取消交易保持类型，不带 access list
+	cancel, err := mgr.Cancel(ctx, alice.Signer, fast.Hash())
+	if err != nil {
+		t.Fatalf("取消交易失败: %v", err)
+	}
+	if cancel.Type() != types.AccessListTxType || len(cancel.AccessList()) != 0 || cancel.Gas() != 21000 {
+		t.Errorf("取消交易类型 = %d, access list = %v, gas = %d", cancel.Type(), cancel.AccessList(), cancel.Gas())
+	}
+	chain.Commit()
+
+	// EIP-1559 交易：tip 和 feeCap 都按涨幅上调，access list 保留
+	orig = sendRaw(t, chain, &types.DynamicFeeTx{
+		ChainID: chain.ChainID(), Nonce: 1, To: &bob.Address, Value: big.NewInt(1),
+		Gas: 30000, GasTipCap: gwei(1000), GasFeeCap: gwei(2000), AccessList: accessList,
+	})
+	fast, err = mgr.SpeedUp(ctx, alice.Signer, orig.Hash(), 20)
+	if err != nil {
+		t.Fatalf("加速交易失败: %v", err)
+	}
+	if fast.Type() != types.DynamicFeeTxType || len(fast.AccessList()) != 1 {
+		t.Errorf("加速交易类型 = %d, access list = %v", fast.Type(), fast.AccessList())
+	}
+	if fast.GasTipCap().Cmp(bumped(1000, 20)) != 0 || fast.GasFeeCap().Cmp(bumped(2000, 20)) != 0 {
+		t.Errorf("加速交易 tip = %s, feeCap = %s, want %s, %s", fast.GasTipCap(), fast.GasFeeCap(), bumped(1000, 20), bumped(2000, 20))
+	}
+}
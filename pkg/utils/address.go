@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrInvalidAddress 地址格式错误：缺少 0x 前缀、长度不是 40 个十六进制字符或包含非十六进制字符
+	ErrInvalidAddress = errors.New("无效的地址格式")
+	// ErrBadChecksum 大小写混合的地址校验和不匹配，通常是抄错了某个字符
+	ErrBadChecksum = errors.New("地址校验和错误")
+)
+
+// EIP1191ChainIDs 采用 EIP-1191 校验和的链：RSK 主网和测试网
+var EIP1191ChainIDs = []int64{30, 31}
+
+// AddressError 地址解析错误，Err 为 ErrInvalidAddress 或 ErrBadChecksum
+type AddressError struct {
+	Input string
+	Err   error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Input)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// UsesEIP1191 判断链是否使用 EIP-1191 校验和
+func UsesEIP1191(chainID *big.Int) bool {
+	if chainID == nil || !chainID.IsInt64() {
+		return false
+	}
+	for _, id := range EIP1191ChainIDs {
+		if chainID.Int64() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ChecksumAddress 返回 EIP-55 校验和格式的地址
+func ChecksumAddress(addr common.Address) string {
+	return ChecksumAddressForChain(addr, nil)
+}
+
+// ChecksumAddressForChain 返回链对应的校验和格式：EIP-1191 链把链 ID 加入哈希，其余链使用 EIP-55
+func ChecksumAddressForChain(addr common.Address, chainID *big.Int) string {
+	lower := hex.EncodeToString(addr.Bytes())
+
+	input := lower
+	if UsesEIP1191(chainID) {
+		input = chainID.String() + "0x" + lower
+	}
+	hash := crypto.Keccak256([]byte(input))
+
+	out := []byte(lower)
+	for i, c := range out {
+		// 哈希对应半字节 >= 8 的字母大写
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+// ParseAddress 解析并校验 EIP-55 地址
+//
+// 全小写或全大写的地址不带校验和，直接接受；大小写混合时必须与校验和一致，否则返回 ErrBadChecksum
+func ParseAddress(s string) (common.Address, error) {
+	return ParseAddressForChain(s, nil)
+}
+
+// ParseAddressForChain 按链的校验和规则（EIP-55 或 EIP-1191）解析并校验地址
+func ParseAddressForChain(s string, chainID *big.Int) (common.Address, error) {
+	input := s
+	s = strings.TrimSpace(s)
+	if len(s) != 2+2*common.AddressLength || (s[:2] != "0x" && s[:2] != "0X") {
+		return common.Address{}, &AddressError{Input: input, Err: ErrInvalidAddress}
+	}
+
+	body := s[2:]
+	raw, err := hex.DecodeString(body)
+	if err != nil {
+		return common.Address{}, &AddressError{Input: input, Err: ErrInvalidAddress}
+	}
+	addr := common.BytesToAddress(raw)
+
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return addr, nil
+	}
+	if ChecksumAddressForChain(addr, chainID)[2:] != body {
+		return common.Address{}, &AddressError{Input: input, Err: ErrBadChecksum}
+	}
+	return addr, nil
+}
+
+// IsChecksumAddress 判断地址是否为正确的 EIP-55 校验和格式（全小写、全大写返回 false）
+func IsChecksumAddress(s string) bool {
+	addr, err := ParseAddress(s)
+	return err == nil && ChecksumAddress(addr) == s
+}
@@ -0,0 +1,72 @@
+package utils_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"go-eth-learning/pkg/utils"
+)
+
+func TestParseAddress(t *testing.T) {
+	want := common.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	tests := []struct {
+		in  string
+		err error
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", nil},
+		{" 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed ", nil},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", utils.ErrBadChecksum},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", utils.ErrInvalidAddress},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", utils.ErrInvalidAddress},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeG", utils.ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		got, err := utils.ParseAddress(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseAddress(%q) err = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != want {
+			t.Errorf("ParseAddress(%q) = %s", tt.in, got.Hex())
+		}
+		var addrErr *utils.AddressError
+		if err != nil && !errors.As(err, &addrErr) {
+			t.Errorf("ParseAddress(%q) 错误类型 = %T, want *AddressError", tt.in, err)
+		}
+	}
+}
+
+func TestChecksumAddressForChain(t *testing.T) {
+	// EIP-1191 测试向量
+	tests := []struct {
+		chainID int64
+		want    string
+	}{
+		{1, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{30, "0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD"},
+		{31, "0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd"},
+	}
+	addr := common.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	for _, tt := range tests {
+		chainID := big.NewInt(tt.chainID)
+		if got := utils.ChecksumAddressForChain(addr, chainID); got != tt.want {
+			t.Errorf("链 %d 校验和 = %s, want %s", tt.chainID, got, tt.want)
+		}
+		if _, err := utils.ParseAddressForChain(tt.want, chainID); err != nil {
+			t.Errorf("链 %d 解析 %s 失败: %v", tt.chainID, tt.want, err)
+		}
+	}
+
+	// RSK 上的 EIP-55 校验和不成立
+	if _, err := utils.ParseAddressForChain("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", big.NewInt(30)); !errors.Is(err, utils.ErrBadChecksum) {
+		t.Errorf("err = %v, want ErrBadChecksum", err)
+	}
+	if !utils.IsChecksumAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed") || utils.IsChecksumAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed") {
+		t.Error("IsChecksumAddress 结果错误")
+	}
+}
@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// 常用单位的小数位数
+const (
+	WeiDecimals   uint8 = 0
+	GweiDecimals  uint8 = 9
+	EtherDecimals uint8 = 18
+)
+
+// ErrExcessPrecision 金额的小数位超过单位精度
+var ErrExcessPrecision = errors.New("金额精度超过单位的小数位数")
+
+// RoundingMode 金额超出精度时的舍入方式
+type RoundingMode int
+
+const (
+	// RoundExact 不舍入，超出精度时返回 ErrExcessPrecision
+	RoundExact RoundingMode = iota
+	// RoundDown 向零截断
+	RoundDown
+	// RoundUp 远离零进位
+	RoundUp
+	// RoundHalfUp 四舍五入，恰好一半时远离零
+	RoundHalfUp
+	// RoundHalfEven 银行家舍入，恰好一半时取偶数
+	RoundHalfEven
+)
+
+// String 返回舍入方式的名称
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundExact:
+		return "exact"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfEven:
+		return "half-even"
+	default:
+		return fmt.Sprintf("RoundingMode(%d)", int(m))
+	}
+}
+
+// ParseUnits 将十进制字符串精确转换为最小单位，小数位超过 decimals 时返回 ErrExcessPrecision
+//
+// 只接受 [+-]整数[.小数] 形式，如 "1"、"0.1"、".5"、"-2.25"，不接受科学计数法和千分位
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	return ParseUnitsRound(s, decimals, RoundExact)
+}
+
+// ParseUnitsRound 将十进制字符串转换为最小单位，超出精度的部分按 mode 舍入
+func ParseUnitsRound(s string, decimals uint8, mode RoundingMode) (*big.Int, error) {
+	neg, whole, frac, err := splitDecimal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// 多出的小数位单独舍入，整数部分和保留的小数位直接拼接，全程不经过浮点数
+	var rest string
+	if len(frac) > int(decimals) {
+		frac, rest = frac[:decimals], frac[decimals:]
+	}
+	digits := whole + frac + strings.Repeat("0", int(decimals)-len(frac))
+
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("无效金额: %s", s)
+	}
+	if strings.Trim(rest, "0") != "" {
+		up, err := roundAway(mode, amount, rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s 最多 %d 位小数", err, s, decimals)
+		}
+		if up {
+			amount.Add(amount, big.NewInt(1))
+		}
+	}
+	if neg {
+		amount.Neg(amount)
+	}
+	return amount, nil
+}
+
+// FormatUnits 将最小单位精确格式化为十进制字符串，去掉小数末尾的 0
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	s := formatFixed(new(big.Int).Abs(amount), decimals)
+	if decimals > 0 {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	if amount.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// FormatUnitsRound 将最小单位格式化为保留 places 位小数的十进制字符串，舍去的部分按 mode 舍入
+//
+// places 不小于 decimals 时结果精确，mode 为 RoundExact 且需要舍入时返回 ErrExcessPrecision
+func FormatUnitsRound(amount *big.Int, decimals, places uint8, mode RoundingMode) (string, error) {
+	abs := new(big.Int).Abs(amount)
+	if places < decimals {
+		digits := formatFixed(abs, decimals)
+		cut := len(digits) - int(decimals-places)
+		rest := digits[cut:]
+
+		abs.Quo(abs, pow10(decimals-places))
+		if strings.Trim(rest, "0") != "" {
+			up, err := roundAway(mode, abs, rest)
+			if err != nil {
+				return "", fmt.Errorf("%w: 无法精确保留 %d 位小数", err, places)
+			}
+			if up {
+				abs.Add(abs, big.NewInt(1))
+			}
+		}
+		decimals = places
+	}
+
+	s := formatFixed(abs, decimals)
+	if places > decimals {
+		if decimals == 0 {
+			s += "."
+		}
+		s += strings.Repeat("0", int(places-decimals))
+	}
+	if amount.Sign() < 0 && strings.Trim(s, "0.") != "" {
+		s = "-" + s
+	}
+	return s, nil
+}
+
+// ParseEther 将 ETH 数量精确转换为 wei
+func ParseEther(s string) (*big.Int, error) {
+	return ParseUnits(s, EtherDecimals)
+}
+
+// FormatEther 将 wei 精确格式化为 ETH
+func FormatEther(wei *big.Int) string {
+	return FormatUnits(wei, EtherDecimals)
+}
+
+// ParseGwei 将 gwei 数量精确转换为 wei，常用于 gas 价格
+func ParseGwei(s string) (*big.Int, error) {
+	return ParseUnits(s, GweiDecimals)
+}
+
+// FormatGwei 将 wei 精确格式化为 gwei
+func FormatGwei(wei *big.Int) string {
+	return FormatUnits(wei, GweiDecimals)
+}
+
+// splitDecimal 拆分十进制字符串的符号、整数部分和小数部分
+func splitDecimal(s string) (neg bool, whole, frac string, err error) {
+	in := s
+	s = strings.TrimSpace(s)
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, _ = strings.Cut(s, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return false, "", "", fmt.Errorf("无效金额: %s", in)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	return neg, whole, frac, nil
+}
+
+// isDigits 是否只包含 0-9（空串返回 true）
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// roundAway 判断被舍去的非零数字 rest 是否需要让 kept（绝对值）进一位
+func roundAway(mode RoundingMode, kept *big.Int, rest string) (bool, error) {
+	switch mode {
+	case RoundExact:
+		return false, ErrExcessPrecision
+	case RoundDown:
+		return false, nil
+	case RoundUp:
+		return true, nil
+	case RoundHalfUp, RoundHalfEven:
+		switch {
+		case rest[0] > '5':
+			return true, nil
+		case rest[0] < '5':
+			return false, nil
+		case strings.Trim(rest[1:], "0") != "":
+			return true, nil
+		}
+		// 恰好一半
+		return mode == RoundHalfUp || kept.Bit(0) == 1, nil
+	default:
+		return false, fmt.Errorf("未知的舍入方式: %s", mode)
+	}
+}
+
+// formatFixed 将非负整数格式化为恰好 decimals 位小数
+func formatFixed(abs *big.Int, decimals uint8) string {
+	s := abs.String()
+	if decimals == 0 {
+		return s
+	}
+	if len(s) <= int(decimals) {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+	return s[:len(s)-int(decimals)] + "." + s[len(s)-int(decimals):]
+}
+
+// pow10 返回 10^n
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
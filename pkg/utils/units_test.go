@@ -0,0 +1,135 @@
+package utils_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"go-eth-learning/pkg/utils"
+)
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint8
+		want     string
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"0.1", 18, "100000000000000000"},
+		{"1.000000000000000001", 18, "1000000000000000001"},
+		{".5", 18, "500000000000000000"},
+		{"-2.25", 6, "-2250000"},
+		{"1.50", 1, "15"},
+		{"42", 0, "42"},
+		{"0", 18, "0"},
+		{"115792089237316195423570985008687907853269984665640564039457.584007913129639935", 18,
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+	}
+	for _, tt := range tests {
+		got, err := utils.ParseUnits(tt.in, tt.decimals)
+		if err != nil {
+			t.Fatalf("ParseUnits(%s, %d) 失败: %v", tt.in, tt.decimals, err)
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseUnits(%s, %d) = %s, want %s", tt.in, tt.decimals, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", ".", "abc", "1e18", "1,000", "0x10", "1.2.3", "--1"} {
+		if _, err := utils.ParseUnits(in, 18); err == nil {
+			t.Errorf("ParseUnits(%q) 应该报错", in)
+		}
+	}
+	if _, err := utils.ParseEther("0.0000000000000000001"); !errors.Is(err, utils.ErrExcessPrecision) {
+		t.Errorf("超出精度 err = %v, want ErrExcessPrecision", err)
+	}
+}
+
+func TestParseUnitsRound(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode utils.RoundingMode
+		want int64
+	}{
+		{"1.25", utils.RoundDown, 12},
+		{"1.25", utils.RoundUp, 13},
+		{"1.25", utils.RoundHalfUp, 13},
+		{"1.25", utils.RoundHalfEven, 12},
+		{"1.35", utils.RoundHalfEven, 14},
+		{"1.251", utils.RoundHalfEven, 13},
+		{"1.249", utils.RoundHalfUp, 12},
+		{"-1.25", utils.RoundHalfUp, -13},
+		{"-1.21", utils.RoundDown, -12},
+		{"1.20", utils.RoundExact, 12},
+	}
+	for _, tt := range tests {
+		got, err := utils.ParseUnitsRound(tt.in, 1, tt.mode)
+		if err != nil {
+			t.Fatalf("ParseUnitsRound(%s, %s) 失败: %v", tt.in, tt.mode, err)
+		}
+		if got.Int64() != tt.want {
+			t.Errorf("ParseUnitsRound(%s, %s) = %s, want %d", tt.in, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1500000000000000000", 10)
+	if got := utils.FormatEther(wei); got != "1.5" {
+		t.Errorf("FormatEther = %s, want 1.5", got)
+	}
+	if got := utils.FormatEther(big.NewInt(1)); got != "0.000000000000000001" {
+		t.Errorf("FormatEther(1) = %s", got)
+	}
+	if got := utils.FormatUnits(big.NewInt(1234567), 6); got != "1.234567" {
+		t.Errorf("FormatUnits = %s, want 1.234567", got)
+	}
+	if got := utils.FormatUnits(big.NewInt(42), 0); got != "42" {
+		t.Errorf("FormatUnits = %s, want 42", got)
+	}
+	if got := utils.FormatUnits(big.NewInt(-2500), 3); got != "-2.5" {
+		t.Errorf("FormatUnits = %s, want -2.5", got)
+	}
+	if got := utils.FormatGwei(big.NewInt(30_000_000_001)); got != "30.000000001" {
+		t.Errorf("FormatGwei = %s, want 30.000000001", got)
+	}
+
+	tests := []struct {
+		amount int64
+		places uint8
+		mode   utils.RoundingMode
+		want   string
+	}{
+		{1234567, 2, utils.RoundDown, "1.23"},
+		{1234567, 2, utils.RoundHalfUp, "1.23"},
+		{1235000, 2, utils.RoundHalfEven, "1.24"},
+		{1225000, 2, utils.RoundHalfEven, "1.22"},
+		{1230000, 2, utils.RoundExact, "1.23"},
+		{1234567, 8, utils.RoundExact, "1.23456700"},
+		{999999, 0, utils.RoundHalfUp, "1"},
+		{-1234567, 1, utils.RoundUp, "-1.3"},
+		{-1, 2, utils.RoundDown, "0.00"},
+	}
+	for _, tt := range tests {
+		got, err := utils.FormatUnitsRound(big.NewInt(tt.amount), 6, tt.places, tt.mode)
+		if err != nil {
+			t.Fatalf("FormatUnitsRound(%d, %d, %s) 失败: %v", tt.amount, tt.places, tt.mode, err)
+		}
+		if got != tt.want {
+			t.Errorf("FormatUnitsRound(%d, %d, %s) = %s, want %s", tt.amount, tt.places, tt.mode, got, tt.want)
+		}
+	}
+	if _, err := utils.FormatUnitsRound(big.NewInt(1234567), 6, 2, utils.RoundExact); !errors.Is(err, utils.ErrExcessPrecision) {
+		t.Errorf("err = %v, want ErrExcessPrecision", err)
+	}
+}
+
+func TestEtherToWei_Exact(t *testing.T) {
+	// float64 乘 1e18 时 0.1 会在最后几位出错
+	if got := utils.EtherToWei(0.1); got.String() != "100000000000000000" {
+		t.Errorf("EtherToWei(0.1) = %s", got)
+	}
+	if got := utils.EtherToWei(1.1); got.String() != "1100000000000000000" {
+		t.Errorf("EtherToWei(1.1) = %s", got)
+	}
+}
@@ -3,37 +3,31 @@ package utils
 
 import (
 	"math/big"
+	"strconv"
 )
 
 // WeiToEther 将 Wei 转换为 Ether
+//
+// Deprecated: 返回的 *big.Float 只用于近似计算，显示和序列化请使用 FormatEther
 func WeiToEther(wei *big.Int) *big.Float {
-	return new(big.Float).Quo(
-		new(big.Float).SetInt(wei),
-		big.NewFloat(1e18),
-	)
+	ether, _, _ := new(big.Float).SetPrec(256).Parse(FormatEther(wei), 10)
+	return ether
 }
 
-// EtherToWei 将 Ether 转换为 Wei
+// EtherToWei 将 Ether 转换为 Wei，按 float64 的最短十进制表示换算（0.1 得到恰好 10^17 wei）
+//
+// Deprecated: float64 无法表示超过 15~17 位有效数字的金额，请使用 ParseEther
 func EtherToWei(ether float64) *big.Int {
-	eth := big.NewFloat(ether)
-	wei := new(big.Float).Mul(eth, big.NewFloat(1e18))
-	result, _ := wei.Int(nil)
-	return result
+	wei, err := ParseUnitsRound(strconv.FormatFloat(ether, 'f', -1, 64), EtherDecimals, RoundHalfEven)
+	if err != nil {
+		// NaN、Inf 无法换算
+		return new(big.Int)
+	}
+	return wei
 }
 
-// IsValidAddress 验证以太坊地址格式
+// IsValidAddress 验证以太坊地址格式和 EIP-55 校验和，需要地址值时使用 ParseAddress
 func IsValidAddress(address string) bool {
-	if len(address) != 42 {
-		return false
-	}
-	if address[:2] != "0x" {
-		return false
-	}
-	for i := 2; i < 42; i++ {
-		c := address[i]
-		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
-			return false
-		}
-	}
-	return true
+	_, err := ParseAddress(address)
+	return err == nil
 }
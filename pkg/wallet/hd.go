@@ -0,0 +1,173 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultBasePath 以太坊 BIP-44 基础路径，第 i 个地址为 m/44'/60'/0'/0/i
+const DefaultBasePath = "m/44'/60'/0'/0"
+
+// hardenedOffset 强化派生索引起点
+const hardenedOffset = 0x80000000
+
+// ErrInvalidChildKey 派生出的子密钥无效（概率约 2^-127，应换用下一个索引）
+var ErrInvalidChildKey = errors.New("派生的子密钥无效")
+
+// extendedKey BIP-32 扩展私钥
+type extendedKey struct {
+	key       []byte // 32 字节私钥
+	chainCode []byte // 32 字节链码
+}
+
+// HDWallet BIP-32 分层确定性钱包
+type HDWallet struct {
+	master   *extendedKey
+	basePath accounts.DerivationPath
+}
+
+// NewHDWallet 从 BIP-39 种子创建 HD 钱包
+func NewHDWallet(seed []byte) (*HDWallet, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("种子长度必须为 16-64 字节: %d", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(sum[:32])
+	if k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, fmt.Errorf("生成主密钥失败: %w", ErrInvalidChildKey)
+	}
+
+	basePath, _ := accounts.ParseDerivationPath(DefaultBasePath)
+	return &HDWallet{
+		master:   &extendedKey{key: sum[:32], chainCode: sum[32:]},
+		basePath: basePath,
+	}, nil
+}
+
+// NewHDWalletFromMnemonic 从助记词和可选密码创建 HD 钱包
+func NewHDWalletFromMnemonic(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDWallet(seed)
+}
+
+// Derive 按路径派生钱包，例如 "m/44'/60'/0'/0/0"
+func (h *HDWallet) Derive(path string) (*Wallet, error) {
+	parsed, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("解析派生路径失败: %w", err)
+	}
+	return h.DerivePath(parsed)
+}
+
+// DerivePath 按已解析的路径派生钱包
+func (h *HDWallet) DerivePath(path accounts.DerivationPath) (*Wallet, error) {
+	key := h.master
+	for _, index := range path {
+		child, err := key.child(index)
+		if err != nil {
+			return nil, fmt.Errorf("派生 %s 失败: %w", path, err)
+		}
+		key = child
+	}
+
+	privateKey, err := crypto.ToECDSA(key.key)
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %w", err)
+	}
+	return walletFromPrivateKey(privateKey), nil
+}
+
+// DeriveIndex 派生 m/44'/60'/0'/0/index 上的钱包
+func (h *HDWallet) DeriveIndex(index uint32) (*Wallet, error) {
+	return h.DerivePath(h.PathAt(index))
+}
+
+// DeriveRange 批量派生从 start 开始的 count 个钱包，索引必须都是非强化索引（小于 2^31）
+func (h *HDWallet) DeriveRange(start, count uint32) ([]*Wallet, error) {
+	// 超出范围时 start+count 会溢出 uint32，或派生出强化路径的地址
+	if uint64(start)+uint64(count) > hardenedOffset {
+		return nil, fmt.Errorf("派生范围 %d+%d 超出非强化索引上限 %d", start, count, uint32(hardenedOffset))
+	}
+
+	// 基础路径只需派生一次
+	base := h.master
+	for _, index := range h.basePath {
+		child, err := base.child(index)
+		if err != nil {
+			return nil, fmt.Errorf("派生 %s 失败: %w", h.basePath, err)
+		}
+		base = child
+	}
+
+	wallets := make([]*Wallet, 0, count)
+	for i := start; i < start+count; i++ {
+		child, err := base.child(i)
+		if err != nil {
+			return nil, fmt.Errorf("派生 %s 失败: %w", h.PathAt(i), err)
+		}
+		privateKey, err := crypto.ToECDSA(child.key)
+		if err != nil {
+			return nil, fmt.Errorf("解析私钥失败: %w", err)
+		}
+		wallets = append(wallets, walletFromPrivateKey(privateKey))
+	}
+	return wallets, nil
+}
+
+// PathAt 返回基础路径下第 index 个地址的完整路径
+func (h *HDWallet) PathAt(index uint32) accounts.DerivationPath {
+	path := make(accounts.DerivationPath, len(h.basePath), len(h.basePath)+1)
+	copy(path, h.basePath)
+	return append(path, index)
+}
+
+// child 按 BIP-32 派生子私钥，index >= 2^31 为强化派生
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0x00)
+		data = append(data, k.key...)
+	} else {
+		privateKey, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&privateKey.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	il := new(big.Int).SetBytes(sum[:32])
+	if il.Cmp(n) >= 0 {
+		return nil, ErrInvalidChildKey
+	}
+	childKey := il.Add(il, new(big.Int).SetBytes(k.key))
+	childKey.Mod(childKey, n)
+	if childKey.Sign() == 0 {
+		return nil, ErrInvalidChildKey
+	}
+
+	return &extendedKey{
+		key:       math.PaddedBigBytes(childKey, 32),
+		chainCode: sum[32:],
+	}, nil
+}
@@ -0,0 +1,125 @@
+package wallet_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	"go-eth-learning/pkg/wallet"
+)
+
+func TestMnemonicToSeed(t *testing.T) {
+	// BIP-39 官方测试向量
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+
+	seed, err := wallet.MnemonicToSeed(mnemonic, "TREZOR")
+	if err != nil {
+		t.Fatalf("派生种子失败: %v", err)
+	}
+	if hex.EncodeToString(seed) != want {
+		t.Errorf("种子 = %x, want %s", seed, want)
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	languages := []wallet.Language{wallet.English, wallet.ChineseSimplified, wallet.Japanese, wallet.Korean}
+
+	for _, lang := range languages {
+		mnemonic, err := wallet.NewMnemonic(256, lang)
+		if err != nil {
+			t.Fatalf("%s: 生成助记词失败: %v", lang, err)
+		}
+		if err := wallet.ValidateMnemonic(mnemonic); err != nil {
+			t.Errorf("%s: 校验失败: %v", lang, err)
+		}
+	}
+
+	if _, err := wallet.NewMnemonic(100, wallet.English); err == nil {
+		t.Error("无效熵长度应该返回错误")
+	}
+}
+
+func TestValidateMnemonic_Invalid(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"abandon abandon abandon", wallet.ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon notaword", wallet.ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", wallet.ErrMnemonicChecksum},
+	}
+
+	for _, tt := range tests {
+		if err := wallet.ValidateMnemonic(tt.mnemonic); !errors.Is(err, tt.err) {
+			t.Errorf("ValidateMnemonic(%q) = %v, want %v", tt.mnemonic, err, tt.err)
+		}
+	}
+}
+
+func TestHDWallet_BIP32Vector(t *testing.T) {
+	// BIP-32 测试向量 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	hd, err := wallet.NewHDWallet(seed)
+	if err != nil {
+		t.Fatalf("创建 HD 钱包失败: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+
+	for _, tt := range tests {
+		w, err := hd.Derive(tt.path)
+		if err != nil {
+			t.Fatalf("派生 %s 失败: %v", tt.path, err)
+		}
+		if w.GetPrivateKeyHex() != tt.key {
+			t.Errorf("%s 私钥 = %s, want %s", tt.path, w.GetPrivateKeyHex(), tt.key)
+		}
+	}
+}
+
+func TestHDWallet_DeriveRange(t *testing.T) {
+	// Hardhat / Anvil 默认助记词
+	mnemonic := "test test test test test test test test test test test junk"
+	hd, err := wallet.NewHDWalletFromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatalf("创建 HD 钱包失败: %v", err)
+	}
+
+	wallets, err := hd.DeriveRange(0, 2)
+	if err != nil {
+		t.Fatalf("批量派生失败: %v", err)
+	}
+
+	want := []string{
+		"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+	}
+	for i, w := range wallets {
+		if w.GetAddressHex() != want[i] {
+			t.Errorf("地址 %d = %s, want %s", i, w.GetAddressHex(), want[i])
+		}
+	}
+
+	single, _ := hd.DeriveIndex(1)
+	if single.Address != wallets[1].Address {
+		t.Error("DeriveIndex 与 DeriveRange 结果不一致")
+	}
+
+	// 溢出 uint32 或进入强化索引的范围直接拒绝，不会派生任何地址
+	for _, r := range [][2]uint32{{math.MaxUint32, 2}, {1<<31 - 1, 2}, {1 << 31, 1}} {
+		if _, err := hd.DeriveRange(r[0], r[1]); err == nil {
+			t.Errorf("DeriveRange(%d, %d) 应返回错误", r[0], r[1])
+		}
+	}
+	if last, err := hd.DeriveRange(1<<31-1, 1); err != nil || len(last) != 1 {
+		t.Errorf("DeriveRange(2^31-1, 1) = %d 个, %v", len(last), err)
+	}
+}
@@ -0,0 +1,393 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+
+	"go-eth-learning/pkg/utils"
+)
+
+// KDF keystore 使用的密钥派生函数
+type KDF string
+
+const (
+	// KDFScrypt scrypt 派生（geth 默认）
+	KDFScrypt KDF = "scrypt"
+	// KDFPBKDF2 pbkdf2-hmac-sha256 派生
+	KDFPBKDF2 KDF = "pbkdf2"
+)
+
+// 默认 pbkdf2 迭代次数，与 MetaMask / ethers.js 保持一致
+const defaultPBKDF2Iterations = 262144
+
+// ErrAccountNotFound keystore 目录中不存在该账户
+var ErrAccountNotFound = errors.New("账户不存在")
+
+// KeystoreOptions keystore 加密参数
+type KeystoreOptions struct {
+	KDF KDF
+
+	// scrypt 参数
+	ScryptN int
+	ScryptP int
+
+	// pbkdf2 参数
+	PBKDF2Iterations int
+}
+
+// StandardKeystoreOptions 标准强度参数（与 geth 默认一致）
+func StandardKeystoreOptions() *KeystoreOptions {
+	return &KeystoreOptions{
+		KDF:     KDFScrypt,
+		ScryptN: keystore.StandardScryptN,
+		ScryptP: keystore.StandardScryptP,
+	}
+}
+
+// LightKeystoreOptions 轻量参数，适合测试或低性能设备
+func LightKeystoreOptions() *KeystoreOptions {
+	return &KeystoreOptions{
+		KDF:     KDFScrypt,
+		ScryptN: keystore.LightScryptN,
+		ScryptP: keystore.LightScryptP,
+	}
+}
+
+// encryptedKeyJSON Web3 Secret Storage v3 文件结构
+type encryptedKeyJSON struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	ID      string              `json:"id"`
+	Version int                 `json:"version"`
+}
+
+// EncryptKeystore 将钱包加密为 Web3 Secret Storage v3 JSON
+func (w *Wallet) EncryptKeystore(password string, opts *KeystoreOptions) ([]byte, error) {
+	if opts == nil {
+		opts = StandardKeystoreOptions()
+	}
+
+	keyBytes := math.PaddedBigBytes(w.PrivateKey.D, 32)
+
+	var (
+		cryptoJSON keystore.CryptoJSON
+		err        error
+	)
+	switch opts.KDF {
+	case KDFScrypt, "":
+		cryptoJSON, err = keystore.EncryptDataV3(keyBytes, []byte(password), opts.ScryptN, opts.ScryptP)
+	case KDFPBKDF2:
+		cryptoJSON, err = encryptDataPBKDF2(keyBytes, []byte(password), opts.PBKDF2Iterations)
+	default:
+		return nil, fmt.Errorf("不支持的 KDF: %s", opts.KDF)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("加密私钥失败: %w", err)
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("生成 keystore ID 失败: %w", err)
+	}
+
+	return json.Marshal(encryptedKeyJSON{
+		Address: hex.EncodeToString(w.Address[:]),
+		Crypto:  cryptoJSON,
+		ID:      id.String(),
+		Version: 3,
+	})
+}
+
+// encryptDataPBKDF2 使用 pbkdf2 派生密钥并以 aes-128-ctr 加密
+func encryptDataPBKDF2(data, auth []byte, iterations int) (keystore.CryptoJSON, error) {
+	if iterations <= 0 {
+		iterations = defaultPBKDF2Iterations
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return keystore.CryptoJSON{}, err
+	}
+	derivedKey := pbkdf2.Key(auth, salt, iterations, 32, sha256.New)
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return keystore.CryptoJSON{}, err
+	}
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return keystore.CryptoJSON{}, err
+	}
+	cipherText := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(cipherText, data)
+
+	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
+
+	return keystore.CryptoJSON{
+		Cipher:     "aes-128-ctr",
+		CipherText: hex.EncodeToString(cipherText),
+		CipherParams: struct {
+			IV string `json:"iv"`
+		}{IV: hex.EncodeToString(iv)},
+		KDF: string(KDFPBKDF2),
+		KDFParams: map[string]interface{}{
+			"c":     iterations,
+			"dklen": 32,
+			"prf":   "hmac-sha256",
+			"salt":  hex.EncodeToString(salt),
+		},
+		MAC: hex.EncodeToString(mac),
+	}, nil
+}
+
+// ErrKeystoreAddressMismatch keystore 记录的地址与解密出的私钥不一致，文件可能被篡改或拼接错误
+var ErrKeystoreAddressMismatch = errors.New("keystore 地址与私钥不一致")
+
+// FromKeystore 解密 keystore JSON 加载钱包（支持 scrypt 和 pbkdf2）
+//
+// keystore 中带 address 字段时会校验其格式，并核对与私钥推导出的地址一致
+func FromKeystore(keyJSON []byte, password string) (*Wallet, error) {
+	recorded, err := keystoreAddress(keyJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("解密 keystore 失败: %w", err)
+	}
+
+	w := walletFromPrivateKey(key.PrivateKey)
+	if recorded != nil && *recorded != w.Address {
+		return nil, fmt.Errorf("%w: 记录 %s, 实际 %s", ErrKeystoreAddressMismatch, recorded.Hex(), w.Address.Hex())
+	}
+	return w, nil
+}
+
+// keystoreAddress 解析 keystore JSON 的 address 字段，字段不存在时返回 nil
+func keystoreAddress(keyJSON []byte) (*common.Address, error) {
+	var meta struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keyJSON, &meta); err != nil {
+		return nil, fmt.Errorf("解析 keystore 失败: %w", err)
+	}
+	if meta.Address == "" {
+		return nil, nil
+	}
+
+	s := meta.Address
+	if len(s) == 2*common.AddressLength {
+		s = "0x" + s
+	}
+	addr, err := utils.ParseAddress(s)
+	if err != nil {
+		return nil, fmt.Errorf("keystore 地址字段: %w", err)
+	}
+	return &addr, nil
+}
+
+// SaveKeystore 加密钱包并写入文件
+func (w *Wallet) SaveKeystore(path, password string, opts *KeystoreOptions) error {
+	keyJSON, err := w.EncryptKeystore(password, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(path, keyJSON, 0600); err != nil {
+		return fmt.Errorf("写入 keystore 文件失败: %w", err)
+	}
+	return nil
+}
+
+// LoadKeystore 从 keystore 文件加载钱包
+func LoadKeystore(path, password string) (*Wallet, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 keystore 文件失败: %w", err)
+	}
+
+	return FromKeystore(keyJSON, password)
+}
+
+// KeyStore 基于目录的 keystore，文件格式与 geth 的 keystore 目录兼容
+type KeyStore struct {
+	dir string
+	ks  *keystore.KeyStore
+}
+
+// NewKeyStore 打开（或创建）keystore 目录
+func NewKeyStore(dir string, opts *KeystoreOptions) (*KeyStore, error) {
+	if opts == nil {
+		opts = StandardKeystoreOptions()
+	}
+	if opts.KDF != KDFScrypt && opts.KDF != "" {
+		return nil, fmt.Errorf("keystore 目录仅支持 scrypt, 当前: %s", opts.KDF)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("创建 keystore 目录失败: %w", err)
+	}
+
+	return &KeyStore{
+		dir: dir,
+		ks:  keystore.NewKeyStore(dir, opts.ScryptN, opts.ScryptP),
+	}, nil
+}
+
+// Dir 返回 keystore 目录
+func (k *KeyStore) Dir() string {
+	return k.dir
+}
+
+// Accounts 列出目录中的全部账户地址
+func (k *KeyStore) Accounts() []common.Address {
+	accs := k.ks.Accounts()
+	addrs := make([]common.Address, len(accs))
+	for i, acc := range accs {
+		addrs[i] = acc.Address
+	}
+	return addrs
+}
+
+// HasAccount 判断目录中是否存在该账户
+func (k *KeyStore) HasAccount(address common.Address) bool {
+	return k.ks.HasAddress(address)
+}
+
+// NewAccount 生成新账户并加密保存
+func (k *KeyStore) NewAccount(password string) (common.Address, error) {
+	acc, err := k.ks.NewAccount(password)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("创建账户失败: %w", err)
+	}
+	return acc.Address, nil
+}
+
+// ImportWallet 将已有钱包加密保存到目录
+func (k *KeyStore) ImportWallet(w *Wallet, password string) (common.Address, error) {
+	acc, err := k.ks.ImportECDSA(w.PrivateKey, password)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("导入钱包失败: %w", err)
+	}
+	return acc.Address, nil
+}
+
+// Import 导入 keystore JSON，并以新密码重新加密保存
+//
+// keystore 中带 address 字段时核对与私钥一致，不一致时不保留导入的文件
+func (k *KeyStore) Import(keyJSON []byte, password, newPassword string) (common.Address, error) {
+	recorded, err := keystoreAddress(keyJSON)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	acc, err := k.ks.Import(keyJSON, password, newPassword)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("导入 keystore 失败: %w", err)
+	}
+	if recorded != nil && *recorded != acc.Address {
+		_ = k.ks.Delete(acc, newPassword)
+		return common.Address{}, fmt.Errorf("%w: 记录 %s, 实际 %s", ErrKeystoreAddressMismatch, recorded.Hex(), acc.Address.Hex())
+	}
+	return acc.Address, nil
+}
+
+// Export 导出账户的 keystore JSON，并以新密码重新加密
+func (k *KeyStore) Export(address common.Address, password, newPassword string) ([]byte, error) {
+	acc, err := k.find(address)
+	if err != nil {
+		return nil, err
+	}
+
+	keyJSON, err := k.ks.Export(acc, password, newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("导出 keystore 失败: %w", err)
+	}
+	return keyJSON, nil
+}
+
+// Delete 验证密码后删除账户文件
+func (k *KeyStore) Delete(address common.Address, password string) error {
+	acc, err := k.find(address)
+	if err != nil {
+		return err
+	}
+
+	if err := k.ks.Delete(acc, password); err != nil {
+		return fmt.Errorf("删除账户失败: %w", err)
+	}
+	return nil
+}
+
+// Wallet 解密账户并返回钱包
+func (k *KeyStore) Wallet(address common.Address, password string) (*Wallet, error) {
+	acc, err := k.find(address)
+	if err != nil {
+		return nil, err
+	}
+
+	keyJSON, err := os.ReadFile(acc.URL.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 keystore 文件失败: %w", err)
+	}
+	return FromKeystore(keyJSON, password)
+}
+
+// SignTx 使用账户密码签名交易，私钥不会离开 keystore
+func (k *KeyStore) SignTx(address common.Address, password string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	acc, err := k.find(address)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := k.ks.SignTxWithPassphrase(acc, password, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("签名交易失败: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignHash 使用账户密码签名 32 字节哈希，返回的 V 为 0/1
+func (k *KeyStore) SignHash(address common.Address, password string, hash []byte) ([]byte, error) {
+	acc, err := k.find(address)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := k.ks.SignHashWithPassphrase(acc, password, hash)
+	if err != nil {
+		return nil, fmt.Errorf("签名失败: %w", err)
+	}
+	return signature, nil
+}
+
+// find 按地址查找账户
+func (k *KeyStore) find(address common.Address) (accounts.Account, error) {
+	acc, err := k.ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("%w: %s", ErrAccountNotFound, address.Hex())
+	}
+	return acc, nil
+}
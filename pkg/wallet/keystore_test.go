@@ -0,0 +1,158 @@
+package wallet_test
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+
+	"go-eth-learning/pkg/utils"
+	"go-eth-learning/pkg/wallet"
+)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	w, _ := wallet.NewWallet()
+
+	tests := []struct {
+		name string
+		opts *wallet.KeystoreOptions
+	}{
+		{"scrypt", wallet.LightKeystoreOptions()},
+		{"pbkdf2", &wallet.KeystoreOptions{KDF: wallet.KDFPBKDF2, PBKDF2Iterations: 1024}},
+	}
+
+	for _, tt := range tests {
+		keyJSON, err := w.EncryptKeystore("secret", tt.opts)
+		if err != nil {
+			t.Fatalf("%s: 加密失败: %v", tt.name, err)
+		}
+
+		// geth 的解密实现应该能读取
+		key, err := keystore.DecryptKey(keyJSON, "secret")
+		if err != nil {
+			t.Fatalf("%s: geth 解密失败: %v", tt.name, err)
+		}
+		if key.Address != w.Address {
+			t.Errorf("%s: 地址不匹配", tt.name)
+		}
+
+		if _, err := wallet.FromKeystore(keyJSON, "wrong"); err == nil {
+			t.Errorf("%s: 错误密码应该返回错误", tt.name)
+		}
+	}
+}
+
+func TestSaveAndLoadKeystore(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	path := filepath.Join(t.TempDir(), "key.json")
+
+	if err := w.SaveKeystore(path, "secret", wallet.LightKeystoreOptions()); err != nil {
+		t.Fatalf("保存失败: %v", err)
+	}
+
+	loaded, err := wallet.LoadKeystore(path, "secret")
+	if err != nil {
+		t.Fatalf("加载失败: %v", err)
+	}
+	if loaded.GetPrivateKeyHex() != w.GetPrivateKeyHex() {
+		t.Error("私钥不匹配")
+	}
+}
+
+func TestKeyStoreDir(t *testing.T) {
+	ks, err := wallet.NewKeyStore(t.TempDir(), wallet.LightKeystoreOptions())
+	if err != nil {
+		t.Fatalf("打开 keystore 失败: %v", err)
+	}
+
+	addr, err := ks.NewAccount("secret")
+	if err != nil {
+		t.Fatalf("创建账户失败: %v", err)
+	}
+
+	w, _ := wallet.NewWallet()
+	if _, err := ks.ImportWallet(w, "secret"); err != nil {
+		t.Fatalf("导入钱包失败: %v", err)
+	}
+
+	if len(ks.Accounts()) != 2 {
+		t.Fatalf("账户数量 = %d, want 2", len(ks.Accounts()))
+	}
+
+	// 导出后用新密码解密
+	keyJSON, err := ks.Export(w.Address, "secret", "new-secret")
+	if err != nil {
+		t.Fatalf("导出失败: %v", err)
+	}
+	exported, err := wallet.FromKeystore(keyJSON, "new-secret")
+	if err != nil {
+		t.Fatalf("解密导出文件失败: %v", err)
+	}
+	if exported.Address != w.Address {
+		t.Error("导出的地址不匹配")
+	}
+
+	unlocked, err := ks.Wallet(addr, "secret")
+	if err != nil {
+		t.Fatalf("解锁账户失败: %v", err)
+	}
+	if unlocked.Address != addr {
+		t.Error("解锁的地址不匹配")
+	}
+
+	if err := ks.Delete(addr, "wrong"); err == nil {
+		t.Error("错误密码应该无法删除")
+	}
+	if err := ks.Delete(addr, "secret"); err != nil {
+		t.Fatalf("删除失败: %v", err)
+	}
+	if ks.HasAccount(addr) {
+		t.Error("账户应该已删除")
+	}
+
+	if _, err := ks.Wallet(addr, "secret"); !errors.Is(err, wallet.ErrAccountNotFound) {
+		t.Errorf("期望 ErrAccountNotFound, 得到 %v", err)
+	}
+}
+
+func TestFromKeystore_AddressMismatch(t *testing.T) {
+	w, _ := wallet.NewWallet()
+	other, _ := wallet.NewWallet()
+
+	keyJSON, err := w.EncryptKeystore("secret", wallet.LightKeystoreOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(keyJSON, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	// 篡改 address 字段
+	doc["address"] = strings.TrimPrefix(strings.ToLower(other.Address.Hex()), "0x")
+	tampered, _ := json.Marshal(doc)
+	if _, err := wallet.FromKeystore(tampered, "secret"); !errors.Is(err, wallet.ErrKeystoreAddressMismatch) {
+		t.Errorf("err = %v, want ErrKeystoreAddressMismatch", err)
+	}
+
+	ks, err := wallet.NewKeyStore(t.TempDir(), wallet.LightKeystoreOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Import(tampered, "secret", "secret"); !errors.Is(err, wallet.ErrKeystoreAddressMismatch) {
+		t.Errorf("Import err = %v, want ErrKeystoreAddressMismatch", err)
+	}
+	if n := len(ks.Accounts()); n != 0 {
+		t.Errorf("地址不一致时不应保留导入的账户, 当前 %d 个", n)
+	}
+
+	// 大小写混合但校验和错误的地址字段
+	doc["address"] = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+	bad, _ := json.Marshal(doc)
+	if _, err := wallet.FromKeystore(bad, "secret"); !errors.Is(err, utils.ErrBadChecksum) {
+		t.Errorf("err = %v, want ErrBadChecksum", err)
+	}
+}
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Language 助记词语言
+type Language string
+
+// BIP-39 支持的词表
+const (
+	English            Language = "english"
+	ChineseSimplified  Language = "chinese_simplified"
+	ChineseTraditional Language = "chinese_traditional"
+	Czech              Language = "czech"
+	French             Language = "french"
+	Italian            Language = "italian"
+	Japanese           Language = "japanese"
+	Korean             Language = "korean"
+	Spanish            Language = "spanish"
+)
+
+var (
+	// ErrInvalidMnemonic 助记词格式错误（长度或单词不在词表中）
+	ErrInvalidMnemonic = errors.New("无效的助记词")
+	// ErrMnemonicChecksum 助记词校验和不匹配
+	ErrMnemonicChecksum = errors.New("助记词校验和错误")
+)
+
+// languages 按检测优先级排列
+var languages = []Language{
+	English, ChineseSimplified, ChineseTraditional, Czech,
+	French, Italian, Japanese, Korean, Spanish,
+}
+
+var wordlistByLanguage = map[Language][]string{
+	English:            wordlists.English,
+	ChineseSimplified:  wordlists.ChineseSimplified,
+	ChineseTraditional: wordlists.ChineseTraditional,
+	Czech:              wordlists.Czech,
+	French:             wordlists.French,
+	Italian:            wordlists.Italian,
+	Japanese:           wordlists.Japanese,
+	Korean:             wordlists.Korean,
+	Spanish:            wordlists.Spanish,
+}
+
+// wordIndex 词表反向索引，单词统一为 NFKD 形式
+var wordIndex = func() map[Language]map[string]int {
+	index := make(map[Language]map[string]int, len(wordlistByLanguage))
+	for lang, words := range wordlistByLanguage {
+		m := make(map[string]int, len(words))
+		for i, w := range words {
+			m[norm.NFKD.String(w)] = i
+		}
+		index[lang] = m
+	}
+	return index
+}()
+
+// Wordlist 返回指定语言的词表
+func Wordlist(lang Language) ([]string, error) {
+	words, ok := wordlistByLanguage[lang]
+	if !ok {
+		return nil, fmt.Errorf("不支持的助记词语言: %s", lang)
+	}
+	return words, nil
+}
+
+// NewMnemonic 生成助记词，bits 为熵长度（128/160/192/224/256，对应 12-24 个单词）
+func NewMnemonic(bits int, lang Language) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("熵长度必须为 128-256 之间 32 的倍数: %d", bits)
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("生成随机熵失败: %w", err)
+	}
+
+	return MnemonicFromEntropy(entropy, lang)
+}
+
+// MnemonicFromEntropy 按 BIP-39 将熵编码为助记词
+func MnemonicFromEntropy(entropy []byte, lang Language) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("熵长度必须为 128-256 之间 32 的倍数: %d", bits)
+	}
+	words, err := Wordlist(lang)
+	if err != nil {
+		return "", err
+	}
+
+	// 熵 + 校验和（SHA256 前 bits/32 位），每 11 位对应一个单词
+	checksumBits := bits / 32
+	hash := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	count := (bits + checksumBits) / 11
+	result := make([]string, count)
+	mask := big.NewInt(2047)
+	for i := count - 1; i >= 0; i-- {
+		result[i] = words[new(big.Int).And(data, mask).Int64()]
+		data.Rsh(data, 11)
+	}
+
+	return strings.Join(result, separator(lang)), nil
+}
+
+// separator 日语助记词使用全角空格分隔
+func separator(lang Language) string {
+	if lang == Japanese {
+		return "　"
+	}
+	return " "
+}
+
+// DetectLanguage 根据单词判断助记词语言
+func DetectLanguage(mnemonic string) (Language, error) {
+	candidates := candidateLanguages(splitMnemonic(mnemonic))
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: 单词不在任何词表中", ErrInvalidMnemonic)
+	}
+	return candidates[0], nil
+}
+
+// candidateLanguages 返回包含全部单词的词表（简繁中文存在重叠）
+func candidateLanguages(words []string) []Language {
+	if len(words) == 0 {
+		return nil
+	}
+
+	var result []Language
+	for _, lang := range languages {
+		index := wordIndex[lang]
+		matched := true
+		for _, w := range words {
+			if _, ok := index[w]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, lang)
+		}
+	}
+	return result
+}
+
+// ValidateMnemonic 校验助记词长度、单词和校验和
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToEntropy 解码助记词并返回原始熵
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := splitMnemonic(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("%w: 单词数量 %d", ErrInvalidMnemonic, len(words))
+	}
+
+	candidates := candidateLanguages(words)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: 单词不在任何词表中", ErrInvalidMnemonic)
+	}
+
+	for _, lang := range candidates {
+		if entropy, ok := decodeEntropy(words, wordIndex[lang]); ok {
+			return entropy, nil
+		}
+	}
+	return nil, ErrMnemonicChecksum
+}
+
+// decodeEntropy 按词表解码并校验校验和
+func decodeEntropy(words []string, index map[string]int) ([]byte, bool) {
+	data := new(big.Int)
+	for _, w := range words {
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index[w])))
+	}
+
+	totalBits := len(words) * 11
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+
+	checksum := new(big.Int).And(data, big.NewInt(int64(1<<checksumBits-1)))
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := make([]byte, entropyBits/8)
+	data.FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	return entropy, int64(hash[0]>>(8-checksumBits)) == checksum.Int64()
+}
+
+// MnemonicToSeed 按 BIP-39 由助记词和可选密码派生 64 字节种子
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := strings.Join(splitMnemonic(mnemonic), " ")
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(normalized), []byte(salt), 2048, 64, sha512.New), nil
+}
+
+// splitMnemonic NFKD 规范化后按空白（含全角空格）拆分
+func splitMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(mnemonic))
+}